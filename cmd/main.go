@@ -2,20 +2,29 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/audit"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/file"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
 	bigservice "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/services/biginterface"
 	smallservice "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/services/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
 )
 
 // InMemoryStoreの実装をすべて削除
 
 func main() {
-	ctx := context.Background()
+	auditPath := flag.String("audit-log", "", "append the audit log of the small interface approach to this JSON lines file instead of keeping it in memory")
+	flag.Parse()
+
+	// The demo acts on behalf of the process itself, and its mutations are attributed to this actor in the audit log
+	ctx := audit.WithActor(auth.WithSystem(context.Background()), "admin")
 
 	// Create a common data store
 	store := inmemory.NewStore()
@@ -50,8 +59,10 @@ func main() {
 
 	fmt.Println("===== Big Interface Approach =====")
 	// Big interface approach
-	bigUserService := bigservice.NewUserService(store)
-	bigTodoService := bigservice.NewTodoService(store)
+	// Wrapping the whole DataStore records every mutation in its audit log
+	bigStore := audit.NewDataStore(store)
+	bigUserService := bigservice.NewUserService(bigStore)
+	bigTodoService := bigservice.NewTodoService(bigStore)
 
 	// Get user information
 	fetchedUser, err := bigUserService.GetUser(ctx, "user1")
@@ -77,8 +88,19 @@ func main() {
 
 	fmt.Println("\n===== Small Interface Approach =====")
 	// Small interface approach
-	smallUserService := smallservice.NewUserService(store)
-	smallTodoService := smallservice.NewTodoService(store, store)
+	// The audit log is a store of its own, so it can live outside the data store
+	var auditLog smallinterface.AuditStore = store
+	if *auditPath != "" {
+		fileLog, err := file.NewAuditStore(*auditPath)
+		if err != nil {
+			log.Fatalf("Audit log error: %v", err)
+		}
+		auditLog = fileLog
+	}
+	smallUserService := smallservice.NewUserService(store, store)
+	// Only the stores whose mutations should be audited are wrapped
+	smallTodoService := smallservice.NewTodoService(audit.NewTodoStore(store, auditLog, store), store, store, store, store, audit.NewTxRunner(store, auditLog))
+	smallAuditService := smallservice.NewAuditService(auditLog)
 
 	// Get user information
 	fetchedUser, err = smallUserService.GetUser(ctx, "user1")
@@ -116,4 +138,13 @@ func main() {
 		}
 		fmt.Printf("- %s: %s (%s)\n", t.Title, t.Description, status)
 	}
+
+	fmt.Println("\n===== Audit Log =====")
+	entries, err := smallAuditService.GetEntityHistory(ctx, domain.AuditEntityTodo, "todo1")
+	if err != nil {
+		log.Fatalf("Audit log retrieval error: %v", err)
+	}
+	for _, e := range entries {
+		fmt.Printf("- %s %s %s by %s\n", e.At.Format(time.RFC3339), e.Action, e.EntityID, e.Actor)
+	}
 }
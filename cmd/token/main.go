@@ -0,0 +1,189 @@
+// Command token issues and revokes the API tokens the HTTP API authenticates with
+//
+// The store file holds a snapshot of an in-memory store, as for the transfer command
+//
+//	token issue -store data.json -user user1 -role user -ttl 720h
+//	token list -store data.json -user user1
+//	token revoke -store data.json -id 0123456789abcdef0123456789abcdef
+//
+// Tokens belong to the tenant given with -tenant and only authenticate requests to it
+//
+//	token issue -store data.json -tenant acme -user user1
+//
+// issue prints the token to stdout; it cannot be shown again, as only the hash of its secret is stored
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal("usage: token issue|list|revoke [flags]")
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "issue":
+		err = runIssue(os.Args[2:])
+	case "list":
+		err = runList(os.Args[2:])
+	case "revoke":
+		err = runRevoke(os.Args[2:])
+	default:
+		err = fmt.Errorf("unknown command %q, want issue, list or revoke", os.Args[1])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runIssue(args []string) error {
+	flags := flag.NewFlagSet("issue", flag.ExitOnError)
+	storePath := flags.String("store", "data.json", "store file")
+	tenantID := flags.String("tenant", tenant.Default, "tenant the token belongs to")
+	userID := flags.String("user", "", "user the token authenticates as (required)")
+	role := flags.String("role", string(domain.RoleUser), "role of the token: user or admin")
+	ttl := flags.Duration("ttl", 0, "how long the token is valid (default forever)")
+	flags.Parse(args)
+
+	if *userID == "" {
+		return errors.New("-user is required")
+	}
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	store, err := loadStore(*storePath)
+	if err != nil {
+		return err
+	}
+	raw, token, err := auth.NewTokenService(store, store).IssueToken(ctx, *userID, domain.Role(*role), *ttl)
+	if err != nil {
+		return err
+	}
+	if err := saveStore(store, *storePath); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Issued token %s\n", token.ID)
+	fmt.Println(raw)
+	return nil
+}
+
+func runList(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	storePath := flags.String("store", "data.json", "store file")
+	tenantID := flags.String("tenant", tenant.Default, "tenant the token belongs to")
+	userID := flags.String("user", "", "user whose tokens are listed (required)")
+	flags.Parse(args)
+
+	if *userID == "" {
+		return errors.New("-user is required")
+	}
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	store, err := loadStore(*storePath)
+	if err != nil {
+		return err
+	}
+	tokens, err := auth.NewTokenService(store, store).GetUserTokens(ctx, *userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, token := range tokens {
+		status := "active"
+		switch {
+		case token.IsRevoked():
+			status = "revoked"
+		case token.IsExpired(now):
+			status = "expired"
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", token.ID, token.Role, token.CreatedAt.Format(time.RFC3339), status)
+	}
+	return nil
+}
+
+func runRevoke(args []string) error {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	storePath := flags.String("store", "data.json", "store file")
+	tenantID := flags.String("tenant", tenant.Default, "tenant the token belongs to")
+	id := flags.String("id", "", "ID of the token to revoke (required)")
+	flags.Parse(args)
+
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	store, err := loadStore(*storePath)
+	if err != nil {
+		return err
+	}
+	if err := auth.NewTokenService(store, store).RevokeToken(ctx, *id); err != nil {
+		return err
+	}
+	return saveStore(store, *storePath)
+}
+
+// tenantContext returns a context acting on behalf of the process itself on the tenant with the given ID
+func tenantContext(id string) (context.Context, error) {
+	if err := tenant.Validate(id); err != nil {
+		return nil, err
+	}
+	return auth.WithSystem(tenant.WithID(context.Background(), id)), nil
+}
+
+// loadStore restores an in-memory store from the snapshot in the store file
+func loadStore(path string) (*inmemory.Store, error) {
+	store := inmemory.NewStore()
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := store.Restore(f); err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// saveStore replaces the store file with a snapshot of the store,
+// writing through a temporary file so that a failed write leaves the old file intact
+func saveStore(store *inmemory.Store, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := store.Snapshot(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
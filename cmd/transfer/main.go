@@ -0,0 +1,233 @@
+// Command transfer exports users and Todos from a store file and imports them into it
+//
+// The store file holds a snapshot of an in-memory store; a missing file is an empty store
+//
+//	transfer export -store data.json -format csv -user user1 -out user1.csv
+//	transfer import -store data.json -format ndjson -in todos.ndjson -duplicates skip -dry-run
+//
+// The ical format holds the Todos of the user given with -user, which an import creates or updates by UID
+//
+//	transfer export -store data.json -format ical -user user1 -out user1.ics
+//	transfer import -store data.json -format ical -user user1 -in user1.ics
+//
+// Both act on the data of the tenant given with -tenant, the default tenant unless set
+//
+//	transfer export -store data.json -tenant acme -out acme.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/transfer"
+)
+
+// icalFormat names the iCalendar format, which holds only one user's Todos
+const icalFormat = "ical"
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal("usage: transfer export|import [flags]")
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	default:
+		err = fmt.Errorf("unknown command %q, want export or import", os.Args[1])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	storePath := flags.String("store", "data.json", "store file")
+	tenantID := flags.String("tenant", tenant.Default, "tenant whose data is transferred")
+	formatName := flags.String("format", "json", "output format: json, csv, ndjson or ical")
+	userID := flags.String("user", "", "only export this user (required for ical)")
+	out := flags.String("out", "", "output file (default stdout)")
+	flags.Parse(args)
+
+	encode := func(w io.Writer, data *transfer.Dataset) error {
+		return transfer.EncodeICal(w, data.Todos)
+	}
+	if *formatName == icalFormat {
+		if *userID == "" {
+			return errors.New("-user is required for the ical format")
+		}
+	} else {
+		format, err := transfer.ParseFormat(*formatName)
+		if err != nil {
+			return err
+		}
+		encode = func(w io.Writer, data *transfer.Dataset) error {
+			return transfer.Encode(w, format, data)
+		}
+	}
+
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	store, err := loadStore(*storePath)
+	if err != nil {
+		return err
+	}
+	data, err := transfer.NewExporter(store, store).Export(ctx, *userID)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return encode(os.Stdout, data)
+	}
+	return writeFile(*out, func(w io.Writer) error {
+		return encode(w, data)
+	})
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	storePath := flags.String("store", "data.json", "store file")
+	tenantID := flags.String("tenant", tenant.Default, "tenant whose data is transferred")
+	formatName := flags.String("format", "json", "input format: json, csv, ndjson or ical")
+	userID := flags.String("user", "", "owner of the imported todos (ical only, required)")
+	in := flags.String("in", "", "input file (default stdin)")
+	duplicates := flags.String("duplicates", "fail", "what to do with IDs that already exist: fail, skip or overwrite (ignored for ical)")
+	dryRun := flags.Bool("dry-run", false, "only report what would be imported")
+	flags.Parse(args)
+
+	input := io.Reader(os.Stdin)
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	// importInto runs the import once the store is loaded
+	var importInto func(ctx context.Context, importer *transfer.Importer) (*transfer.Report, error)
+	if *formatName == icalFormat {
+		if *userID == "" {
+			return errors.New("-user is required for the ical format")
+		}
+		todos, err := transfer.DecodeICal(input)
+		if err != nil {
+			return err
+		}
+		importInto = func(ctx context.Context, importer *transfer.Importer) (*transfer.Report, error) {
+			return importer.ImportICal(ctx, *userID, todos, *dryRun)
+		}
+	} else {
+		format, err := transfer.ParseFormat(*formatName)
+		if err != nil {
+			return err
+		}
+		strategy, err := transfer.ParseDuplicateStrategy(*duplicates)
+		if err != nil {
+			return err
+		}
+		data, err := transfer.Decode(input, format)
+		if err != nil {
+			return err
+		}
+		importInto = func(ctx context.Context, importer *transfer.Importer) (*transfer.Report, error) {
+			return importer.Import(ctx, data, transfer.ImportOptions{Duplicates: strategy, DryRun: *dryRun})
+		}
+	}
+
+	ctx, err := tenantContext(*tenantID)
+	if err != nil {
+		return err
+	}
+	store, err := loadStore(*storePath)
+	if err != nil {
+		return err
+	}
+	report, err := importInto(ctx, transfer.NewImporter(store, store))
+	if report != nil {
+		printReport(report)
+	}
+	if err != nil || *dryRun {
+		return err
+	}
+	return saveStore(store, *storePath)
+}
+
+// tenantContext returns a context acting on behalf of the process itself on the tenant with the given ID
+func tenantContext(id string) (context.Context, error) {
+	if err := tenant.Validate(id); err != nil {
+		return nil, err
+	}
+	return auth.WithSystem(tenant.WithID(context.Background(), id)), nil
+}
+
+// loadStore restores an in-memory store from the snapshot in the store file
+func loadStore(path string) (*inmemory.Store, error) {
+	store := inmemory.NewStore()
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := store.Restore(f); err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// saveStore replaces the store file with a snapshot of the store
+func saveStore(store *inmemory.Store, path string) error {
+	return writeFile(path, store.Snapshot)
+}
+
+// writeFile writes through a temporary file that is renamed into place,
+// so that a failed write leaves the old file intact
+func writeFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func printReport(report *transfer.Report) {
+	if report.DryRun {
+		fmt.Fprintln(os.Stderr, "Dry run, nothing was written")
+	}
+	fmt.Fprintf(os.Stderr, "Users: %d created, %d updated, %d skipped\n", report.Users.Created, report.Users.Updated, report.Users.Skipped)
+	fmt.Fprintf(os.Stderr, "Todos: %d created, %d updated, %d skipped\n", report.Todos.Created, report.Todos.Updated, report.Todos.Skipped)
+	for _, recordErr := range report.Errors {
+		fmt.Fprintf(os.Stderr, "  %v\n", recordErr)
+	}
+}
@@ -0,0 +1,28 @@
+// Package audit records every mutation made through the stores in an audit log
+package audit
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context whose mutations are attributed to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the context, or else the user ID of its principal,
+// or SystemActor when it has neither
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.UserID != "" {
+		return principal.UserID
+	}
+	return domain.SystemActor
+}
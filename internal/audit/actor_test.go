@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestActorFromContext(t *testing.T) {
+	user1 := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "user1", Role: domain.RoleUser})
+
+	tests := map[string]struct {
+		ctx         context.Context
+		expectActor string
+	}{
+		"Actor set explicitly": {
+			ctx:         WithActor(user1, "alice"),
+			expectActor: "alice",
+		},
+		"Principal without an actor": {
+			ctx:         user1,
+			expectActor: "user1",
+		},
+		"System principal": {
+			ctx:         auth.WithSystem(context.Background()),
+			expectActor: domain.SystemActor,
+		},
+		"Neither actor nor principal": {
+			ctx:         context.Background(),
+			expectActor: domain.SystemActor,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expectActor, ActorFromContext(tt.ctx))
+		})
+	}
+}
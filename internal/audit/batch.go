@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Batch operations are applied item by item, so that every item gets its own audit entry
+
+func (s *UserStore) CreateUsers(ctx context.Context, users []*domain.User) error {
+	var batchErr domain.BatchError
+	for i, user := range users {
+		if err := s.CreateUser(ctx, user); err != nil {
+			batchErr.Add(i, user.ID, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (s *UserStore) DeleteUsers(ctx context.Context, ids []string) error {
+	return eachID(ids, func(id string) error {
+		return s.DeleteUser(ctx, id)
+	})
+}
+
+func (s *TodoStore) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	var batchErr domain.BatchError
+	for i, todo := range todos {
+		if err := s.CreateTodo(ctx, todo); err != nil {
+			batchErr.Add(i, todo.ID, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (s *TodoStore) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	var batchErr domain.BatchError
+	for i, todo := range todos {
+		if err := s.UpdateTodo(ctx, todo); err != nil {
+			batchErr.Add(i, todo.ID, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (s *TodoStore) DeleteTodos(ctx context.Context, ids []string) error {
+	return eachID(ids, func(id string) error {
+		return s.DeleteTodo(ctx, id)
+	})
+}
+
+func (s *TodoStore) MarkTodosComplete(ctx context.Context, ids []string) error {
+	return eachID(ids, func(id string) error {
+		return s.MarkTodoComplete(ctx, id)
+	})
+}
+
+// eachID runs fn for every ID, collecting the failures in a *domain.BatchError
+func eachID(ids []string, fn func(id string) error) error {
+	var batchErr domain.BatchError
+	for i, id := range ids {
+		if err := fn(id); err != nil {
+			batchErr.Add(i, id, err)
+		}
+	}
+	return batchErr.Err()
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestTodoStore_BatchRecordsEveryItem(t *testing.T) {
+	tests := map[string]struct {
+		batch        func(ctx context.Context, todos *TodoStore) error
+		expectAction domain.AuditAction
+		expectIDs    []string
+		expectFailed []int
+	}{
+		"CreateTodos": {
+			batch: func(ctx context.Context, todos *TodoStore) error {
+				return todos.CreateTodos(ctx, []*domain.Todo{
+					{ID: "todo3", UserID: "user1"},
+					{ID: "", UserID: "user1"},
+					{ID: "todo4", UserID: "user1"},
+				})
+			},
+			expectAction: domain.AuditActionCreate,
+			expectIDs:    []string{"todo3", "todo4"},
+			expectFailed: []int{1},
+		},
+		"DeleteTodos": {
+			batch: func(ctx context.Context, todos *TodoStore) error {
+				return todos.DeleteTodos(ctx, []string{"todo1", "todo2"})
+			},
+			expectAction: domain.AuditActionDelete,
+			expectIDs:    []string{"todo1"},
+			expectFailed: []int{1},
+		},
+		"MarkTodosComplete": {
+			batch: func(ctx context.Context, todos *TodoStore) error {
+				return todos.MarkTodosComplete(ctx, []string{"todo1"})
+			},
+			expectAction: domain.AuditActionComplete,
+			expectIDs:    []string{"todo1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := seededStore(t)
+			todos := NewTodoStore(store, store, store)
+
+			err := tt.batch(ctx, todos)
+
+			if len(tt.expectFailed) > 0 {
+				var batchErr *domain.BatchError
+				require.True(t, errors.As(err, &batchErr))
+				indexes := make([]int, 0, len(batchErr.Items))
+				for _, item := range batchErr.Items {
+					indexes = append(indexes, item.Index)
+				}
+				assert.Equal(t, tt.expectFailed, indexes)
+			} else {
+				require.NoError(t, err)
+			}
+			entries, err := store.ListActorAuditEntries(ctx, domain.SystemActor)
+			require.NoError(t, err)
+			ids := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				assert.Equal(t, tt.expectAction, entry.Action)
+				ids = append(ids, entry.EntityID)
+			}
+			assert.Equal(t, tt.expectIDs, ids)
+		})
+	}
+}
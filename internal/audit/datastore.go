@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// DataStore wraps a DataStore and records every mutation in the store's own audit log
+// Each mutating method of DataStore has to be overridden here, otherwise it silently
+// bypasses the audit log
+type DataStore struct {
+	biginterface.DataStore
+	users    *UserStore
+	todos    *TodoStore
+	projects *ProjectStore
+}
+
+var _ biginterface.DataStore = (*DataStore)(nil)
+
+// NewDataStore creates a DataStore that records mutations into store itself
+// Each mutation runs in a transaction of store together with its entry
+func NewDataStore(store biginterface.DataStore) *DataStore {
+	runner := dataStoreRunner{store}
+	return &DataStore{
+		DataStore: store,
+		users:     NewUserStore(store, store, runner),
+		todos:     NewTodoStore(store, store, runner),
+		projects:  NewProjectStore(store, store, runner),
+	}
+}
+
+// dataStoreRunner runs the transactions of the decorated stores with WithinTx
+type dataStoreRunner struct {
+	store biginterface.DataStore
+}
+
+func (r dataStoreRunner) RunInTx(ctx context.Context, fn func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context, tx biginterface.DataStore) error {
+		return fn(ctx, tx, tx)
+	})
+}
+
+// User-related operations
+func (s *DataStore) CreateUser(ctx context.Context, user *domain.User) error {
+	return s.users.CreateUser(ctx, user)
+}
+
+func (s *DataStore) UpdateUser(ctx context.Context, user *domain.User) error {
+	return s.users.UpdateUser(ctx, user)
+}
+
+func (s *DataStore) DeleteUser(ctx context.Context, id string) error {
+	return s.users.DeleteUser(ctx, id)
+}
+
+func (s *DataStore) RestoreUser(ctx context.Context, id string) error {
+	return s.users.RestoreUser(ctx, id)
+}
+
+func (s *DataStore) PurgeUser(ctx context.Context, id string) error {
+	return s.users.PurgeUser(ctx, id)
+}
+
+// Todo-related operations
+func (s *DataStore) CreateTodo(ctx context.Context, todo *domain.Todo) error {
+	return s.todos.CreateTodo(ctx, todo)
+}
+
+func (s *DataStore) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
+	return s.todos.UpdateTodo(ctx, todo)
+}
+
+func (s *DataStore) DeleteTodo(ctx context.Context, id string) error {
+	return s.todos.DeleteTodo(ctx, id)
+}
+
+func (s *DataStore) MarkTodoComplete(ctx context.Context, id string) error {
+	return s.todos.MarkTodoComplete(ctx, id)
+}
+
+func (s *DataStore) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	return s.todos.SetTodoDueDate(ctx, id, dueAt)
+}
+
+func (s *DataStore) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	return s.todos.SetTodoPriority(ctx, id, priority)
+}
+
+func (s *DataStore) RestoreTodo(ctx context.Context, id string) error {
+	return s.todos.RestoreTodo(ctx, id)
+}
+
+func (s *DataStore) PurgeTodo(ctx context.Context, id string) error {
+	return s.todos.PurgeTodo(ctx, id)
+}
+
+func (s *DataStore) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	return s.todos.PurgeTodosDeletedBefore(ctx, cutoff)
+}
+
+// Batch operations
+func (s *DataStore) CreateUsers(ctx context.Context, users []*domain.User) error {
+	return s.users.CreateUsers(ctx, users)
+}
+
+func (s *DataStore) DeleteUsers(ctx context.Context, ids []string) error {
+	return s.users.DeleteUsers(ctx, ids)
+}
+
+func (s *DataStore) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	return s.todos.CreateTodos(ctx, todos)
+}
+
+func (s *DataStore) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	return s.todos.UpdateTodos(ctx, todos)
+}
+
+func (s *DataStore) DeleteTodos(ctx context.Context, ids []string) error {
+	return s.todos.DeleteTodos(ctx, ids)
+}
+
+func (s *DataStore) MarkTodosComplete(ctx context.Context, ids []string) error {
+	return s.todos.MarkTodosComplete(ctx, ids)
+}
+
+// Project-related operations
+func (s *DataStore) CreateProject(ctx context.Context, project *domain.Project) error {
+	return s.projects.CreateProject(ctx, project)
+}
+
+func (s *DataStore) UpdateProject(ctx context.Context, project *domain.Project) error {
+	return s.projects.UpdateProject(ctx, project)
+}
+
+func (s *DataStore) DeleteProject(ctx context.Context, id string) error {
+	return s.projects.DeleteProject(ctx, id)
+}
+
+// Transaction operations
+
+// WithinTx hands fn a DataStore that records its mutations in the transaction,
+// so a rollback discards the audit entries along with the changes
+func (s *DataStore) WithinTx(ctx context.Context, fn func(ctx context.Context, tx biginterface.DataStore) error) error {
+	return s.DataStore.WithinTx(ctx, func(ctx context.Context, tx biginterface.DataStore) error {
+		return fn(ctx, NewDataStore(tx))
+	})
+}
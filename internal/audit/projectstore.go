@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// ProjectStore wraps a ProjectStore and records every mutation in an audit log
+// Read operations are passed through unchanged
+type ProjectStore struct {
+	smallinterface.ProjectStore
+	recorder recorder
+}
+
+var _ smallinterface.ProjectStore = (*ProjectStore)(nil)
+
+// NewProjectStore creates a ProjectStore that records mutations of projects into log
+// runner is used as by NewTodoStore
+func NewProjectStore(projects smallinterface.ProjectStore, log smallinterface.AuditStore, runner smallinterface.TxRunner) *ProjectStore {
+	return &ProjectStore{
+		ProjectStore: projects,
+		recorder:     newRecorder(log, runner),
+	}
+}
+
+func (s *ProjectStore) CreateProject(ctx context.Context, project *domain.Project) error {
+	return s.mutate(ctx, domain.AuditActionCreate, project.ID, func(ctx context.Context) error {
+		return s.ProjectStore.CreateProject(ctx, project)
+	})
+}
+
+func (s *ProjectStore) UpdateProject(ctx context.Context, project *domain.Project) error {
+	return s.mutate(ctx, domain.AuditActionUpdate, project.ID, func(ctx context.Context) error {
+		return s.ProjectStore.UpdateProject(ctx, project)
+	})
+}
+
+func (s *ProjectStore) DeleteProject(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionDelete, id, func(ctx context.Context) error {
+		return s.ProjectStore.DeleteProject(ctx, id)
+	})
+}
+
+// mutate runs fn and records the project as it was before and after, in one transaction
+func (s *ProjectStore) mutate(ctx context.Context, action domain.AuditAction, id string, fn func(ctx context.Context) error) error {
+	return s.recorder.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		after, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recorder.record(ctx, action, domain.AuditEntityProject, id, before, after)
+	})
+}
+
+func (s *ProjectStore) snapshot(ctx context.Context, id string) (json.RawMessage, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return snapshot(s.ProjectStore.GetProject(ctx, id))
+}
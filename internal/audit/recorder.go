@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// recorder appends audit entries for the decorated stores
+// The writes it records run in a transaction of runner together with their entry,
+// so that a failed append rolls the write back instead of leaving it unaudited
+type recorder struct {
+	log    smallinterface.AuditStore
+	runner smallinterface.TxRunner
+	now    func() time.Time
+	newID  func() string
+}
+
+func newRecorder(log smallinterface.AuditStore, runner smallinterface.TxRunner) recorder {
+	return recorder{
+		log:    log,
+		runner: runner,
+		now:    time.Now,
+		newID:  domain.NewID,
+	}
+}
+
+// inTx runs fn in a transaction, joining the one already running in ctx
+// Stores are reached through ctx inside it, the ones the runner hands over are not used
+func (r recorder) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.runner.RunInTx(ctx, func(ctx context.Context, _ smallinterface.UserStore, _ smallinterface.TodoStore) error {
+		return fn(ctx)
+	})
+}
+
+func (r recorder) record(ctx context.Context, action domain.AuditAction, entityType domain.AuditEntityType, entityID string, before, after json.RawMessage) error {
+	entry := &domain.AuditEntry{
+		ID:         r.newID(),
+		Actor:      ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		At:         r.now(),
+	}
+	if err := r.log.AppendAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+	return nil
+}
+
+// snapshot encodes an entity, or returns nil when it was not found
+func snapshot(entity interface{}, err error) (json.RawMessage, error) {
+	if err != nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot entity: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// TodoStore wraps a TodoStore and records every mutation in an audit log
+// Read operations are passed through unchanged
+type TodoStore struct {
+	smallinterface.TodoStore
+	recorder recorder
+}
+
+var _ smallinterface.TodoStore = (*TodoStore)(nil)
+
+// NewTodoStore creates a TodoStore that records mutations of todos into log
+// runner must run the transactions of todos and join the one already running in a context,
+// as the stores of this repository do
+func NewTodoStore(todos smallinterface.TodoStore, log smallinterface.AuditStore, runner smallinterface.TxRunner) *TodoStore {
+	return &TodoStore{
+		TodoStore: todos,
+		recorder:  newRecorder(log, runner),
+	}
+}
+
+func (s *TodoStore) CreateTodo(ctx context.Context, todo *domain.Todo) error {
+	return s.mutate(ctx, domain.AuditActionCreate, todo.ID, func(ctx context.Context) error {
+		return s.TodoStore.CreateTodo(ctx, todo)
+	})
+}
+
+func (s *TodoStore) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
+	return s.mutate(ctx, domain.AuditActionUpdate, todo.ID, func(ctx context.Context) error {
+		return s.TodoStore.UpdateTodo(ctx, todo)
+	})
+}
+
+func (s *TodoStore) DeleteTodo(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionDelete, id, func(ctx context.Context) error {
+		return s.TodoStore.DeleteTodo(ctx, id)
+	})
+}
+
+func (s *TodoStore) MarkTodoComplete(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionComplete, id, func(ctx context.Context) error {
+		return s.TodoStore.MarkTodoComplete(ctx, id)
+	})
+}
+
+func (s *TodoStore) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	return s.mutate(ctx, domain.AuditActionUpdate, id, func(ctx context.Context) error {
+		return s.TodoStore.SetTodoDueDate(ctx, id, dueAt)
+	})
+}
+
+func (s *TodoStore) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	return s.mutate(ctx, domain.AuditActionUpdate, id, func(ctx context.Context) error {
+		return s.TodoStore.SetTodoPriority(ctx, id, priority)
+	})
+}
+
+func (s *TodoStore) RestoreTodo(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionRestore, id, func(ctx context.Context) error {
+		return s.TodoStore.RestoreTodo(ctx, id)
+	})
+}
+
+func (s *TodoStore) PurgeTodo(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionPurge, id, func(ctx context.Context) error {
+		return s.TodoStore.PurgeTodo(ctx, id)
+	})
+}
+
+// PurgeTodosDeletedBefore records a single entry without an entity ID
+// The store does not report which todos it purged, only how many
+func (s *TodoStore) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	var purged int
+	err := s.recorder.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = s.TodoStore.PurgeTodosDeletedBefore(ctx, cutoff)
+		if err != nil || purged == 0 {
+			return err
+		}
+		after, err := json.Marshal(struct {
+			Cutoff time.Time `json:"cutoff"`
+			Purged int       `json:"purged"`
+		}{cutoff, purged})
+		if err != nil {
+			return err
+		}
+		return s.recorder.record(ctx, domain.AuditActionPurge, domain.AuditEntityTodo, "", nil, after)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// mutate runs fn and records the todo as it was before and after, in one transaction
+func (s *TodoStore) mutate(ctx context.Context, action domain.AuditAction, id string, fn func(ctx context.Context) error) error {
+	return s.recorder.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		after, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recorder.record(ctx, action, domain.AuditEntityTodo, id, before, after)
+	})
+}
+
+// snapshot encodes a todo, looking in the trash when it is not live
+func (s *TodoStore) snapshot(ctx context.Context, id string) (json.RawMessage, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if todo, err := s.TodoStore.GetTodo(ctx, id); err == nil {
+		return snapshot(todo, nil)
+	}
+	return snapshot(s.TodoStore.GetDeletedTodo(ctx, id))
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// failingLog is an audit log that cannot be appended to
+type failingLog struct {
+	smallinterface.AuditStore
+}
+
+func (failingLog) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	return errors.New("disk full")
+}
+
+// seededStore returns a store holding user1 with the live todo1 and the trashed todo2
+func seededStore(t *testing.T) *inmemory.Store {
+	t.Helper()
+	ctx := context.Background()
+	store := inmemory.NewStore()
+	require.NoError(t, store.CreateUser(ctx, &domain.User{ID: "user1", Name: "John"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Live"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", Title: "Trashed"}))
+	require.NoError(t, store.DeleteTodo(ctx, "todo2"))
+	return store
+}
+
+func decodeTodo(t *testing.T, data json.RawMessage) *domain.Todo {
+	t.Helper()
+	if data == nil {
+		return nil
+	}
+	var todo domain.Todo
+	require.NoError(t, json.Unmarshal(data, &todo))
+	return &todo
+}
+
+func TestTodoStore_RecordsBeforeAndAfter(t *testing.T) {
+	tests := map[string]struct {
+		id          string
+		mutate      func(ctx context.Context, todos *TodoStore) error
+		expectEntry domain.AuditAction
+		checkBefore func(t *testing.T, before *domain.Todo)
+		checkAfter  func(t *testing.T, after *domain.Todo)
+	}{
+		"Create has no before": {
+			id: "todo3",
+			mutate: func(ctx context.Context, todos *TodoStore) error {
+				return todos.CreateTodo(ctx, &domain.Todo{ID: "todo3", UserID: "user1", Title: "New"})
+			},
+			expectEntry: domain.AuditActionCreate,
+			checkBefore: func(t *testing.T, before *domain.Todo) {
+				assert.Nil(t, before)
+			},
+			checkAfter: func(t *testing.T, after *domain.Todo) {
+				require.NotNil(t, after)
+				assert.Equal(t, "New", after.Title)
+			},
+		},
+		"Delete reads the after from the trash": {
+			id: "todo1",
+			mutate: func(ctx context.Context, todos *TodoStore) error {
+				return todos.DeleteTodo(ctx, "todo1")
+			},
+			expectEntry: domain.AuditActionDelete,
+			checkBefore: func(t *testing.T, before *domain.Todo) {
+				require.NotNil(t, before)
+				assert.Nil(t, before.DeletedAt)
+			},
+			checkAfter: func(t *testing.T, after *domain.Todo) {
+				require.NotNil(t, after)
+				assert.NotNil(t, after.DeletedAt)
+			},
+		},
+		"Purge has no after": {
+			id: "todo2",
+			mutate: func(ctx context.Context, todos *TodoStore) error {
+				return todos.PurgeTodo(ctx, "todo2")
+			},
+			expectEntry: domain.AuditActionPurge,
+			checkBefore: func(t *testing.T, before *domain.Todo) {
+				require.NotNil(t, before)
+				assert.Equal(t, "Trashed", before.Title)
+			},
+			checkAfter: func(t *testing.T, after *domain.Todo) {
+				assert.Nil(t, after)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := WithActor(context.Background(), "alice")
+			store := seededStore(t)
+			todos := NewTodoStore(store, store, store)
+
+			require.NoError(t, tt.mutate(ctx, todos))
+
+			entries, err := store.ListEntityAuditEntries(ctx, domain.AuditEntityTodo, tt.id)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expectEntry, entries[0].Action)
+			assert.Equal(t, "alice", entries[0].Actor)
+			tt.checkBefore(t, decodeTodo(t, entries[0].Before))
+			tt.checkAfter(t, decodeTodo(t, entries[0].After))
+		})
+	}
+}
+
+func TestTodoStore_RecordsNothingWhenTheWriteFails(t *testing.T) {
+	ctx := context.Background()
+	store := seededStore(t)
+	todos := NewTodoStore(store, store, store)
+
+	assert.Error(t, todos.DeleteTodo(ctx, "missing"))
+	assert.Error(t, todos.PurgeTodo(ctx, "todo1"))
+
+	entries, err := store.ListActorAuditEntries(ctx, domain.SystemActor)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestTodoStore_FailedAppendRollsTheWriteBack(t *testing.T) {
+	ctx := context.Background()
+	store := seededStore(t)
+	todos := NewTodoStore(store, failingLog{store}, store)
+
+	err := todos.DeleteTodo(ctx, "todo1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	_, err = store.GetTodo(ctx, "todo1")
+	assert.NoError(t, err)
+
+	_, err = todos.PurgeTodosDeletedBefore(ctx, time.Now().Add(time.Hour))
+	require.Error(t, err)
+	_, err = store.GetDeletedTodo(ctx, "todo2")
+	assert.NoError(t, err)
+}
+
+func TestTodoStore_PurgeTodosDeletedBeforeRecordsASummary(t *testing.T) {
+	ctx := context.Background()
+	store := seededStore(t)
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo3", UserID: "user1", Title: "Also trashed"}))
+	require.NoError(t, store.DeleteTodo(ctx, "todo3"))
+	todos := NewTodoStore(store, store, store)
+	cutoff := time.Now().Add(time.Hour)
+
+	purged, err := todos.PurgeTodosDeletedBefore(ctx, cutoff)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	entries, err := store.ListActorAuditEntries(ctx, domain.SystemActor)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionPurge, entries[0].Action)
+	assert.Empty(t, entries[0].EntityID)
+	assert.Nil(t, entries[0].Before)
+	var summary struct {
+		Cutoff time.Time `json:"cutoff"`
+		Purged int       `json:"purged"`
+	}
+	require.NoError(t, json.Unmarshal(entries[0].After, &summary))
+	assert.True(t, cutoff.Equal(summary.Cutoff))
+	assert.Equal(t, 2, summary.Purged)
+
+	// Nothing left to purge, so nothing is recorded
+	purged, err = todos.PurgeTodosDeletedBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	entries, err = store.ListActorAuditEntries(ctx, domain.SystemActor)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
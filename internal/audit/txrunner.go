@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// TxRunner wraps a TxRunner and records the mutations made inside its transactions in an audit log
+// When log belongs to the same store as the runner, the entries are rolled back with the changes
+type TxRunner struct {
+	runner smallinterface.TxRunner
+	log    smallinterface.AuditStore
+}
+
+var _ smallinterface.TxRunner = (*TxRunner)(nil)
+
+// NewTxRunner creates a TxRunner that records mutations into log
+func NewTxRunner(runner smallinterface.TxRunner, log smallinterface.AuditStore) *TxRunner {
+	return &TxRunner{
+		runner: runner,
+		log:    log,
+	}
+}
+
+func (r *TxRunner) RunInTx(ctx context.Context, fn func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error) error {
+	return r.runner.RunInTx(ctx, func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error {
+		return fn(ctx, NewUserStore(users, r.log, r.runner), NewTodoStore(todos, r.log, r.runner))
+	})
+}
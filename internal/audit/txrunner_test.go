@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+func TestTxRunner_RunInTx(t *testing.T) {
+	tests := map[string]struct {
+		fnErr         error
+		expectEntries int
+	}{
+		"Success: Every write made in the transaction is recorded": {
+			fnErr:         nil,
+			expectEntries: 3,
+		},
+		"Error: Entries are rolled back with the writes": {
+			fnErr:         errors.New("abort"),
+			expectEntries: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := WithActor(context.Background(), "alice")
+			store := seededStore(t)
+			runner := NewTxRunner(store, store)
+
+			err := runner.RunInTx(ctx, func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error {
+				require.NoError(t, users.UpdateUser(ctx, &domain.User{ID: "user1", Name: "Johnny"}))
+				require.NoError(t, todos.CreateTodo(ctx, &domain.Todo{ID: "todo3", UserID: "user1", Title: "New"}))
+				require.NoError(t, todos.MarkTodoComplete(ctx, "todo3"))
+				return tt.fnErr
+			})
+
+			assert.Equal(t, tt.fnErr, err)
+			entries, err := store.ListActorAuditEntries(ctx, "alice")
+			require.NoError(t, err)
+			assert.Len(t, entries, tt.expectEntries)
+		})
+	}
+}
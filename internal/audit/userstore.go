@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// UserStore wraps a UserStore and records every mutation in an audit log
+// Read operations are passed through unchanged
+type UserStore struct {
+	smallinterface.UserStore
+	recorder recorder
+}
+
+var _ smallinterface.UserStore = (*UserStore)(nil)
+
+// NewUserStore creates a UserStore that records mutations of users into log
+// runner is used as by NewTodoStore
+func NewUserStore(users smallinterface.UserStore, log smallinterface.AuditStore, runner smallinterface.TxRunner) *UserStore {
+	return &UserStore{
+		UserStore: users,
+		recorder:  newRecorder(log, runner),
+	}
+}
+
+func (s *UserStore) CreateUser(ctx context.Context, user *domain.User) error {
+	return s.mutate(ctx, domain.AuditActionCreate, user.ID, func(ctx context.Context) error {
+		return s.UserStore.CreateUser(ctx, user)
+	})
+}
+
+func (s *UserStore) UpdateUser(ctx context.Context, user *domain.User) error {
+	return s.mutate(ctx, domain.AuditActionUpdate, user.ID, func(ctx context.Context) error {
+		return s.UserStore.UpdateUser(ctx, user)
+	})
+}
+
+func (s *UserStore) DeleteUser(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionDelete, id, func(ctx context.Context) error {
+		return s.UserStore.DeleteUser(ctx, id)
+	})
+}
+
+func (s *UserStore) RestoreUser(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionRestore, id, func(ctx context.Context) error {
+		return s.UserStore.RestoreUser(ctx, id)
+	})
+}
+
+func (s *UserStore) PurgeUser(ctx context.Context, id string) error {
+	return s.mutate(ctx, domain.AuditActionPurge, id, func(ctx context.Context) error {
+		return s.UserStore.PurgeUser(ctx, id)
+	})
+}
+
+// mutate runs fn and records the user as it was before and after, in one transaction
+func (s *UserStore) mutate(ctx context.Context, action domain.AuditAction, id string, fn func(ctx context.Context) error) error {
+	return s.recorder.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		after, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recorder.record(ctx, action, domain.AuditEntityUser, id, before, after)
+	})
+}
+
+// snapshot encodes a user, looking in the trash when it is not live
+func (s *UserStore) snapshot(ctx context.Context, id string) (json.RawMessage, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if user, err := s.UserStore.GetUser(ctx, id); err == nil {
+		return snapshot(user, nil)
+	}
+	return snapshot(s.UserStore.GetDeletedUser(ctx, id))
+}
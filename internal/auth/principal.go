@@ -0,0 +1,47 @@
+// Package auth carries the authenticated principal of a request and decides what it may act on
+package auth
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a context acting on behalf of principal
+func WithPrincipal(ctx context.Context, principal domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// WithSystem returns a context acting on behalf of the process itself, which may act on any data
+// Commands and background jobs use it, as a context without a principal may not act on anything
+func WithSystem(ctx context.Context) context.Context {
+	return WithPrincipal(ctx, domain.SystemPrincipal)
+}
+
+// PrincipalFromContext returns the principal of the context, if it has one
+func PrincipalFromContext(ctx context.Context) (domain.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(domain.Principal)
+	return principal, ok
+}
+
+// Authorize checks that the context may act on the data of the user with the given ID
+// A user may only act on their own data and an admin on everyone's
+// A context without a principal may not act on any data
+func Authorize(ctx context.Context, userID string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if ok && (principal.IsAdmin() || principal.UserID == userID) {
+		return nil
+	}
+	return domain.ErrPermissionDenied
+}
+
+// AuthorizeAdmin checks that the context may act on the data of every user
+func AuthorizeAdmin(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if ok && principal.IsAdmin() {
+		return nil
+	}
+	return domain.ErrPermissionDenied
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestAuthorize(t *testing.T) {
+	user1 := WithPrincipal(context.Background(), domain.Principal{UserID: "user1", Role: domain.RoleUser})
+	admin := WithPrincipal(context.Background(), domain.Principal{UserID: "root", Role: domain.RoleAdmin})
+
+	tests := map[string]struct {
+		ctx            context.Context
+		userID         string
+		expectErr      error
+		expectAdminErr error
+	}{
+		"Own data": {
+			ctx:            user1,
+			userID:         "user1",
+			expectAdminErr: domain.ErrPermissionDenied,
+		},
+		"Another user's data": {
+			ctx:            user1,
+			userID:         "user2",
+			expectErr:      domain.ErrPermissionDenied,
+			expectAdminErr: domain.ErrPermissionDenied,
+		},
+		"Admin": {
+			ctx:    admin,
+			userID: "user2",
+		},
+		"System": {
+			ctx:    WithSystem(context.Background()),
+			userID: "user2",
+		},
+		"No principal": {
+			ctx:            context.Background(),
+			userID:         "user2",
+			expectErr:      domain.ErrPermissionDenied,
+			expectAdminErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expectErr, Authorize(tt.ctx, tt.userID))
+			assert.Equal(t, tt.expectAdminErr, AuthorizeAdmin(tt.ctx))
+		})
+	}
+}
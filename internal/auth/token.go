@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// Errors returned by Authenticate
+// Callers such as the HTTP middleware treat them all as a failed authentication
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrTokenRevoked   = errors.New("token revoked")
+)
+
+// tokenSeparator separates the ID of a token from its secret
+const tokenSeparator = "."
+
+// TokenService issues, revokes and authenticates opaque API tokens
+// A token reads "<id>.<secret>": the ID looks the token up and the secret proves its holder got it when it was issued
+type TokenService struct {
+	tokenStore smallinterface.TokenStore
+	userStore  smallinterface.UserStore
+	now        func() time.Time
+	newID      func() string
+}
+
+// NewTokenService creates a new TokenService
+func NewTokenService(tokenStore smallinterface.TokenStore, userStore smallinterface.UserStore) *TokenService {
+	return &TokenService{
+		tokenStore: tokenStore,
+		userStore:  userStore,
+		now:        time.Now,
+		newID:      domain.NewID,
+	}
+}
+
+// IssueToken creates a token authenticating as the user with the given role and returns it with its secret
+// The secret is not stored, so the returned string is the only copy of the usable token
+// A ttl of zero or less issues a token that never expires, and only admins may issue admin tokens
+func (s *TokenService) IssueToken(ctx context.Context, userID string, role domain.Role, ttl time.Duration) (string, *domain.Token, error) {
+	if !role.IsValid() {
+		return "", nil, fmt.Errorf("invalid role: %q", role)
+	}
+	if err := Authorize(ctx, userID); err != nil {
+		return "", nil, err
+	}
+	if role == domain.RoleAdmin {
+		if err := AuthorizeAdmin(ctx); err != nil {
+			return "", nil, err
+		}
+	}
+	if _, err := s.userStore.GetUser(ctx, userID); err != nil {
+		return "", nil, domain.ErrUserNotFound
+	}
+
+	now := s.now()
+	id, secret := s.newID(), s.newID()
+	token := &domain.Token{
+		ID:         id,
+		UserID:     userID,
+		Role:       role,
+		SecretHash: hashSecret(secret),
+		CreatedAt:  now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.tokenStore.CreateToken(ctx, token); err != nil {
+		return "", nil, err
+	}
+	return token.ID + tokenSeparator + secret, token, nil
+}
+
+// RevokeToken revokes the token with the given ID, so that it no longer authenticates
+func (s *TokenService) RevokeToken(ctx context.Context, id string) error {
+	token, err := s.tokenStore.GetToken(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := Authorize(ctx, token.UserID); err != nil {
+		return err
+	}
+	return s.tokenStore.RevokeToken(ctx, id, s.now())
+}
+
+// GetUserTokens retrieves a user's tokens, revoked and expired ones included
+func (s *TokenService) GetUserTokens(ctx context.Context, userID string) ([]*domain.Token, error) {
+	if err := Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.tokenStore.ListUserTokens(ctx, userID)
+}
+
+// Authenticate resolves a token into the principal it authenticates
+// Tokens of users that were deleted no longer authenticate
+func (s *TokenService) Authenticate(ctx context.Context, raw string) (domain.Principal, error) {
+	id, secret, ok := strings.Cut(raw, tokenSeparator)
+	if !ok || id == "" || secret == "" {
+		return domain.Principal{}, ErrMalformedToken
+	}
+	token, err := s.tokenStore.GetToken(ctx, id)
+	if err != nil {
+		return domain.Principal{}, ErrInvalidToken
+	}
+	// Compare in constant time, so that timing does not reveal how much of a guessed secret is right
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(token.SecretHash)) != 1 {
+		return domain.Principal{}, ErrInvalidToken
+	}
+	if token.IsRevoked() {
+		return domain.Principal{}, ErrTokenRevoked
+	}
+	if token.IsExpired(s.now()) {
+		return domain.Principal{}, ErrTokenExpired
+	}
+	if _, err := s.userStore.GetUser(ctx, token.UserID); err != nil {
+		return domain.Principal{}, ErrInvalidToken
+	}
+	return token.Principal(), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestTokenService_IssueToken(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+	user1 := WithPrincipal(context.Background(), domain.Principal{UserID: "user1", Role: domain.RoleUser})
+
+	tests := map[string]struct {
+		ctx         context.Context
+		role        domain.Role
+		ttl         time.Duration
+		setupMocks  func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore)
+		expectRaw   string
+		expectToken *domain.Token
+		expectErr   error
+	}{
+		"Success: Token expires after its TTL": {
+			ctx:  user1,
+			role: domain.RoleUser,
+			ttl:  time.Hour,
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockTokenStore.EXPECT().CreateToken(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectRaw: "id1.id2",
+			expectToken: &domain.Token{
+				ID:         "id1",
+				UserID:     "user1",
+				Role:       domain.RoleUser,
+				SecretHash: hashSecret("id2"),
+				CreatedAt:  now,
+				ExpiresAt:  &expiresAt,
+			},
+		},
+		"Error: User issues an admin token": {
+			ctx:        user1,
+			role:       domain.RoleAdmin,
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+		"Error: User does not exist": {
+			ctx:  WithSystem(context.Background()),
+			role: domain.RoleAdmin,
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTokenStore := mocks.NewMockTokenStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			tt.setupMocks(mockTokenStore, mockUserStore)
+
+			service := NewTokenService(mockTokenStore, mockUserStore)
+			service.now = func() time.Time { return now }
+			ids := 0
+			service.newID = func() string {
+				ids++
+				return fmt.Sprintf("id%d", ids)
+			}
+			raw, token, err := service.IssueToken(tt.ctx, "user1", tt.role, tt.ttl)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectRaw, raw)
+			assert.Equal(t, tt.expectToken, token)
+		})
+	}
+}
+
+func TestTokenService_Authenticate(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	newToken := func() *domain.Token {
+		return &domain.Token{ID: "token1", UserID: "user1", Role: domain.RoleAdmin, SecretHash: hashSecret("secret"), CreatedAt: past}
+	}
+
+	tests := map[string]struct {
+		raw             string
+		setupMocks      func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore)
+		expectPrincipal domain.Principal
+		expectErr       error
+	}{
+		"Success: Token authenticates with its role": {
+			raw: "token1.secret",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				token := newToken()
+				token.ExpiresAt = &future
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(token, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+			expectPrincipal: domain.Principal{UserID: "user1", Role: domain.RoleAdmin},
+		},
+		"Error: Token without a secret": {
+			raw:        "token1",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {},
+			expectErr:  ErrMalformedToken,
+		},
+		"Error: Token without an ID": {
+			raw:        ".secret",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {},
+			expectErr:  ErrMalformedToken,
+		},
+		"Error: Token does not exist": {
+			raw: "token2.secret",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token2").Return(nil, errors.New("token not found"))
+			},
+			expectErr: ErrInvalidToken,
+		},
+		"Error: Secret is wrong": {
+			raw: "token1.guess",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(newToken(), nil)
+			},
+			expectErr: ErrInvalidToken,
+		},
+		"Error: Token is revoked": {
+			raw: "token1.secret",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				token := newToken()
+				token.RevokedAt = &past
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(token, nil)
+			},
+			expectErr: ErrTokenRevoked,
+		},
+		"Error: Token is expired": {
+			raw: "token1.secret",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				token := newToken()
+				token.ExpiresAt = &now
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(token, nil)
+			},
+			expectErr: ErrTokenExpired,
+		},
+		"Error: User was deleted": {
+			raw: "token1.secret",
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore, mockUserStore *mocks.MockUserStore) {
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(newToken(), nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(nil, errors.New("user not found"))
+			},
+			expectErr: ErrInvalidToken,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTokenStore := mocks.NewMockTokenStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			tt.setupMocks(mockTokenStore, mockUserStore)
+
+			service := NewTokenService(mockTokenStore, mockUserStore)
+			service.now = func() time.Time { return now }
+			principal, err := service.Authenticate(context.Background(), tt.raw)
+
+			assert.Equal(t, tt.expectErr, err)
+			assert.Equal(t, tt.expectPrincipal, principal)
+		})
+	}
+}
+
+func TestTokenService_RevokeToken(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		setupMocks func(mockTokenStore *mocks.MockTokenStore)
+		expectErr  error
+	}{
+		"Success: Owner revokes their token": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore) {
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(&domain.Token{ID: "token1", UserID: "user1"}, nil)
+				mockTokenStore.EXPECT().RevokeToken(gomock.Any(), "token1", now).Return(nil)
+			},
+		},
+		"Error: Another user revokes the token": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			setupMocks: func(mockTokenStore *mocks.MockTokenStore) {
+				mockTokenStore.EXPECT().GetToken(gomock.Any(), "token1").Return(&domain.Token{ID: "token1", UserID: "user1"}, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTokenStore := mocks.NewMockTokenStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			tt.setupMocks(mockTokenStore)
+
+			service := NewTokenService(mockTokenStore, mockUserStore)
+			service.now = func() time.Time { return now }
+			err := service.RevokeToken(WithPrincipal(context.Background(), tt.principal), "token1")
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package biginterface
 
 import (
 	"context"
+	"time"
 
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 )
@@ -17,6 +18,10 @@ type DataStore interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	UpdateUser(ctx context.Context, user *domain.User) error
 	DeleteUser(ctx context.Context, id string) error
+	GetDeletedUser(ctx context.Context, id string) (*domain.User, error)
+	ListDeletedUsers(ctx context.Context) ([]*domain.User, error)
+	RestoreUser(ctx context.Context, id string) error
+	PurgeUser(ctx context.Context, id string) error
 
 	// Todo-related operations
 	GetTodo(ctx context.Context, id string) (*domain.Todo, error)
@@ -26,4 +31,75 @@ type DataStore interface {
 	UpdateTodo(ctx context.Context, todo *domain.Todo) error
 	DeleteTodo(ctx context.Context, id string) error
 	MarkTodoComplete(ctx context.Context, id string) error
+	ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error)
+	ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error)
+	ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error)
+	SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error
+	SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error
+	ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error)
+	ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error)
+	ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error)
+	GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error)
+	ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error)
+	RestoreTodo(ctx context.Context, id string) error
+	PurgeTodo(ctx context.Context, id string) error
+	PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error)
+
+	// Batch operations
+	// Each applies every item it can and reports the others in a *domain.BatchError
+	CreateUsers(ctx context.Context, users []*domain.User) error
+	DeleteUsers(ctx context.Context, ids []string) error
+	CreateTodos(ctx context.Context, todos []*domain.Todo) error
+	UpdateTodos(ctx context.Context, todos []*domain.Todo) error
+	DeleteTodos(ctx context.Context, ids []string) error
+	MarkTodosComplete(ctx context.Context, ids []string) error
+
+	// Tag-related operations
+	AddTodoTag(ctx context.Context, todoID string, tag string) error
+	RemoveTodoTag(ctx context.Context, todoID string, tag string) error
+	ListTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error)
+	ListUserTags(ctx context.Context, userID string) ([]*domain.Tag, error)
+	ListTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error)
+	ListTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error)
+
+	// Project-related operations
+	GetProject(ctx context.Context, id string) (*domain.Project, error)
+	ListUserProjects(ctx context.Context, userID string) ([]*domain.Project, error)
+	CreateProject(ctx context.Context, project *domain.Project) error
+	UpdateProject(ctx context.Context, project *domain.Project) error
+	DeleteProject(ctx context.Context, id string) error
+
+	// Sharing-related operations
+	GetShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) (*domain.Share, error)
+	ListResourceShares(ctx context.Context, resourceType domain.ShareResourceType, resourceID string) ([]*domain.Share, error)
+	ListUserShares(ctx context.Context, userID string) ([]*domain.Share, error)
+	PutShare(ctx context.Context, share *domain.Share) error
+	DeleteShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) error
+
+	// Comment-related operations
+	GetComment(ctx context.Context, id string) (*domain.Comment, error)
+	ListTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error)
+	CreateComment(ctx context.Context, comment *domain.Comment) error
+	UpdateComment(ctx context.Context, comment *domain.Comment) error
+	DeleteComment(ctx context.Context, id string) error
+
+	// Attachment-related operations
+	GetAttachment(ctx context.Context, id string) (*domain.Attachment, error)
+	ListTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error)
+	ListBlobAttachments(ctx context.Context, digest string) ([]*domain.Attachment, error)
+	CreateAttachment(ctx context.Context, attachment *domain.Attachment) error
+	DeleteAttachment(ctx context.Context, id string) error
+
+	// Audit-related operations
+	AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error
+	ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error)
+	ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error)
+
+	// Change notification operations
+	Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error)
+
+	// Transaction operations
+	// fn receives a DataStore scoped to the transaction, which commits when fn returns nil
+	// and rolls back when it returns an error
+	WithinTx(ctx context.Context, fn func(ctx context.Context, tx DataStore) error) error
 }
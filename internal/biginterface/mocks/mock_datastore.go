@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	biginterface "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
 	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,76 @@ func (m *MockDataStore) EXPECT() *MockDataStoreMockRecorder {
 	return m.recorder
 }
 
+// AddTodoTag mocks base method.
+func (m *MockDataStore) AddTodoTag(ctx context.Context, todoID, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoTag", ctx, todoID, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTodoTag indicates an expected call of AddTodoTag.
+func (mr *MockDataStoreMockRecorder) AddTodoTag(ctx, todoID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoTag", reflect.TypeOf((*MockDataStore)(nil).AddTodoTag), ctx, todoID, tag)
+}
+
+// AppendAuditEntry mocks base method.
+func (m *MockDataStore) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAuditEntry indicates an expected call of AppendAuditEntry.
+func (mr *MockDataStoreMockRecorder) AppendAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAuditEntry", reflect.TypeOf((*MockDataStore)(nil).AppendAuditEntry), ctx, entry)
+}
+
+// CreateAttachment mocks base method.
+func (m *MockDataStore) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachment", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAttachment indicates an expected call of CreateAttachment.
+func (mr *MockDataStoreMockRecorder) CreateAttachment(ctx, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachment", reflect.TypeOf((*MockDataStore)(nil).CreateAttachment), ctx, attachment)
+}
+
+// CreateComment mocks base method.
+func (m *MockDataStore) CreateComment(ctx context.Context, comment *domain.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockDataStoreMockRecorder) CreateComment(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockDataStore)(nil).CreateComment), ctx, comment)
+}
+
+// CreateProject mocks base method.
+func (m *MockDataStore) CreateProject(ctx context.Context, project *domain.Project) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", ctx, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockDataStoreMockRecorder) CreateProject(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockDataStore)(nil).CreateProject), ctx, project)
+}
+
 // CreateTodo mocks base method.
 func (m *MockDataStore) CreateTodo(ctx context.Context, todo *domain.Todo) error {
 	m.ctrl.T.Helper()
@@ -55,6 +127,20 @@ func (mr *MockDataStoreMockRecorder) CreateTodo(ctx, todo any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodo", reflect.TypeOf((*MockDataStore)(nil).CreateTodo), ctx, todo)
 }
 
+// CreateTodos mocks base method.
+func (m *MockDataStore) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTodos", ctx, todos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTodos indicates an expected call of CreateTodos.
+func (mr *MockDataStoreMockRecorder) CreateTodos(ctx, todos any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodos", reflect.TypeOf((*MockDataStore)(nil).CreateTodos), ctx, todos)
+}
+
 // CreateUser mocks base method.
 func (m *MockDataStore) CreateUser(ctx context.Context, user *domain.User) error {
 	m.ctrl.T.Helper()
@@ -69,6 +155,76 @@ func (mr *MockDataStoreMockRecorder) CreateUser(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockDataStore)(nil).CreateUser), ctx, user)
 }
 
+// CreateUsers mocks base method.
+func (m *MockDataStore) CreateUsers(ctx context.Context, users []*domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUsers", ctx, users)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUsers indicates an expected call of CreateUsers.
+func (mr *MockDataStoreMockRecorder) CreateUsers(ctx, users any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUsers", reflect.TypeOf((*MockDataStore)(nil).CreateUsers), ctx, users)
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockDataStore) DeleteAttachment(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockDataStoreMockRecorder) DeleteAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockDataStore)(nil).DeleteAttachment), ctx, id)
+}
+
+// DeleteComment mocks base method.
+func (m *MockDataStore) DeleteComment(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockDataStoreMockRecorder) DeleteComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockDataStore)(nil).DeleteComment), ctx, id)
+}
+
+// DeleteProject mocks base method.
+func (m *MockDataStore) DeleteProject(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockDataStoreMockRecorder) DeleteProject(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockDataStore)(nil).DeleteProject), ctx, id)
+}
+
+// DeleteShare mocks base method.
+func (m *MockDataStore) DeleteShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShare", ctx, resourceType, resourceID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockDataStoreMockRecorder) DeleteShare(ctx, resourceType, resourceID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockDataStore)(nil).DeleteShare), ctx, resourceType, resourceID, userID)
+}
+
 // DeleteTodo mocks base method.
 func (m *MockDataStore) DeleteTodo(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -83,6 +239,20 @@ func (mr *MockDataStoreMockRecorder) DeleteTodo(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockDataStore)(nil).DeleteTodo), ctx, id)
 }
 
+// DeleteTodos mocks base method.
+func (m *MockDataStore) DeleteTodos(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTodos", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTodos indicates an expected call of DeleteTodos.
+func (mr *MockDataStoreMockRecorder) DeleteTodos(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodos", reflect.TypeOf((*MockDataStore)(nil).DeleteTodos), ctx, ids)
+}
+
 // DeleteUser mocks base method.
 func (m *MockDataStore) DeleteUser(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -97,6 +267,110 @@ func (mr *MockDataStoreMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockDataStore)(nil).DeleteUser), ctx, id)
 }
 
+// DeleteUsers mocks base method.
+func (m *MockDataStore) DeleteUsers(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUsers", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUsers indicates an expected call of DeleteUsers.
+func (mr *MockDataStoreMockRecorder) DeleteUsers(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUsers", reflect.TypeOf((*MockDataStore)(nil).DeleteUsers), ctx, ids)
+}
+
+// GetAttachment mocks base method.
+func (m *MockDataStore) GetAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, id)
+	ret0, _ := ret[0].(*domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockDataStoreMockRecorder) GetAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockDataStore)(nil).GetAttachment), ctx, id)
+}
+
+// GetComment mocks base method.
+func (m *MockDataStore) GetComment(ctx context.Context, id string) (*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", ctx, id)
+	ret0, _ := ret[0].(*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockDataStoreMockRecorder) GetComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockDataStore)(nil).GetComment), ctx, id)
+}
+
+// GetDeletedTodo mocks base method.
+func (m *MockDataStore) GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedTodo", ctx, id)
+	ret0, _ := ret[0].(*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedTodo indicates an expected call of GetDeletedTodo.
+func (mr *MockDataStoreMockRecorder) GetDeletedTodo(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedTodo", reflect.TypeOf((*MockDataStore)(nil).GetDeletedTodo), ctx, id)
+}
+
+// GetDeletedUser mocks base method.
+func (m *MockDataStore) GetDeletedUser(ctx context.Context, id string) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedUser", ctx, id)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedUser indicates an expected call of GetDeletedUser.
+func (mr *MockDataStoreMockRecorder) GetDeletedUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedUser", reflect.TypeOf((*MockDataStore)(nil).GetDeletedUser), ctx, id)
+}
+
+// GetProject mocks base method.
+func (m *MockDataStore) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", ctx, id)
+	ret0, _ := ret[0].(*domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockDataStoreMockRecorder) GetProject(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockDataStore)(nil).GetProject), ctx, id)
+}
+
+// GetShare mocks base method.
+func (m *MockDataStore) GetShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID, userID string) (*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShare", ctx, resourceType, resourceID, userID)
+	ret0, _ := ret[0].(*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShare indicates an expected call of GetShare.
+func (mr *MockDataStoreMockRecorder) GetShare(ctx, resourceType, resourceID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShare", reflect.TypeOf((*MockDataStore)(nil).GetShare), ctx, resourceType, resourceID, userID)
+}
+
 // GetTodo mocks base method.
 func (m *MockDataStore) GetTodo(ctx context.Context, id string) (*domain.Todo, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +401,201 @@ func (mr *MockDataStoreMockRecorder) GetUser(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockDataStore)(nil).GetUser), ctx, id)
 }
 
+// ListActorAuditEntries mocks base method.
+func (m *MockDataStore) ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActorAuditEntries", ctx, actor)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActorAuditEntries indicates an expected call of ListActorAuditEntries.
+func (mr *MockDataStoreMockRecorder) ListActorAuditEntries(ctx, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActorAuditEntries", reflect.TypeOf((*MockDataStore)(nil).ListActorAuditEntries), ctx, actor)
+}
+
+// ListArchivedTodos mocks base method.
+func (m *MockDataStore) ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArchivedTodos", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchivedTodos indicates an expected call of ListArchivedTodos.
+func (mr *MockDataStoreMockRecorder) ListArchivedTodos(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedTodos", reflect.TypeOf((*MockDataStore)(nil).ListArchivedTodos), ctx, userID)
+}
+
+// ListAssignedTodos mocks base method.
+func (m *MockDataStore) ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssignedTodos", ctx, assigneeID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssignedTodos indicates an expected call of ListAssignedTodos.
+func (mr *MockDataStoreMockRecorder) ListAssignedTodos(ctx, assigneeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssignedTodos", reflect.TypeOf((*MockDataStore)(nil).ListAssignedTodos), ctx, assigneeID)
+}
+
+// ListBlobAttachments mocks base method.
+func (m *MockDataStore) ListBlobAttachments(ctx context.Context, digest string) ([]*domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlobAttachments", ctx, digest)
+	ret0, _ := ret[0].([]*domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlobAttachments indicates an expected call of ListBlobAttachments.
+func (mr *MockDataStoreMockRecorder) ListBlobAttachments(ctx, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlobAttachments", reflect.TypeOf((*MockDataStore)(nil).ListBlobAttachments), ctx, digest)
+}
+
+// ListDeletedTodos mocks base method.
+func (m *MockDataStore) ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedTodos", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedTodos indicates an expected call of ListDeletedTodos.
+func (mr *MockDataStoreMockRecorder) ListDeletedTodos(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedTodos", reflect.TypeOf((*MockDataStore)(nil).ListDeletedTodos), ctx, userID)
+}
+
+// ListDeletedUsers mocks base method.
+func (m *MockDataStore) ListDeletedUsers(ctx context.Context) ([]*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedUsers", ctx)
+	ret0, _ := ret[0].([]*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedUsers indicates an expected call of ListDeletedUsers.
+func (mr *MockDataStoreMockRecorder) ListDeletedUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedUsers", reflect.TypeOf((*MockDataStore)(nil).ListDeletedUsers), ctx)
+}
+
+// ListEntityAuditEntries mocks base method.
+func (m *MockDataStore) ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntityAuditEntries", ctx, entityType, entityID)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntityAuditEntries indicates an expected call of ListEntityAuditEntries.
+func (mr *MockDataStoreMockRecorder) ListEntityAuditEntries(ctx, entityType, entityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntityAuditEntries", reflect.TypeOf((*MockDataStore)(nil).ListEntityAuditEntries), ctx, entityType, entityID)
+}
+
+// ListOverdueTodos mocks base method.
+func (m *MockDataStore) ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueTodos", ctx, userID, now)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueTodos indicates an expected call of ListOverdueTodos.
+func (mr *MockDataStoreMockRecorder) ListOverdueTodos(ctx, userID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueTodos", reflect.TypeOf((*MockDataStore)(nil).ListOverdueTodos), ctx, userID, now)
+}
+
+// ListResourceShares mocks base method.
+func (m *MockDataStore) ListResourceShares(ctx context.Context, resourceType domain.ShareResourceType, resourceID string) ([]*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceShares", ctx, resourceType, resourceID)
+	ret0, _ := ret[0].([]*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceShares indicates an expected call of ListResourceShares.
+func (mr *MockDataStoreMockRecorder) ListResourceShares(ctx, resourceType, resourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceShares", reflect.TypeOf((*MockDataStore)(nil).ListResourceShares), ctx, resourceType, resourceID)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockDataStore) ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", ctx, parentID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockDataStoreMockRecorder) ListSubtasks(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockDataStore)(nil).ListSubtasks), ctx, parentID)
+}
+
+// ListTodoAttachments mocks base method.
+func (m *MockDataStore) ListTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoAttachments", ctx, todoID)
+	ret0, _ := ret[0].([]*domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoAttachments indicates an expected call of ListTodoAttachments.
+func (mr *MockDataStoreMockRecorder) ListTodoAttachments(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoAttachments", reflect.TypeOf((*MockDataStore)(nil).ListTodoAttachments), ctx, todoID)
+}
+
+// ListTodoComments mocks base method.
+func (m *MockDataStore) ListTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoComments", ctx, todoID)
+	ret0, _ := ret[0].([]*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoComments indicates an expected call of ListTodoComments.
+func (mr *MockDataStoreMockRecorder) ListTodoComments(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoComments", reflect.TypeOf((*MockDataStore)(nil).ListTodoComments), ctx, todoID)
+}
+
+// ListTodoTags mocks base method.
+func (m *MockDataStore) ListTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoTags", ctx, todoID)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoTags indicates an expected call of ListTodoTags.
+func (mr *MockDataStoreMockRecorder) ListTodoTags(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoTags", reflect.TypeOf((*MockDataStore)(nil).ListTodoTags), ctx, todoID)
+}
+
 // ListTodos mocks base method.
 func (m *MockDataStore) ListTodos(ctx context.Context) ([]*domain.Todo, error) {
 	m.ctrl.T.Helper()
@@ -142,6 +611,96 @@ func (mr *MockDataStoreMockRecorder) ListTodos(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockDataStore)(nil).ListTodos), ctx)
 }
 
+// ListTodosDueBetween mocks base method.
+func (m *MockDataStore) ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosDueBetween", ctx, userID, from, to)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosDueBetween indicates an expected call of ListTodosDueBetween.
+func (mr *MockDataStoreMockRecorder) ListTodosDueBetween(ctx, userID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosDueBetween", reflect.TypeOf((*MockDataStore)(nil).ListTodosDueBetween), ctx, userID, from, to)
+}
+
+// ListTodosWithAllTags mocks base method.
+func (m *MockDataStore) ListTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosWithAllTags", ctx, userID, tags)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosWithAllTags indicates an expected call of ListTodosWithAllTags.
+func (mr *MockDataStoreMockRecorder) ListTodosWithAllTags(ctx, userID, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosWithAllTags", reflect.TypeOf((*MockDataStore)(nil).ListTodosWithAllTags), ctx, userID, tags)
+}
+
+// ListTodosWithAnyTag mocks base method.
+func (m *MockDataStore) ListTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosWithAnyTag", ctx, userID, tags)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosWithAnyTag indicates an expected call of ListTodosWithAnyTag.
+func (mr *MockDataStoreMockRecorder) ListTodosWithAnyTag(ctx, userID, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosWithAnyTag", reflect.TypeOf((*MockDataStore)(nil).ListTodosWithAnyTag), ctx, userID, tags)
+}
+
+// ListUserProjects mocks base method.
+func (m *MockDataStore) ListUserProjects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserProjects", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserProjects indicates an expected call of ListUserProjects.
+func (mr *MockDataStoreMockRecorder) ListUserProjects(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserProjects", reflect.TypeOf((*MockDataStore)(nil).ListUserProjects), ctx, userID)
+}
+
+// ListUserShares mocks base method.
+func (m *MockDataStore) ListUserShares(ctx context.Context, userID string) ([]*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserShares", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserShares indicates an expected call of ListUserShares.
+func (mr *MockDataStoreMockRecorder) ListUserShares(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserShares", reflect.TypeOf((*MockDataStore)(nil).ListUserShares), ctx, userID)
+}
+
+// ListUserTags mocks base method.
+func (m *MockDataStore) ListUserTags(ctx context.Context, userID string) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTags", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTags indicates an expected call of ListUserTags.
+func (mr *MockDataStoreMockRecorder) ListUserTags(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTags", reflect.TypeOf((*MockDataStore)(nil).ListUserTags), ctx, userID)
+}
+
 // ListUserTodos mocks base method.
 func (m *MockDataStore) ListUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +716,21 @@ func (mr *MockDataStoreMockRecorder) ListUserTodos(ctx, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTodos", reflect.TypeOf((*MockDataStore)(nil).ListUserTodos), ctx, userID)
 }
 
+// ListUserTodosByPriority mocks base method.
+func (m *MockDataStore) ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTodosByPriority", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTodosByPriority indicates an expected call of ListUserTodosByPriority.
+func (mr *MockDataStoreMockRecorder) ListUserTodosByPriority(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTodosByPriority", reflect.TypeOf((*MockDataStore)(nil).ListUserTodosByPriority), ctx, userID)
+}
+
 // ListUsers mocks base method.
 func (m *MockDataStore) ListUsers(ctx context.Context) ([]*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -186,6 +760,175 @@ func (mr *MockDataStoreMockRecorder) MarkTodoComplete(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTodoComplete", reflect.TypeOf((*MockDataStore)(nil).MarkTodoComplete), ctx, id)
 }
 
+// MarkTodosComplete mocks base method.
+func (m *MockDataStore) MarkTodosComplete(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTodosComplete", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkTodosComplete indicates an expected call of MarkTodosComplete.
+func (mr *MockDataStoreMockRecorder) MarkTodosComplete(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTodosComplete", reflect.TypeOf((*MockDataStore)(nil).MarkTodosComplete), ctx, ids)
+}
+
+// PurgeTodo mocks base method.
+func (m *MockDataStore) PurgeTodo(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTodo", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTodo indicates an expected call of PurgeTodo.
+func (mr *MockDataStoreMockRecorder) PurgeTodo(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTodo", reflect.TypeOf((*MockDataStore)(nil).PurgeTodo), ctx, id)
+}
+
+// PurgeTodosDeletedBefore mocks base method.
+func (m *MockDataStore) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTodosDeletedBefore", ctx, cutoff)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTodosDeletedBefore indicates an expected call of PurgeTodosDeletedBefore.
+func (mr *MockDataStoreMockRecorder) PurgeTodosDeletedBefore(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTodosDeletedBefore", reflect.TypeOf((*MockDataStore)(nil).PurgeTodosDeletedBefore), ctx, cutoff)
+}
+
+// PurgeUser mocks base method.
+func (m *MockDataStore) PurgeUser(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeUser indicates an expected call of PurgeUser.
+func (mr *MockDataStoreMockRecorder) PurgeUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUser", reflect.TypeOf((*MockDataStore)(nil).PurgeUser), ctx, id)
+}
+
+// PutShare mocks base method.
+func (m *MockDataStore) PutShare(ctx context.Context, share *domain.Share) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutShare", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutShare indicates an expected call of PutShare.
+func (mr *MockDataStoreMockRecorder) PutShare(ctx, share any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutShare", reflect.TypeOf((*MockDataStore)(nil).PutShare), ctx, share)
+}
+
+// RemoveTodoTag mocks base method.
+func (m *MockDataStore) RemoveTodoTag(ctx context.Context, todoID, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoTag", ctx, todoID, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTodoTag indicates an expected call of RemoveTodoTag.
+func (mr *MockDataStoreMockRecorder) RemoveTodoTag(ctx, todoID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoTag", reflect.TypeOf((*MockDataStore)(nil).RemoveTodoTag), ctx, todoID, tag)
+}
+
+// RestoreTodo mocks base method.
+func (m *MockDataStore) RestoreTodo(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTodo", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTodo indicates an expected call of RestoreTodo.
+func (mr *MockDataStoreMockRecorder) RestoreTodo(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTodo", reflect.TypeOf((*MockDataStore)(nil).RestoreTodo), ctx, id)
+}
+
+// RestoreUser mocks base method.
+func (m *MockDataStore) RestoreUser(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockDataStoreMockRecorder) RestoreUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockDataStore)(nil).RestoreUser), ctx, id)
+}
+
+// SetTodoDueDate mocks base method.
+func (m *MockDataStore) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTodoDueDate", ctx, id, dueAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTodoDueDate indicates an expected call of SetTodoDueDate.
+func (mr *MockDataStoreMockRecorder) SetTodoDueDate(ctx, id, dueAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTodoDueDate", reflect.TypeOf((*MockDataStore)(nil).SetTodoDueDate), ctx, id, dueAt)
+}
+
+// SetTodoPriority mocks base method.
+func (m *MockDataStore) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTodoPriority", ctx, id, priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTodoPriority indicates an expected call of SetTodoPriority.
+func (mr *MockDataStoreMockRecorder) SetTodoPriority(ctx, id, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTodoPriority", reflect.TypeOf((*MockDataStore)(nil).SetTodoPriority), ctx, id, priority)
+}
+
+// UpdateComment mocks base method.
+func (m *MockDataStore) UpdateComment(ctx context.Context, comment *domain.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockDataStoreMockRecorder) UpdateComment(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockDataStore)(nil).UpdateComment), ctx, comment)
+}
+
+// UpdateProject mocks base method.
+func (m *MockDataStore) UpdateProject(ctx context.Context, project *domain.Project) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", ctx, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockDataStoreMockRecorder) UpdateProject(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockDataStore)(nil).UpdateProject), ctx, project)
+}
+
 // UpdateTodo mocks base method.
 func (m *MockDataStore) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
 	m.ctrl.T.Helper()
@@ -200,6 +943,20 @@ func (mr *MockDataStoreMockRecorder) UpdateTodo(ctx, todo any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockDataStore)(nil).UpdateTodo), ctx, todo)
 }
 
+// UpdateTodos mocks base method.
+func (m *MockDataStore) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodos", ctx, todos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTodos indicates an expected call of UpdateTodos.
+func (mr *MockDataStoreMockRecorder) UpdateTodos(ctx, todos any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodos", reflect.TypeOf((*MockDataStore)(nil).UpdateTodos), ctx, todos)
+}
+
 // UpdateUser mocks base method.
 func (m *MockDataStore) UpdateUser(ctx context.Context, user *domain.User) error {
 	m.ctrl.T.Helper()
@@ -213,3 +970,32 @@ func (mr *MockDataStoreMockRecorder) UpdateUser(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockDataStore)(nil).UpdateUser), ctx, user)
 }
+
+// Watch mocks base method.
+func (m *MockDataStore) Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, filter)
+	ret0, _ := ret[0].(<-chan domain.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockDataStoreMockRecorder) Watch(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockDataStore)(nil).Watch), ctx, filter)
+}
+
+// WithinTx mocks base method.
+func (m *MockDataStore) WithinTx(ctx context.Context, fn func(context.Context, biginterface.DataStore) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockDataStoreMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockDataStore)(nil).WithinTx), ctx, fn)
+}
@@ -0,0 +1,172 @@
+// Package changefeed fans out store changes to watchers
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// ErrRevisionCompacted is returned when a watch resumes after a revision that is no longer retained
+// The watcher has to reload the current state and watch from the latest revision
+var ErrRevisionCompacted = errors.New("revision is no longer retained")
+
+// Feed numbers changes and delivers them to watchers
+// The last historySize changes are retained so that a watcher can resume after a revision,
+// and each watcher may fall at most maxPending changes behind before it is dropped
+type Feed struct {
+	mu          sync.Mutex
+	revision    int64
+	history     []domain.Change
+	historySize int
+	maxPending  int
+	watchers    map[*watcher]struct{}
+}
+
+// watcher is a single subscription
+// Changes wait in queue until the watcher's goroutine hands them to out
+type watcher struct {
+	filter  domain.ChangeFilter
+	queue   []domain.Change
+	dropped bool
+	signal  chan struct{}
+	out     chan domain.Change
+}
+
+// NewFeed creates a Feed
+func NewFeed(historySize, maxPending int) *Feed {
+	return &Feed{
+		historySize: historySize,
+		maxPending:  maxPending,
+		watchers:    make(map[*watcher]struct{}),
+	}
+}
+
+// Publish assigns the next revision to a change and queues it for every matching watcher
+// A watcher whose queue is full is dropped instead of slowing down the writer
+func (f *Feed) Publish(change domain.Change) domain.Change {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.revision++
+	change.Revision = f.revision
+
+	if f.historySize > 0 {
+		if len(f.history) == f.historySize {
+			copy(f.history, f.history[1:])
+			f.history = f.history[:len(f.history)-1]
+		}
+		f.history = append(f.history, change)
+	}
+
+	for w := range f.watchers {
+		if w.dropped || !w.filter.Matches(change) {
+			continue
+		}
+		if len(w.queue) >= f.maxPending {
+			w.dropped = true
+		} else {
+			w.queue = append(w.queue, change)
+		}
+		notify(w)
+	}
+	return change
+}
+
+// Revision returns the revision of the latest change
+func (f *Feed) Revision() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.revision
+}
+
+// Watch subscribes to the changes that match filter
+// The channel is closed when ctx is done, or after the queued changes are delivered
+// when the watcher fell too far behind; in that case it can resume after the last
+// revision it received
+func (f *Feed) Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &watcher{
+		filter: filter,
+		signal: make(chan struct{}, 1),
+		out:    make(chan domain.Change),
+	}
+
+	if filter.AfterRevision > 0 {
+		if filter.AfterRevision > f.revision {
+			return nil, fmt.Errorf("revision %d has not happened yet, latest is %d", filter.AfterRevision, f.revision)
+		}
+		oldest := f.revision + 1
+		if len(f.history) > 0 {
+			oldest = f.history[0].Revision
+		}
+		if filter.AfterRevision < oldest-1 {
+			return nil, fmt.Errorf("%w: %d, oldest retained is %d", ErrRevisionCompacted, filter.AfterRevision, oldest)
+		}
+		for _, change := range f.history {
+			if change.Revision > filter.AfterRevision && filter.Matches(change) {
+				w.queue = append(w.queue, change)
+			}
+		}
+		if len(w.queue) > 0 {
+			notify(w)
+		}
+	}
+
+	f.watchers[w] = struct{}{}
+	go f.deliver(ctx, w)
+	return w.out, nil
+}
+
+// deliver hands queued changes to the watcher until ctx is done or the watcher is dropped
+func (f *Feed) deliver(ctx context.Context, w *watcher) {
+	defer close(w.out)
+	defer f.remove(w)
+
+	for {
+		f.mu.Lock()
+		batch := w.queue
+		w.queue = nil
+		dropped := w.dropped
+		f.mu.Unlock()
+
+		for _, change := range batch {
+			select {
+			case w.out <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if dropped {
+			return
+		}
+		if len(batch) > 0 {
+			continue
+		}
+
+		select {
+		case <-w.signal:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (f *Feed) remove(w *watcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.watchers, w)
+}
+
+// notify wakes up the watcher's goroutine without blocking
+func notify(w *watcher) {
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
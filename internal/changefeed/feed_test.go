@@ -0,0 +1,150 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func todoChange(id, userID string) domain.Change {
+	return domain.Change{Kind: domain.ChangeUpdated, Entity: domain.ChangeEntityTodo, EntityID: id, UserID: userID}
+}
+
+// receive reads n changes, failing the test if they do not arrive in time
+func receive(t *testing.T, ch <-chan domain.Change, n int) []domain.Change {
+	t.Helper()
+	changes := make([]domain.Change, 0, n)
+	for len(changes) < n {
+		select {
+		case change, ok := <-ch:
+			require.True(t, ok, "channel closed after %d changes", len(changes))
+			changes = append(changes, change)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d changes", len(changes), n)
+		}
+	}
+	return changes
+}
+
+func revisions(changes []domain.Change) []int64 {
+	revs := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		revs = append(revs, change.Revision)
+	}
+	return revs
+}
+
+func TestFeed_WatchFiltersChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := NewFeed(10, 10)
+
+	ch, err := feed.Watch(ctx, domain.ChangeFilter{UserID: "user1"})
+	require.NoError(t, err)
+
+	feed.Publish(todoChange("todo1", "user1"))
+	feed.Publish(todoChange("todo2", "user2"))
+	feed.Publish(todoChange("todo3", "user1"))
+
+	changes := receive(t, ch, 2)
+	assert.Equal(t, []int64{1, 3}, revisions(changes))
+	assert.Equal(t, "todo3", changes[1].EntityID)
+}
+
+func TestFeed_WatchResumesAfterRevision(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := NewFeed(10, 10)
+	for i := 0; i < 3; i++ {
+		feed.Publish(todoChange("todo1", "user1"))
+	}
+
+	ch, err := feed.Watch(ctx, domain.ChangeFilter{AfterRevision: 1})
+	require.NoError(t, err)
+	feed.Publish(todoChange("todo1", "user1"))
+
+	assert.Equal(t, []int64{2, 3, 4}, revisions(receive(t, ch, 3)))
+}
+
+func TestFeed_WatchErrors(t *testing.T) {
+	feed := NewFeed(2, 10)
+	for i := 0; i < 5; i++ {
+		feed.Publish(todoChange("todo1", "user1"))
+	}
+
+	tests := map[string]struct {
+		afterRevision int64
+		expectErr     error
+	}{
+		"Revision no longer retained": {
+			afterRevision: 2,
+			expectErr:     ErrRevisionCompacted,
+		},
+		"Revision in the future": {
+			afterRevision: 6,
+			expectErr:     errors.New("revision 6 has not happened yet"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := feed.Watch(context.Background(), domain.ChangeFilter{AfterRevision: tt.afterRevision})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectErr.Error())
+		})
+	}
+}
+
+func TestFeed_SlowWatcherIsDropped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := NewFeed(10, 2)
+
+	ch, err := feed.Watch(ctx, domain.ChangeFilter{})
+	require.NoError(t, err)
+
+	// Nobody reads, so the delivering goroutine holds at most one change
+	// and the rest pile up in the queue until the watcher is dropped
+	for i := 0; i < 10; i++ {
+		feed.Publish(todoChange("todo1", "user1"))
+	}
+
+	received := make([]domain.Change, 0)
+	for change := range ch {
+		received = append(received, change)
+	}
+	assert.NotEmpty(t, received)
+	assert.Less(t, len(received), 10)
+	// What was received has no gaps, so the watcher can resume after the last revision
+	for i, change := range received {
+		assert.Equal(t, int64(i+1), change.Revision)
+	}
+
+	resumed, err := feed.Watch(ctx, domain.ChangeFilter{AfterRevision: received[len(received)-1].Revision})
+	require.NoError(t, err)
+	rest := receive(t, resumed, 10-len(received))
+	assert.Equal(t, int64(10), rest[len(rest)-1].Revision)
+}
+
+func TestFeed_WatchEndsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	feed := NewFeed(10, 10)
+
+	ch, err := feed.Watch(ctx, domain.ChangeFilter{})
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed")
+	}
+}
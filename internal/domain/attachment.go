@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Blob is a piece of content kept in a BlobStore under the hex SHA-256 Digest of its bytes
+// ContentType is sniffed from the content rather than taken from the uploader
+type Blob struct {
+	Digest      string    `json:"digest"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Attachment is a file such as a screenshot or a document attached to a Todo
+// Its content is the blob with Digest, which attachments with the same content share
+type Attachment struct {
+	ID          string    `json:"id"`
+	TodoID      string    `json:"todo_id"`
+	UploaderID  string    `json:"uploader_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Digest      string    `json:"digest"`
+	CreatedAt   time.Time `json:"created_at"`
+}
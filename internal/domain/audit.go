@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the kind of mutation an audit entry records
+type AuditAction string
+
+const (
+	AuditActionCreate   AuditAction = "create"
+	AuditActionUpdate   AuditAction = "update"
+	AuditActionDelete   AuditAction = "delete"
+	AuditActionComplete AuditAction = "complete"
+	AuditActionRestore  AuditAction = "restore"
+	AuditActionPurge    AuditAction = "purge"
+)
+
+// AuditEntityType is the kind of entity an audit entry is about
+type AuditEntityType string
+
+const (
+	AuditEntityUser    AuditEntityType = "user"
+	AuditEntityTodo    AuditEntityType = "todo"
+	AuditEntityProject AuditEntityType = "project"
+)
+
+// IsValid reports whether the entity type is one that is audited
+func (t AuditEntityType) IsValid() bool {
+	switch t {
+	case AuditEntityUser, AuditEntityTodo, AuditEntityProject:
+		return true
+	}
+	return false
+}
+
+// SystemActor is recorded when a mutation happens without an actor in the context
+const SystemActor = "system"
+
+// AuditEntry records a single mutation of an entity
+// Before and After are JSON snapshots of the entity and are empty when it did not exist
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     AuditAction     `json:"action"`
+	EntityType AuditEntityType `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	At         time.Time       `json:"at"`
+}
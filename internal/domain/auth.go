@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// Role decides what a principal may act on
+type Role string
+
+const (
+	// RoleUser may only act on the principal's own data
+	RoleUser Role = "user"
+	// RoleAdmin may act on the data of every user
+	RoleAdmin Role = "admin"
+)
+
+// IsValid reports whether the role is one of the defined roles
+func (r Role) IsValid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
+// Principal is the authenticated user a request acts on behalf of
+type Principal struct {
+	UserID string `json:"user_id"`
+	Role   Role   `json:"role"`
+}
+
+// IsAdmin reports whether the principal may act on the data of every user
+func (p Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+// SystemPrincipal is the principal of the process itself, such as a CLI or a background job
+// It is an admin, and its mutations are audited as SystemActor
+var SystemPrincipal = Principal{UserID: SystemActor, Role: RoleAdmin}
+
+// Token is an API token that authenticates its holder as a principal of the user with the token's role
+// Only the SHA-256 hash of the secret is kept, so the stored tokens cannot be used to authenticate
+type Token struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Role       Role       `json:"role"`
+	SecretHash string     `json:"secret_hash"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *Token) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpired reports whether the token has expired by now
+// Tokens without ExpiresAt never expire
+func (t *Token) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// Principal returns the principal the token authenticates
+func (t *Token) Principal() Principal {
+	return Principal{UserID: t.UserID, Role: t.Role}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BatchMode decides what a batch operation does when some of its items fail
+type BatchMode string
+
+const (
+	// BatchAllOrNothing applies no item unless every item succeeds
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchBestEffort applies every item that succeeds and reports the others
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// IsValid reports whether the mode is one of the defined modes
+func (m BatchMode) IsValid() bool {
+	return m == BatchAllOrNothing || m == BatchBestEffort
+}
+
+// BatchItemError is the failure of a single item of a batch
+// Index is the item's position in the batch as given by the caller
+type BatchItemError struct {
+	Index int
+	ID    string
+	Err   error
+}
+
+func (e BatchItemError) Error() string {
+	return fmt.Sprintf("item %d (%s): %v", e.Index, e.ID, e.Err)
+}
+
+func (e BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON writes the error as its message
+func (e BatchItemError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Index int    `json:"index"`
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	}{e.Index, e.ID, e.Err.Error()})
+}
+
+// BatchError is returned by the batch operations of stores when some of the items failed
+// Items that are not listed were applied
+type BatchError struct {
+	Items []BatchItemError
+}
+
+// Add records the failure of the item at index
+func (e *BatchError) Add(index int, id string, err error) {
+	e.Items = append(e.Items, BatchItemError{Index: index, ID: id, Err: err})
+}
+
+// Err returns the BatchError, or nil when no item failed
+func (e *BatchError) Err() error {
+	if len(e.Items) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		messages = append(messages, item.Error())
+	}
+	return fmt.Sprintf("%d items failed: %s", len(e.Items), strings.Join(messages, "; "))
+}
+
+// BatchResult reports the outcome of a batch operation item by item
+// Succeeded holds the IDs of the items that were applied, in the order they were given
+type BatchResult struct {
+	Succeeded []string         `json:"succeeded"`
+	Failed    []BatchItemError `json:"failed"`
+}
+
+// TodoFilter selects a user's Todos for batch operations
+// Empty fields match every Todo
+type TodoFilter struct {
+	UserID    string
+	ProjectID string
+	Completed *bool
+	Priority  *Priority
+	// DueBefore matches Todos with a due date before it
+	DueBefore *time.Time
+}
+
+// Matches reports whether a Todo passes the filter
+func (f TodoFilter) Matches(todo *Todo) bool {
+	if f.UserID != "" && f.UserID != todo.UserID {
+		return false
+	}
+	if f.ProjectID != "" && f.ProjectID != todo.ProjectID {
+		return false
+	}
+	if f.Completed != nil && *f.Completed != todo.Completed {
+		return false
+	}
+	if f.Priority != nil && *f.Priority != todo.Priority {
+		return false
+	}
+	if f.DueBefore != nil && (todo.DueAt == nil || !todo.DueAt.Before(*f.DueBefore)) {
+		return false
+	}
+	return true
+}
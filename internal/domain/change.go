@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// ChangeKind is the kind of write a Change reports
+type ChangeKind string
+
+const (
+	ChangeCreated ChangeKind = "created"
+	ChangeUpdated ChangeKind = "updated"
+	// ChangeCompleted is an update that marked a Todo as completed
+	ChangeCompleted ChangeKind = "completed"
+	ChangeDeleted   ChangeKind = "deleted"
+	ChangeRestored  ChangeKind = "restored"
+	ChangePurged    ChangeKind = "purged"
+)
+
+// ChangeEntity is the kind of entity a Change is about
+type ChangeEntity string
+
+const (
+	ChangeEntityUser ChangeEntity = "user"
+	ChangeEntityTodo ChangeEntity = "todo"
+)
+
+// Change is published after every successful write to a store
+// Revisions increase by one with every change, so a watcher can resume after the last one it saw
+// User or Todo holds the entity as it was after the write, and both are nil once it is purged
+type Change struct {
+	Revision int64        `json:"revision"`
+	Kind     ChangeKind   `json:"kind"`
+	Entity   ChangeEntity `json:"entity"`
+	EntityID string       `json:"entity_id"`
+	UserID   string       `json:"user_id"`
+	User     *User        `json:"user,omitempty"`
+	Todo     *Todo        `json:"todo,omitempty"`
+	At       time.Time    `json:"at"`
+}
+
+// ChangeFilter selects the changes a watcher receives
+// Empty fields match everything
+type ChangeFilter struct {
+	Entity   ChangeEntity
+	EntityID string
+	// UserID matches a user and every todo owned by that user
+	UserID string
+	// AfterRevision resumes a watch, delivering retained changes after that revision first
+	AfterRevision int64
+}
+
+// Matches reports whether a change passes the filter, ignoring AfterRevision
+func (f ChangeFilter) Matches(change Change) bool {
+	if f.Entity != "" && f.Entity != change.Entity {
+		return false
+	}
+	if f.EntityID != "" && f.EntityID != change.EntityID {
+		return false
+	}
+	if f.UserID != "" && f.UserID != change.UserID {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// ChecklistItem is an item of a Markdown checklist such as "- [x] Buy milk"
+// Items indented below another item are its Items
+type ChecklistItem struct {
+	Title     string
+	Completed bool
+	Items     []*ChecklistItem
+}
+
+// checklistIndent is the indentation RenderChecklist uses per nesting level
+const checklistIndent = "  "
+
+// checklistTabWidth is how many columns a tab counts for when nesting is determined
+const checklistTabWidth = 4
+
+// ParseChecklist parses a Markdown checklist
+// Items start with "-", "*" or "+" followed by "[ ]", or "[x]" for completed ones, and an item is nested
+// below the closest preceding item that is indented less; blank lines are ignored
+func ParseChecklist(markdown string) ([]*ChecklistItem, error) {
+	type open struct {
+		indent int
+		item   *ChecklistItem
+	}
+
+	var roots []*ChecklistItem
+	var stack []open
+	scanner := bufio.NewScanner(strings.NewReader(markdown))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if text == "" {
+			continue
+		}
+		trimmed := strings.TrimLeft(text, " \t")
+		item, err := parseChecklistItem(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		indent := checklistColumns(text[:len(text)-len(trimmed)])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, item)
+		} else {
+			parent := stack[len(stack)-1].item
+			parent.Items = append(parent.Items, item)
+		}
+		stack = append(stack, open{indent: indent, item: item})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
+
+func parseChecklistItem(text string) (*ChecklistItem, error) {
+	if len(text) < 6 || !strings.ContainsRune("-*+", rune(text[0])) || text[1] != ' ' || text[2] != '[' || text[4] != ']' {
+		return nil, fmt.Errorf("not a checklist item: %q", text)
+	}
+	item := &ChecklistItem{}
+	switch text[3] {
+	case ' ':
+	case 'x', 'X':
+		item.Completed = true
+	default:
+		return nil, fmt.Errorf("invalid checkbox %q", text[2:5])
+	}
+	if text[5] != ' ' {
+		return nil, fmt.Errorf("not a checklist item: %q", text)
+	}
+	item.Title = strings.TrimSpace(text[6:])
+	if item.Title == "" {
+		return nil, fmt.Errorf("checklist item without a title: %q", text)
+	}
+	return item, nil
+}
+
+// checklistColumns returns how many columns an indentation spans
+func checklistColumns(indent string) int {
+	columns := 0
+	for _, r := range indent {
+		if r == '\t' {
+			columns += checklistTabWidth - columns%checklistTabWidth
+		} else {
+			columns++
+		}
+	}
+	return columns
+}
+
+var checklistLineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// RenderChecklist renders items as a Markdown checklist that ParseChecklist reads back as they are
+// Line breaks in titles become spaces, since an item takes a single line
+func RenderChecklist(items []*ChecklistItem) string {
+	var b strings.Builder
+	renderChecklistItems(&b, items, 0)
+	return b.String()
+}
+
+func renderChecklistItems(b *strings.Builder, items []*ChecklistItem, level int) {
+	for _, item := range items {
+		box := "[ ]"
+		if item.Completed {
+			box = "[x]"
+		}
+		title := strings.TrimSpace(checklistLineBreaks.Replace(item.Title))
+		fmt.Fprintf(b, "%s- %s %s\n", strings.Repeat(checklistIndent, level), box, title)
+		renderChecklistItems(b, item.Items, level+1)
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecklist(t *testing.T) {
+	tests := map[string]struct {
+		markdown    string
+		expectItems []*ChecklistItem
+		expectErr   string
+	}{
+		"Nested items and checked boxes": {
+			markdown: "- [ ] Groceries\n  - [x] Milk\n  - [ ] Eggs\n    * [X] Free range\n\n+ [ ] Laundry\n",
+			expectItems: []*ChecklistItem{
+				{Title: "Groceries", Items: []*ChecklistItem{
+					{Title: "Milk", Completed: true},
+					{Title: "Eggs", Items: []*ChecklistItem{{Title: "Free range", Completed: true}}},
+				}},
+				{Title: "Laundry"},
+			},
+		},
+		"Tabs and uneven indentation": {
+			markdown: "- [ ] Parent\n\t- [ ] Tab child\n   - [ ] Space child\n",
+			expectItems: []*ChecklistItem{
+				{Title: "Parent", Items: []*ChecklistItem{{Title: "Tab child"}, {Title: "Space child"}}},
+			},
+		},
+		"Plain text is rejected": {
+			markdown:  "- [ ] Item\nSome notes\n",
+			expectErr: "line 2: not a checklist item",
+		},
+		"Unknown checkbox is rejected": {
+			markdown:  "- [~] Item\n",
+			expectErr: "line 1: invalid checkbox",
+		},
+		"Missing title is rejected": {
+			markdown:  "- [x]  \n- [ ] \n",
+			expectErr: "line 1:",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			items, err := ParseChecklist(tt.markdown)
+			if tt.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectItems, items)
+		})
+	}
+}
+
+func TestRenderChecklist_RoundTrip(t *testing.T) {
+	markdown := "- [ ] Groceries\n  - [x] Milk  and  honey\n  - [ ] Eggs\n    - [x] Free range\n- [x] Laundry\n"
+
+	items, err := ParseChecklist(markdown)
+	require.NoError(t, err)
+	assert.Equal(t, markdown, RenderChecklist(items))
+
+	rendered := RenderChecklist([]*ChecklistItem{{Title: "Two\nlines"}})
+	assert.Equal(t, "- [ ] Two lines\n", rendered)
+}
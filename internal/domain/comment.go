@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Comment is a message about a Todo written by one of the users who can see it
+// Only its author may edit or delete it, and it goes away with its Todo
+type Comment struct {
+	ID        string    `json:"id"`
+	TodoID    string    `json:"todo_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
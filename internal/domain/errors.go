@@ -0,0 +1,14 @@
+package domain
+
+import "errors"
+
+// ErrUserNotFound is returned by services when a user does not exist or is in the trash
+// Callers such as HTTP handlers check for it with errors.Is
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrPermissionDenied is returned by services when the principal of the context may not act on the data
+// It is distinct from ErrUserNotFound so that callers can tell a forbidden request from a missing entity
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrBlobTooLarge is returned by blob stores when content exceeds their size limit
+var ErrBlobTooLarge = errors.New("blob too large")
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewID generates a random identifier for entities created by the services
+// themselves rather than by a caller, such as the next occurrence of a recurring Todo
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("domain: cannot read random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
@@ -1,23 +1,159 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // User represents user information
+// A deleted user stays in the trash with DeletedAt set until it is purged
 type User struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsDeleted reports whether the user is in the trash
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// Priority represents how important a Todo is
+// Higher values are more important
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// IsValid reports whether the priority is one of the defined levels
+func (p Priority) IsValid() bool {
+	return p >= PriorityNone && p <= PriorityHigh
+}
+
+// String returns the name of the priority level
+func (p Priority) String() string {
+	switch p {
+	case PriorityNone:
+		return "none"
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
 }
 
 // Todo represents a Todo item
+// A recurring Todo carries its Recurrence, the SeriesID shared by all of its occurrences
+// and its 1-based Occurrence number in that series
+// An archived Todo is hidden from lists but, unlike a deleted one, is not headed for purging
+// A deleted Todo stays in the trash with DeletedAt set until it is purged
+// AssigneeID names the user responsible for a Todo, who does not have to be its owner
+// A new Todo is placed after its siblings unless PositionSet says its Position was chosen,
+// which lets a Todo be created at any position, 0 included; stores do not keep the flag
 type Todo struct {
+	ID          string      `json:"id"`
+	UserID      string      `json:"user_id"`
+	ProjectID   string      `json:"project_id,omitempty"`
+	AssigneeID  string      `json:"assignee_id,omitempty"`
+	ParentID    string      `json:"parent_id,omitempty"`
+	Position    float64     `json:"position"`
+	PositionSet bool        `json:"-"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Completed   bool        `json:"completed"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	Priority    Priority    `json:"priority"`
+	DueAt       *time.Time  `json:"due_at,omitempty"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty"`
+	SeriesID    string      `json:"series_id,omitempty"`
+	Occurrence  int         `json:"occurrence,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	ArchivedAt  *time.Time  `json:"archived_at,omitempty"`
+	DeletedAt   *time.Time  `json:"deleted_at,omitempty"`
+}
+
+// MaxSubtaskDepth is how deeply subtasks can be nested below a top-level Todo
+const MaxSubtaskDepth = 3
+
+// TodoNode is a Todo together with its subtasks, forming a tree
+type TodoNode struct {
+	Todo     *Todo       `json:"todo"`
+	Subtasks []*TodoNode `json:"subtasks"`
+}
+
+// RootTodos returns the Todos whose parent is not among todos, keeping their order
+// Stores delete and purge a Todo together with its subtasks, so only these need to be passed on
+func RootTodos(todos []*Todo) []*Todo {
+	present := make(map[string]struct{}, len(todos))
+	for _, todo := range todos {
+		present[todo.ID] = struct{}{}
+	}
+	roots := make([]*Todo, 0, len(todos))
+	for _, todo := range todos {
+		if _, ok := present[todo.ParentID]; !ok {
+			roots = append(roots, todo)
+		}
+	}
+	return roots
+}
+
+// IsDeleted reports whether the Todo is in the trash
+func (t *Todo) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
+
+// IsArchived reports whether the Todo has been archived
+func (t *Todo) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
+// IsOverdue reports whether the Todo is still incomplete after its due date
+func (t *Todo) IsOverdue(now time.Time) bool {
+	return !t.Completed && t.DueAt != nil && t.DueAt.Before(now)
+}
+
+// Tag represents a label a user attaches to their Todos
+// Tags and Todos are linked many-to-many, and tag names are unique per user
+type Tag struct {
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeTagName returns the canonical form of a tag name
+// so that "Work" and " work " refer to the same tag
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Project groups a user's Todos into a list such as "Work" or "Home"
+type Project struct {
 	ID          string    `json:"id"`
 	UserID      string    `json:"user_id"`
-	Title       string    `json:"title"`
+	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// ProjectDeleteMode decides what happens to a project's Todos when the project is deleted
+type ProjectDeleteMode int
+
+const (
+	// ProjectDeleteDetach keeps the Todos and only removes them from the project
+	ProjectDeleteDetach ProjectDeleteMode = iota
+	// ProjectDeleteCascade deletes the Todos together with the project
+	ProjectDeleteCascade
+)
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Placement says where a Todo is moved to among its siblings
+type Placement int
+
+const (
+	PlaceTop Placement = iota
+	PlaceBottom
+	PlaceBefore
+	PlaceAfter
+)
+
+// TodoMove describes where to move a Todo
+// TargetID names the sibling to move before or after, and is ignored for PlaceTop and PlaceBottom
+type TodoMove struct {
+	Placement Placement
+	TargetID  string
+}
+
+// PlanMove computes the positions to write so that the Todo with the given id ends up where move says
+// siblings must be sorted by position and include the moved Todo itself
+// Usually only the moved Todo gets a new fractional position between its new neighbors,
+// but when two neighbors are too close to fit another position all siblings are renumbered
+func PlanMove(siblings []*Todo, id string, move TodoMove) (map[string]float64, error) {
+	var moved *Todo
+	others := make([]*Todo, 0, len(siblings))
+	for _, sibling := range siblings {
+		if sibling.ID == id {
+			moved = sibling
+			continue
+		}
+		others = append(others, sibling)
+	}
+	if moved == nil {
+		return nil, fmt.Errorf("todo is not among its siblings: %s", id)
+	}
+
+	index, err := insertionIndex(others, move)
+	if err != nil {
+		return nil, err
+	}
+
+	var position float64
+	switch {
+	case len(others) == 0:
+		position = 1
+	case index == 0:
+		position = others[0].Position - 1
+	case index == len(others):
+		position = others[len(others)-1].Position + 1
+	default:
+		lo, hi := others[index-1].Position, others[index].Position
+		position = lo + (hi-lo)/2
+		if position <= lo || position >= hi {
+			return renumber(others, moved, index), nil
+		}
+	}
+	return map[string]float64{moved.ID: position}, nil
+}
+
+// insertionIndex returns where the moved Todo goes in others
+func insertionIndex(others []*Todo, move TodoMove) (int, error) {
+	switch move.Placement {
+	case PlaceTop:
+		return 0, nil
+	case PlaceBottom:
+		return len(others), nil
+	case PlaceBefore, PlaceAfter:
+		if move.TargetID == "" {
+			return 0, errors.New("a target todo is required to move before or after")
+		}
+		for i, other := range others {
+			if other.ID != move.TargetID {
+				continue
+			}
+			if move.Placement == PlaceAfter {
+				return i + 1, nil
+			}
+			return i, nil
+		}
+		return 0, fmt.Errorf("target todo is not a sibling: %s", move.TargetID)
+	default:
+		return 0, fmt.Errorf("unknown placement: %d", move.Placement)
+	}
+}
+
+// renumber assigns positions 1..n to all siblings with the moved Todo inserted at index,
+// returning only the positions that change
+func renumber(others []*Todo, moved *Todo, index int) map[string]float64 {
+	ordered := make([]*Todo, 0, len(others)+1)
+	ordered = append(ordered, others[:index]...)
+	ordered = append(ordered, moved)
+	ordered = append(ordered, others[index:]...)
+
+	positions := make(map[string]float64)
+	for i, todo := range ordered {
+		if position := float64(i + 1); todo.Position != position {
+			positions[todo.ID] = position
+		}
+	}
+	return positions
+}
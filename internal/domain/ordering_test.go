@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanMove(t *testing.T) {
+	siblings := []*Todo{
+		{ID: "a", Position: 1},
+		{ID: "b", Position: 2},
+		{ID: "c", Position: 3},
+	}
+
+	tests := map[string]struct {
+		siblings        []*Todo
+		id              string
+		move            TodoMove
+		expectPositions map[string]float64
+		expectErr       bool
+	}{
+		"Move to top": {
+			siblings:        siblings,
+			id:              "c",
+			move:            TodoMove{Placement: PlaceTop},
+			expectPositions: map[string]float64{"c": 0},
+		},
+		"Move to bottom": {
+			siblings:        siblings,
+			id:              "a",
+			move:            TodoMove{Placement: PlaceBottom},
+			expectPositions: map[string]float64{"a": 4},
+		},
+		"Move before a sibling": {
+			siblings:        siblings,
+			id:              "c",
+			move:            TodoMove{Placement: PlaceBefore, TargetID: "b"},
+			expectPositions: map[string]float64{"c": 1.5},
+		},
+		"Move after a sibling": {
+			siblings:        siblings,
+			id:              "a",
+			move:            TodoMove{Placement: PlaceAfter, TargetID: "b"},
+			expectPositions: map[string]float64{"a": 2.5},
+		},
+		"Renumber when positions run out of precision": {
+			siblings: []*Todo{
+				{ID: "a", Position: 1},
+				{ID: "b", Position: 1},
+				{ID: "c", Position: 3},
+			},
+			id:              "c",
+			move:            TodoMove{Placement: PlaceAfter, TargetID: "a"},
+			expectPositions: map[string]float64{"c": 2, "b": 3},
+		},
+		"Error: Target is not a sibling": {
+			siblings:  siblings,
+			id:        "a",
+			move:      TodoMove{Placement: PlaceBefore, TargetID: "z"},
+			expectErr: true,
+		},
+		"Error: Moved todo is not a sibling": {
+			siblings:  siblings,
+			id:        "z",
+			move:      TodoMove{Placement: PlaceTop},
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			positions, err := PlanMove(tt.siblings, tt.id, tt.move)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectPositions, positions)
+		})
+	}
+}
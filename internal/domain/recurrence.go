@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a recurring Todo repeats
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+// Recurrence describes how a Todo repeats
+// It supports the subset of RFC 5545 RRULE made of FREQ (DAILY, WEEKLY, MONTHLY),
+// INTERVAL, BYDAY (weekly only, without ordinals), UNTIL and COUNT
+type Recurrence struct {
+	Frequency Frequency      `json:"frequency"`
+	Interval  int            `json:"interval,omitempty"`
+	Weekdays  []time.Weekday `json:"weekdays,omitempty"`
+	Until     *time.Time     `json:"until,omitempty"`
+	Count     int            `json:"count,omitempty"`
+}
+
+// maxMonthlySkips bounds the search for a month that has the wanted day,
+// e.g. the next February 29th
+const maxMonthlySkips = 100
+
+var weekdayCodes = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// Validate checks that the recurrence is within the supported RRULE subset
+func (r *Recurrence) Validate() error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	default:
+		return fmt.Errorf("unsupported recurrence frequency: %q", r.Frequency)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("recurrence interval must not be negative: %d", r.Interval)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("recurrence count must not be negative: %d", r.Count)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("recurrence cannot have both an end date and a count")
+	}
+	if len(r.Weekdays) > 0 && r.Frequency != FrequencyWeekly {
+		return errors.New("recurrence weekdays are only supported for weekly frequency")
+	}
+	for _, day := range r.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid recurrence weekday: %d", day)
+		}
+	}
+	return nil
+}
+
+// NextOccurrence returns the due date of the occurrence following the one due at due
+// occurrence is the 1-based position of that current occurrence in the series
+// The second return value is false when the series has ended
+func (r *Recurrence) NextOccurrence(due time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var next time.Time
+	switch r.Frequency {
+	case FrequencyDaily:
+		next = due.AddDate(0, 0, interval)
+	case FrequencyWeekly:
+		next = r.nextWeekly(due, interval)
+	case FrequencyMonthly:
+		var ok bool
+		if next, ok = nextMonthly(due, interval); !ok {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// nextWeekly moves to the next listed weekday of the current week,
+// or to the first listed weekday interval weeks later (weeks start on Monday)
+func (r *Recurrence) nextWeekly(due time.Time, interval int) time.Time {
+	if len(r.Weekdays) == 0 {
+		return due.AddDate(0, 0, 7*interval)
+	}
+
+	offsets := make([]int, 0, len(r.Weekdays))
+	for _, day := range r.Weekdays {
+		offsets = append(offsets, mondayOffset(day))
+	}
+	sort.Ints(offsets)
+
+	current := mondayOffset(due.Weekday())
+	for _, offset := range offsets {
+		if offset > current {
+			return due.AddDate(0, 0, offset-current)
+		}
+	}
+	weekStart := due.AddDate(0, 0, -current)
+	return weekStart.AddDate(0, 0, 7*interval+offsets[0])
+}
+
+// nextMonthly keeps the day of month, skipping months that do not have it as RFC 5545 does
+func nextMonthly(due time.Time, interval int) (time.Time, bool) {
+	year, month, day := due.Date()
+	hour, minute, sec := due.Clock()
+	for i := 1; i <= maxMonthlySkips; i++ {
+		next := time.Date(year, month+time.Month(interval*i), day, hour, minute, sec, due.Nanosecond(), due.Location())
+		if next.Day() == day {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// String formats the recurrence as an RFC 5545 RRULE value
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, 0, len(r.Weekdays))
+		for _, day := range r.Weekdays {
+			days = append(days, weekdayCodes[day])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRRule parses an RFC 5545 RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"
+// An optional "RRULE:" prefix is accepted
+func ParseRRule(rule string) (*Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, errors.New("empty RRULE")
+	}
+
+	r := &Recurrence{}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed RRULE part: %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Frequency = Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseRRuleTime(value)
+			r.Until = &until
+		case "BYDAY":
+			r.Weekdays, err = parseWeekdays(value)
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part: %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE %s: %w", key, err)
+		}
+	}
+
+	if r.Frequency == "" {
+		return nil, errors.New("RRULE must contain FREQ")
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date format: %q", value)
+}
+
+func parseWeekdays(value string) ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, 7)
+	for _, code := range strings.Split(value, ",") {
+		found := false
+		for day, c := range weekdayCodes {
+			if strings.EqualFold(code, c) {
+				days = append(days, day)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unsupported weekday: %q", code)
+		}
+	}
+	return days, nil
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrence_NextOccurrence(t *testing.T) {
+	// 2024-01-31 is a Wednesday
+	due := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		recurrence *Recurrence
+		occurrence int
+		expectNext time.Time
+		expectOK   bool
+	}{
+		"Daily with interval": {
+			recurrence: &Recurrence{Frequency: FrequencyDaily, Interval: 3},
+			occurrence: 1,
+			expectNext: time.Date(2024, 2, 3, 9, 30, 0, 0, time.UTC),
+			expectOK:   true,
+		},
+		"Weekly without weekdays": {
+			recurrence: &Recurrence{Frequency: FrequencyWeekly},
+			occurrence: 1,
+			expectNext: time.Date(2024, 2, 7, 9, 30, 0, 0, time.UTC),
+			expectOK:   true,
+		},
+		"Weekly moves to a later weekday in the same week": {
+			recurrence: &Recurrence{Frequency: FrequencyWeekly, Weekdays: []time.Weekday{time.Monday, time.Friday}},
+			occurrence: 1,
+			expectNext: time.Date(2024, 2, 2, 9, 30, 0, 0, time.UTC),
+			expectOK:   true,
+		},
+		"Biweekly wraps to the first weekday after the interval": {
+			recurrence: &Recurrence{Frequency: FrequencyWeekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Tuesday}},
+			occurrence: 1,
+			expectNext: time.Date(2024, 2, 12, 9, 30, 0, 0, time.UTC),
+			expectOK:   true,
+		},
+		"Monthly skips months without the day": {
+			recurrence: &Recurrence{Frequency: FrequencyMonthly},
+			occurrence: 1,
+			expectNext: time.Date(2024, 3, 31, 9, 30, 0, 0, time.UTC),
+			expectOK:   true,
+		},
+		"Series ends after count": {
+			recurrence: &Recurrence{Frequency: FrequencyDaily, Count: 3},
+			occurrence: 3,
+			expectOK:   false,
+		},
+		"Series ends after until": {
+			recurrence: &Recurrence{Frequency: FrequencyWeekly, Until: &until},
+			occurrence: 1,
+			expectOK:   false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			next, ok := tt.recurrence.NextOccurrence(due, tt.occurrence)
+
+			assert.Equal(t, tt.expectOK, ok)
+			if tt.expectOK {
+				assert.Equal(t, tt.expectNext, next)
+			}
+		})
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := map[string]struct {
+		rule         string
+		expectString string
+		expectErr    bool
+	}{
+		"Weekly with weekdays and count": {
+			rule:         "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10",
+			expectString: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10",
+		},
+		"Monthly until a date": {
+			rule:         "FREQ=MONTHLY;UNTIL=20241231",
+			expectString: "FREQ=MONTHLY;UNTIL=20241231T000000Z",
+		},
+		"Error: Missing FREQ": {
+			rule:      "INTERVAL=2",
+			expectErr: true,
+		},
+		"Error: Unsupported frequency": {
+			rule:      "FREQ=YEARLY",
+			expectErr: true,
+		},
+		"Error: Both UNTIL and COUNT": {
+			rule:      "FREQ=DAILY;UNTIL=20241231;COUNT=3",
+			expectErr: true,
+		},
+		"Error: BYDAY on a daily rule": {
+			rule:      "FREQ=DAILY;BYDAY=MO",
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recurrence, err := ParseRRule(tt.rule)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectString, recurrence.String())
+		})
+	}
+}
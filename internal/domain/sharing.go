@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ShareRole is the access a share grants to the user a todo or project is shared with
+type ShareRole string
+
+const (
+	// ShareViewer may read the shared todo or project
+	ShareViewer ShareRole = "viewer"
+	// ShareEditor may also change it, but only its owner may delete or share it
+	ShareEditor ShareRole = "editor"
+)
+
+// IsValid reports whether the role is one of the defined roles
+func (r ShareRole) IsValid() bool {
+	return r == ShareViewer || r == ShareEditor
+}
+
+// Allows reports whether the role grants at least the access of required
+func (r ShareRole) Allows(required ShareRole) bool {
+	return r == required || r == ShareEditor && required == ShareViewer
+}
+
+// ShareResourceType is the kind of entity a share grants access to
+type ShareResourceType string
+
+const (
+	ShareResourceTodo    ShareResourceType = "todo"
+	ShareResourceProject ShareResourceType = "project"
+)
+
+// IsValid reports whether the resource type is one that can be shared
+func (t ShareResourceType) IsValid() bool {
+	return t == ShareResourceTodo || t == ShareResourceProject
+}
+
+// Share grants a user access to a todo or a project owned by another user
+// Sharing a project grants the same access to every todo in it
+type Share struct {
+	ResourceType ShareResourceType `json:"resource_type"`
+	ResourceID   string            `json:"resource_id"`
+	OwnerID      string            `json:"owner_id"`
+	UserID       string            `json:"user_id"`
+	Role         ShareRole         `json:"role"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
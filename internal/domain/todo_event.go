@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TodoEventType is the kind of change a TodoEvent records
+type TodoEventType string
+
+const (
+	TodoCreated     TodoEventType = "TodoCreated"
+	TodoUpdated     TodoEventType = "TodoUpdated"
+	TodoRenamed     TodoEventType = "TodoRenamed"
+	TodoCompleted   TodoEventType = "TodoCompleted"
+	TodoReopened    TodoEventType = "TodoReopened"
+	TodoDueDateSet  TodoEventType = "TodoDueDateSet"
+	TodoPrioritySet TodoEventType = "TodoPrioritySet"
+	TodoDeleted     TodoEventType = "TodoDeleted"
+	TodoRestored    TodoEventType = "TodoRestored"
+	TodoPurged      TodoEventType = "TodoPurged"
+)
+
+// TodoEvent is a single change to a Todo in an append-only event stream
+// Only the fields used by its type are set: Todo for TodoCreated and TodoUpdated,
+// Title for TodoRenamed, DueAt for TodoDueDateSet and Priority for TodoPrioritySet
+type TodoEvent struct {
+	Seq      int64         `json:"seq"`
+	Type     TodoEventType `json:"type"`
+	TodoID   string        `json:"todo_id"`
+	At       time.Time     `json:"at"`
+	Todo     *Todo         `json:"todo,omitempty"`
+	Title    string        `json:"title,omitempty"`
+	DueAt    *time.Time    `json:"due_at,omitempty"`
+	Priority Priority      `json:"priority,omitempty"`
+}
+
+// Apply returns the state of a Todo after the event without modifying todo
+// todo is nil before TodoCreated, and the result is nil after TodoPurged
+func (e TodoEvent) Apply(todo *Todo) (*Todo, error) {
+	if e.Type == TodoCreated {
+		if todo != nil {
+			return nil, fmt.Errorf("todo already exists: %s", e.TodoID)
+		}
+		if e.Todo == nil {
+			return nil, fmt.Errorf("%s event without todo: %s", e.Type, e.TodoID)
+		}
+		created := *e.Todo
+		return &created, nil
+	}
+	if todo == nil {
+		return nil, fmt.Errorf("todo not found: %s", e.TodoID)
+	}
+
+	at := e.At
+	next := *todo
+	switch e.Type {
+	case TodoUpdated:
+		if e.Todo == nil {
+			return nil, fmt.Errorf("%s event without todo: %s", e.Type, e.TodoID)
+		}
+		next = *e.Todo
+	case TodoRenamed:
+		next.Title = e.Title
+		next.UpdatedAt = at
+	case TodoCompleted:
+		next.Completed = true
+		next.CompletedAt = &at
+		next.UpdatedAt = at
+	case TodoReopened:
+		next.Completed = false
+		next.CompletedAt = nil
+		next.UpdatedAt = at
+	case TodoDueDateSet:
+		next.DueAt = e.DueAt
+		next.UpdatedAt = at
+	case TodoPrioritySet:
+		next.Priority = e.Priority
+		next.UpdatedAt = at
+	case TodoDeleted:
+		next.DeletedAt = &at
+	case TodoRestored:
+		next.DeletedAt = nil
+		next.UpdatedAt = at
+	case TodoPurged:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown todo event type: %s", e.Type)
+	}
+	return &next, nil
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoEvent_Apply(t *testing.T) {
+	createdAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	at := createdAt.Add(time.Hour)
+	events := []TodoEvent{
+		{Type: TodoCreated, TodoID: "todo1", At: createdAt, Todo: &Todo{ID: "todo1", UserID: "user1", Title: "Draft", CreatedAt: createdAt}},
+		{Type: TodoRenamed, TodoID: "todo1", At: at, Title: "Final"},
+		{Type: TodoPrioritySet, TodoID: "todo1", At: at, Priority: PriorityHigh},
+		{Type: TodoCompleted, TodoID: "todo1", At: at},
+		{Type: TodoDeleted, TodoID: "todo1", At: at},
+		{Type: TodoRestored, TodoID: "todo1", At: at},
+	}
+
+	var todo *Todo
+	for _, event := range events {
+		next, err := event.Apply(todo)
+		require.NoError(t, err)
+		todo = next
+	}
+
+	assert.Equal(t, "Final", todo.Title)
+	assert.Equal(t, PriorityHigh, todo.Priority)
+	assert.True(t, todo.Completed)
+	require.NotNil(t, todo.CompletedAt)
+	assert.Equal(t, at, *todo.CompletedAt)
+	assert.False(t, todo.IsDeleted())
+	assert.Equal(t, createdAt, todo.CreatedAt)
+
+	purged, err := TodoEvent{Type: TodoPurged, TodoID: "todo1", At: at}.Apply(todo)
+	require.NoError(t, err)
+	assert.Nil(t, purged)
+}
+
+func TestTodoEvent_ApplyDoesNotModifyState(t *testing.T) {
+	todo := &Todo{ID: "todo1", Title: "Draft"}
+
+	next, err := TodoEvent{Type: TodoRenamed, TodoID: "todo1", Title: "Final"}.Apply(todo)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Draft", todo.Title)
+	assert.Equal(t, "Final", next.Title)
+}
+
+func TestTodoEvent_ApplyErrors(t *testing.T) {
+	tests := map[string]struct {
+		event TodoEvent
+		todo  *Todo
+	}{
+		"Create existing todo": {
+			event: TodoEvent{Type: TodoCreated, TodoID: "todo1", Todo: &Todo{ID: "todo1"}},
+			todo:  &Todo{ID: "todo1"},
+		},
+		"Change missing todo": {
+			event: TodoEvent{Type: TodoCompleted, TodoID: "todo1"},
+			todo:  nil,
+		},
+		"Unknown event type": {
+			event: TodoEvent{Type: "TodoExploded", TodoID: "todo1"},
+			todo:  &Todo{ID: "todo1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tt.event.Apply(tt.todo)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Authenticator resolves a bearer token into the principal it authenticates
+// auth.TokenService satisfies it
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.Principal, error)
+}
+
+// bearerPrefix starts the Authorization header of a bearer token (RFC 6750)
+const bearerPrefix = "Bearer "
+
+// Authenticate serves requests carrying a valid bearer token with next, on behalf of the token's principal
+// Other requests are answered with 401 Unauthorized and a WWW-Authenticate challenge
+func Authenticate(authenticator Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		// The scheme is case-insensitive
+		if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			w.Header().Set("WWW-Authenticate", `Bearer`)
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(r.Context(), strings.TrimSpace(header[len(bearerPrefix):]))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="`+err.Error()+`"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
@@ -0,0 +1,12 @@
+package httpapi
+
+import "net/http"
+
+// NewRouter routes requests to the API handlers
+// Paths are matched by prefix and each handler parses the rest of its path itself
+// Every route acts on the tenant named by TenantHeader and requires a bearer token that authenticator accepts within it
+func NewRouter(authenticator Authenticator, todoEvents *TodoEventsHandler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/users/", todoEvents)
+	return Tenant(Authenticate(authenticator, mux))
+}
@@ -0,0 +1,28 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// TenantHeader names the tenant a request acts on
+// Requests without it act on tenant.Default
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant serves requests with next in the context of the tenant named by their TenantHeader
+// It goes before Authenticate, so that tokens are only valid within the tenant they were issued in
+// Requests naming an invalid tenant are answered with 400 Bad Request
+func Tenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(TenantHeader)
+		if id == "" {
+			id = tenant.Default
+		}
+		if err := tenant.Validate(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), id)))
+	})
+}
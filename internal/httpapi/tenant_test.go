@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+func TestTenant(t *testing.T) {
+	acme := auth.WithSystem(tenant.WithID(context.Background(), "acme"))
+	store := inmemory.NewStore()
+	require.NoError(t, store.CreateUser(acme, &domain.User{ID: "user1", Name: "Test User"}))
+	tokens := auth.NewTokenService(store, store)
+	token, _, err := tokens.IssueToken(acme, "user1", domain.RoleUser, 0)
+	require.NoError(t, err)
+
+	// The handler echoes the tenant it was called in
+	handler := Tenant(Authenticate(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, tenant.FromContext(r.Context()))
+	})))
+
+	tests := map[string]struct {
+		tenantID     string
+		expectStatus int
+		expectBody   string
+	}{
+		"Success: Tenant the token was issued in": {
+			tenantID:     "acme",
+			expectStatus: http.StatusOK,
+			expectBody:   "acme",
+		},
+		"Error: Another tenant": {
+			tenantID:     "globex",
+			expectStatus: http.StatusUnauthorized,
+		},
+		"Error: No tenant acts on the default one": {
+			expectStatus: http.StatusUnauthorized,
+		},
+		"Error: Invalid tenant": {
+			tenantID:     "../acme",
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/user1/todos/events", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.tenantID != "" {
+				req.Header.Set(TenantHeader, tt.tenantID)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectStatus, rec.Code)
+			if tt.expectStatus == http.StatusOK {
+				assert.Equal(t, tt.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package httpapi exposes the services over HTTP
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/changefeed"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// DefaultHeartbeat is how often an idle event stream sends a comment to keep proxies from closing it
+const DefaultHeartbeat = 15 * time.Second
+
+// TodoWatcher streams changes to a user's Todos
+// Both the big and the small interface WatchService satisfy it
+type TodoWatcher interface {
+	WatchUserTodos(ctx context.Context, userID string, afterRevision int64) (<-chan domain.Change, error)
+}
+
+// TodoEventsHandler serves GET /users/{id}/todos/events as a Server-Sent Events stream
+// Each change is sent with its revision as the event ID and "todo.<kind>" as the event name,
+// so a reconnecting EventSource resumes through the Last-Event-ID header
+type TodoEventsHandler struct {
+	watcher   TodoWatcher
+	heartbeat time.Duration
+}
+
+// NewTodoEventsHandler creates a new TodoEventsHandler
+// A heartbeat of zero or less uses DefaultHeartbeat
+func NewTodoEventsHandler(watcher TodoWatcher, heartbeat time.Duration) *TodoEventsHandler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+	return &TodoEventsHandler{
+		watcher:   watcher,
+		heartbeat: heartbeat,
+	}
+}
+
+func (h *TodoEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseTodoEventsPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var afterRevision int64
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		revision, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || revision < 0 {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		afterRevision = revision
+	}
+
+	changes, err := h.watcher.WatchUserTodos(r.Context(), userID, afterRevision)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrPermissionDenied):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, changefeed.ErrRevisionCompacted):
+			// The client missed changes that are gone, so it has to reload before watching again
+			http.Error(w, err.Error(), http.StatusGone)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case change, ok := <-changes:
+			// A closed channel means the client fell behind; it reconnects with Last-Event-ID
+			if !ok {
+				return
+			}
+			if err := writeChange(w, change); err != nil {
+				log.Printf("todo events: write change %d: %v", change.Revision, err)
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeChange writes a change as a single SSE event
+func writeChange(w http.ResponseWriter, change domain.Change) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: todo.%s\ndata: %s\n\n", change.Revision, change.Kind, data)
+	return err
+}
+
+// parseTodoEventsPath extracts the user ID from /users/{id}/todos/events
+func parseTodoEventsPath(path string) (string, bool) {
+	rest := strings.TrimPrefix(path, "/users/")
+	if rest == path {
+		return "", false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != "todos" || parts[2] != "events" {
+		return "", false
+	}
+	return parts[0], true
+}
@@ -0,0 +1,220 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	smallservice "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/services/smallinterface"
+)
+
+// sseEvent is a single event read from the stream
+// Heartbeats have only a comment
+type sseEvent struct {
+	id      string
+	name    string
+	data    string
+	comment string
+}
+
+// newTestServer starts a server with user1 and returns it with a token authenticating as user1
+func newTestServer(t *testing.T, heartbeat time.Duration) (*httptest.Server, *inmemory.Store, string) {
+	t.Helper()
+	store := inmemory.NewStore()
+	require.NoError(t, store.CreateUser(context.Background(), &domain.User{ID: "user1", Name: "Test User"}))
+	tokens := auth.NewTokenService(store, store)
+	token, _, err := tokens.IssueToken(auth.WithSystem(context.Background()), "user1", domain.RoleUser, 0)
+	require.NoError(t, err)
+
+	handler := NewTodoEventsHandler(smallservice.NewWatchService(store, store), heartbeat)
+	server := httptest.NewServer(NewRouter(tokens, handler))
+	t.Cleanup(server.Close)
+	return server, store, token
+}
+
+// openStream starts a request and waits for the response headers,
+// after which the watch is registered and no change can be missed
+func openStream(t *testing.T, ctx context.Context, url, token, lastEventID string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// readEvents reads n events from the stream, skipping heartbeats unless withHeartbeats is set
+func readEvents(t *testing.T, r *bufio.Reader, n int, withHeartbeats bool) []sseEvent {
+	t.Helper()
+	events := make([]sseEvent, 0, n)
+	var event sseEvent
+	for len(events) < n {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "":
+			if event.comment == "" || withHeartbeats {
+				events = append(events, event)
+			}
+			event = sseEvent{}
+		case strings.HasPrefix(line, ":"):
+			event.comment = strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	return events
+}
+
+func TestTodoEventsHandler_StreamsChangesInOrder(t *testing.T) {
+	server, store, token := newTestServer(t, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := openStream(t, ctx, server.URL+"/users/user1/todos/events", token, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "First"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", Title: "Second"}))
+	require.NoError(t, store.MarkTodoComplete(ctx, "todo1"))
+	require.NoError(t, store.DeleteTodo(ctx, "todo2"))
+
+	events := readEvents(t, bufio.NewReader(resp.Body), 4, false)
+
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, event.name)
+	}
+	assert.Equal(t, []string{"todo.created", "todo.created", "todo.completed", "todo.deleted"}, names)
+	// User1 was created first, so todo changes start at revision 2
+	assert.Equal(t, []string{"2", "3", "4", "5"}, []string{events[0].id, events[1].id, events[2].id, events[3].id})
+
+	var change domain.Change
+	require.NoError(t, json.Unmarshal([]byte(events[2].data), &change))
+	assert.Equal(t, "todo1", change.EntityID)
+	require.NotNil(t, change.Todo)
+	assert.True(t, change.Todo.Completed)
+}
+
+func TestTodoEventsHandler_ResumesFromLastEventID(t *testing.T) {
+	server, store, token := newTestServer(t, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "First"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", Title: "Second"}))
+	require.NoError(t, store.MarkTodoComplete(ctx, "todo2"))
+
+	resp := openStream(t, ctx, server.URL+"/users/user1/todos/events", token, "2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+
+	events := readEvents(t, bufio.NewReader(resp.Body), 3, false)
+
+	assert.Equal(t, "3", events[0].id)
+	assert.Equal(t, "todo.created", events[0].name)
+	assert.Equal(t, "4", events[1].id)
+	assert.Equal(t, "todo.completed", events[1].name)
+	assert.Equal(t, "5", events[2].id)
+	assert.Equal(t, "todo.deleted", events[2].name)
+}
+
+func TestTodoEventsHandler_SendsHeartbeats(t *testing.T) {
+	server, _, token := newTestServer(t, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := openStream(t, ctx, server.URL+"/users/user1/todos/events", token, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	events := readEvents(t, bufio.NewReader(resp.Body), 2, true)
+
+	assert.Equal(t, "heartbeat", events[0].comment)
+	assert.Equal(t, "heartbeat", events[1].comment)
+}
+
+func TestTodoEventsHandler_Errors(t *testing.T) {
+	server, store, token := newTestServer(t, time.Hour)
+	adminToken, _, err := auth.NewTokenService(store, store).IssueToken(auth.WithSystem(context.Background()), "user1", domain.RoleAdmin, 0)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		method       string
+		path         string
+		admin        bool
+		lastEventID  string
+		expectStatus int
+	}{
+		"User not found": {
+			method:       http.MethodGet,
+			path:         "/users/nonexistent/todos/events",
+			admin:        true,
+			expectStatus: http.StatusNotFound,
+		},
+		"Another user's events": {
+			method:       http.MethodGet,
+			path:         "/users/user2/todos/events",
+			expectStatus: http.StatusForbidden,
+		},
+		"Invalid Last-Event-ID": {
+			method:       http.MethodGet,
+			path:         "/users/user1/todos/events",
+			lastEventID:  "abc",
+			expectStatus: http.StatusBadRequest,
+		},
+		"Unknown path": {
+			method:       http.MethodGet,
+			path:         "/users/user1/todos",
+			expectStatus: http.StatusNotFound,
+		},
+		"Method not allowed": {
+			method:       http.MethodPost,
+			path:         "/users/user1/todos/events",
+			expectStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			require.NoError(t, err)
+			if tt.admin {
+				req.Header.Set("Authorization", "Bearer "+adminToken)
+			} else {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectStatus, resp.StatusCode)
+		})
+	}
+}
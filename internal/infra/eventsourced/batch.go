@@ -0,0 +1,67 @@
+package eventsourced
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Batch operations
+// The events of a batch are appended to the log at once, or left to the transaction it is part of
+func (s *Store) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	ids := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	return s.batch(ctx, ids, func(ctx context.Context, i int) error {
+		return s.CreateTodo(ctx, todos[i])
+	})
+}
+
+func (s *Store) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	ids := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	return s.batch(ctx, ids, func(ctx context.Context, i int) error {
+		return s.UpdateTodo(ctx, todos[i])
+	})
+}
+
+func (s *Store) DeleteTodos(ctx context.Context, ids []string) error {
+	return s.batch(ctx, ids, func(ctx context.Context, i int) error {
+		return s.DeleteTodo(ctx, ids[i])
+	})
+}
+
+func (s *Store) MarkTodosComplete(ctx context.Context, ids []string) error {
+	return s.batch(ctx, ids, func(ctx context.Context, i int) error {
+		return s.MarkTodoComplete(ctx, ids[i])
+	})
+}
+
+// batch runs fn for every item, collecting the failures in a *domain.BatchError
+// Outside a transaction the items run in one of their own, and when appending their
+// events fails the projection is put back the way it was before the batch
+func (s *Store) batch(ctx context.Context, ids []string, fn func(ctx context.Context, i int) error) error {
+	txCtx, tx := s.begin(ctx)
+	defer tx.end()
+
+	var batchErr domain.BatchError
+	for i, id := range ids {
+		if err := fn(txCtx, i); err != nil {
+			batchErr.Add(i, id, err)
+		}
+	}
+
+	events := tx.events
+	if err := tx.commit(ctx); err != nil {
+		for _, event := range events {
+			if resyncErr := s.set(ctx, tx.stream, event.TodoID, tx.state[event.TodoID]); resyncErr != nil {
+				return resyncErr
+			}
+		}
+		return err
+	}
+	return batchErr.Err()
+}
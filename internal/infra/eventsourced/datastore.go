@@ -0,0 +1,125 @@
+package eventsourced
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// DataStore serves the todo operations of a DataStore from an event-sourced Store
+// Every other operation is passed to the wrapped DataStore, which is usually also
+// the Store's projection so that tags and projects see the same todos
+type DataStore struct {
+	biginterface.DataStore
+	todos *Store
+}
+
+var _ biginterface.DataStore = (*DataStore)(nil)
+
+// NewDataStore creates a DataStore whose todo operations go through todos
+func NewDataStore(store biginterface.DataStore, todos *Store) *DataStore {
+	return &DataStore{
+		DataStore: store,
+		todos:     todos,
+	}
+}
+
+// Todo-related operations
+func (s *DataStore) GetTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	return s.todos.GetTodo(ctx, id)
+}
+
+func (s *DataStore) ListTodos(ctx context.Context) ([]*domain.Todo, error) {
+	return s.todos.ListTodos(ctx)
+}
+
+func (s *DataStore) ListUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	return s.todos.ListUserTodos(ctx, userID)
+}
+
+func (s *DataStore) CreateTodo(ctx context.Context, todo *domain.Todo) error {
+	return s.todos.CreateTodo(ctx, todo)
+}
+
+func (s *DataStore) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
+	return s.todos.UpdateTodo(ctx, todo)
+}
+
+func (s *DataStore) DeleteTodo(ctx context.Context, id string) error {
+	return s.todos.DeleteTodo(ctx, id)
+}
+
+func (s *DataStore) MarkTodoComplete(ctx context.Context, id string) error {
+	return s.todos.MarkTodoComplete(ctx, id)
+}
+
+func (s *DataStore) ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error) {
+	return s.todos.ListSubtasks(ctx, parentID)
+}
+
+func (s *DataStore) ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	return s.todos.ListArchivedTodos(ctx, userID)
+}
+
+func (s *DataStore) ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error) {
+	return s.todos.ListAssignedTodos(ctx, assigneeID)
+}
+
+func (s *DataStore) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	return s.todos.SetTodoDueDate(ctx, id, dueAt)
+}
+
+func (s *DataStore) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	return s.todos.SetTodoPriority(ctx, id, priority)
+}
+
+func (s *DataStore) ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error) {
+	return s.todos.ListOverdueTodos(ctx, userID, now)
+}
+
+func (s *DataStore) ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error) {
+	return s.todos.ListTodosDueBetween(ctx, userID, from, to)
+}
+
+func (s *DataStore) ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	return s.todos.ListUserTodosByPriority(ctx, userID)
+}
+
+func (s *DataStore) GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	return s.todos.GetDeletedTodo(ctx, id)
+}
+
+func (s *DataStore) ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	return s.todos.ListDeletedTodos(ctx, userID)
+}
+
+func (s *DataStore) RestoreTodo(ctx context.Context, id string) error {
+	return s.todos.RestoreTodo(ctx, id)
+}
+
+func (s *DataStore) PurgeTodo(ctx context.Context, id string) error {
+	return s.todos.PurgeTodo(ctx, id)
+}
+
+func (s *DataStore) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	return s.todos.PurgeTodosDeletedBefore(ctx, cutoff)
+}
+
+// Batch operations
+func (s *DataStore) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	return s.todos.CreateTodos(ctx, todos)
+}
+
+func (s *DataStore) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	return s.todos.UpdateTodos(ctx, todos)
+}
+
+func (s *DataStore) DeleteTodos(ctx context.Context, ids []string) error {
+	return s.todos.DeleteTodos(ctx, ids)
+}
+
+func (s *DataStore) MarkTodosComplete(ctx context.Context, ids []string) error {
+	return s.todos.MarkTodosComplete(ctx, ids)
+}
@@ -0,0 +1,103 @@
+package eventsourced
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// EventLog is an append-only log of todo events, holding a stream for each tenant
+// Append and the loads act on the stream of the tenant of ctx
+// Events are returned in the order of their sequence numbers
+type EventLog interface {
+	Append(ctx context.Context, events []domain.TodoEvent) error
+	// Load returns every event with a sequence number greater than after
+	Load(ctx context.Context, after int64) ([]domain.TodoEvent, error)
+	LoadTodo(ctx context.Context, todoID string) ([]domain.TodoEvent, error)
+	// Tenants returns the IDs of the tenants with events, in no particular order
+	Tenants(ctx context.Context) ([]string, error)
+}
+
+// Snapshot is the state of every todo up to and including event Seq
+type Snapshot struct {
+	Seq     int64
+	TakenAt time.Time
+	Todos   []*domain.Todo
+}
+
+// SnapshotStore keeps the latest snapshot of each tenant so that startup does not replay the whole log
+// Its methods act on the snapshot of the tenant of ctx
+type SnapshotStore interface {
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+	// LoadSnapshot returns nil without an error when no snapshot has been saved
+	LoadSnapshot(ctx context.Context) (*Snapshot, error)
+}
+
+// MemoryLog is an EventLog held in memory
+type MemoryLog struct {
+	events map[string][]domain.TodoEvent
+}
+
+var _ EventLog = (*MemoryLog)(nil)
+
+// NewMemoryLog creates an empty in-memory event log
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{events: make(map[string][]domain.TodoEvent)}
+}
+
+func (l *MemoryLog) Append(ctx context.Context, events []domain.TodoEvent) error {
+	id := tenant.FromContext(ctx)
+	l.events[id] = append(l.events[id], events...)
+	return nil
+}
+
+func (l *MemoryLog) Load(ctx context.Context, after int64) ([]domain.TodoEvent, error) {
+	events := make([]domain.TodoEvent, 0)
+	for _, event := range l.events[tenant.FromContext(ctx)] {
+		if event.Seq > after {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (l *MemoryLog) LoadTodo(ctx context.Context, todoID string) ([]domain.TodoEvent, error) {
+	events := make([]domain.TodoEvent, 0)
+	for _, event := range l.events[tenant.FromContext(ctx)] {
+		if event.TodoID == todoID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (l *MemoryLog) Tenants(ctx context.Context) ([]string, error) {
+	tenants := make([]string, 0, len(l.events))
+	for id := range l.events {
+		tenants = append(tenants, id)
+	}
+	return tenants, nil
+}
+
+// MemorySnapshots is a SnapshotStore held in memory
+type MemorySnapshots struct {
+	latest map[string]*Snapshot
+}
+
+var _ SnapshotStore = (*MemorySnapshots)(nil)
+
+// NewMemorySnapshots creates an in-memory snapshot store without a snapshot
+func NewMemorySnapshots() *MemorySnapshots {
+	return &MemorySnapshots{latest: make(map[string]*Snapshot)}
+}
+
+func (s *MemorySnapshots) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	s.latest[tenant.FromContext(ctx)] = snapshot
+	return nil
+}
+
+func (s *MemorySnapshots) LoadSnapshot(ctx context.Context) (*Snapshot, error) {
+	return s.latest[tenant.FromContext(ctx)], nil
+}
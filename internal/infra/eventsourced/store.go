@@ -0,0 +1,432 @@
+// Package eventsourced provides a todo store whose state is derived from an append-only event log
+package eventsourced
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// Projection is the read model the store keeps up to date with every event
+// Queries are answered by the projection, changes only ever come from the log
+type Projection interface {
+	smallinterface.TodoStore
+	PutTodo(ctx context.Context, todo *domain.Todo) error
+	RemoveTodo(ctx context.Context, id string) error
+}
+
+// Store is a TodoStore that records every change as an event before applying it
+// Read operations are served by the projection
+// Each tenant has a stream of events of its own, and the operations act on the stream of the tenant of ctx
+type Store struct {
+	smallinterface.TodoStore
+
+	log        EventLog
+	snapshots  SnapshotStore
+	projection Projection
+
+	mu      sync.Mutex
+	tenants map[string]*stream
+	now     func() time.Time
+}
+
+// stream is the state of the todos of a tenant, derived from its events up to and including seq
+type stream struct {
+	seq   int64
+	state map[string]*domain.Todo
+}
+
+var _ smallinterface.TodoStore = (*Store)(nil)
+
+// NewStore creates a Store, loading the latest snapshot of every tenant of the log and replaying the events after it
+// snapshots may be nil, in which case the whole log is replayed
+// projection must not hold any todos yet
+func NewStore(ctx context.Context, log EventLog, snapshots SnapshotStore, projection Projection) (*Store, error) {
+	s := &Store{
+		TodoStore:  projection,
+		log:        log,
+		snapshots:  snapshots,
+		projection: projection,
+		tenants:    make(map[string]*stream),
+		now:        time.Now,
+	}
+
+	tenants, err := log.Tenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+	// The default tenant is loaded even without events, as its snapshot may hold them all
+	for _, id := range append([]string{tenant.Default}, tenants...) {
+		if _, ok := s.tenants[id]; ok {
+			continue
+		}
+		if err := s.load(tenant.WithID(ctx, id)); err != nil {
+			return nil, fmt.Errorf("load tenant %s: %w", id, err)
+		}
+	}
+	return s, nil
+}
+
+// load restores the stream of the tenant of ctx from its latest snapshot and the events after it
+func (s *Store) load(ctx context.Context) error {
+	st := s.stream(tenant.FromContext(ctx))
+	if s.snapshots != nil {
+		snapshot, err := s.snapshots.LoadSnapshot(ctx)
+		if err != nil {
+			return fmt.Errorf("load snapshot: %w", err)
+		}
+		if snapshot != nil {
+			for _, todo := range snapshot.Todos {
+				if err := s.put(ctx, st, todo); err != nil {
+					return err
+				}
+			}
+			st.seq = snapshot.Seq
+		}
+	}
+	return s.replay(ctx, st)
+}
+
+// Rebuild discards the derived state of the tenant of ctx and replays its events from the first one
+func (s *Store) Rebuild(ctx context.Context) error {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	for id := range st.state {
+		if err := s.projection.RemoveTodo(ctx, id); err != nil {
+			return err
+		}
+	}
+	st.state = make(map[string]*domain.Todo)
+	st.seq = 0
+	return s.replay(ctx, st)
+}
+
+// Snapshot saves the current state of the tenant of ctx so that the next startup only replays newer events
+func (s *Store) Snapshot(ctx context.Context) error {
+	if s.snapshots == nil {
+		return fmt.Errorf("snapshots are not configured")
+	}
+
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	todos := make([]*domain.Todo, 0, len(st.state))
+	for _, todo := range st.state {
+		copied := *todo
+		todos = append(todos, &copied)
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].ID < todos[j].ID
+	})
+	return s.snapshots.SaveSnapshot(ctx, &Snapshot{Seq: st.seq, TakenAt: s.now(), Todos: todos})
+}
+
+// History returns every event of a todo of the tenant of ctx, oldest first
+// The history remains available after the todo is purged
+func (s *Store) History(ctx context.Context, todoID string) ([]domain.TodoEvent, error) {
+	return s.log.LoadTodo(ctx, todoID)
+}
+
+// CreateTodo appends the todo after its siblings unless its position is set
+func (s *Store) CreateTodo(ctx context.Context, todo *domain.Todo) error {
+	if todo.ID == "" {
+		return fmt.Errorf("todo ID cannot be empty")
+	}
+
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	if !todo.PositionSet {
+		todo.Position = st.lastPosition(todo.UserID, todo.ParentID) + 1
+	}
+	todo.PositionSet = false
+	created := *todo
+	return s.commit(ctx, st, domain.TodoEvent{Type: domain.TodoCreated, TodoID: todo.ID, Todo: &created})
+}
+
+// UpdateTodo records the narrowest event that explains the change
+// Anything that is not a single rename, completion, reopening, due date or priority change
+// is recorded as TodoUpdated with the whole todo
+func (s *Store) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	current, err := st.live(todo.ID)
+	if err != nil {
+		return err
+	}
+	updated := *todo
+	return s.commit(ctx, st, updateEvent(current, &updated))
+}
+
+// DeleteTodo moves a todo to the trash together with its live subtasks
+// Their events are committed at once, so they share the time they were deleted
+func (s *Store) DeleteTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	if _, err := st.live(id); err != nil {
+		return err
+	}
+	events := []domain.TodoEvent{{Type: domain.TodoDeleted, TodoID: id}}
+	for _, todo := range st.descendants(id) {
+		if !todo.IsDeleted() {
+			events = append(events, domain.TodoEvent{Type: domain.TodoDeleted, TodoID: todo.ID})
+		}
+	}
+	return s.commit(ctx, st, events...)
+}
+
+func (s *Store) MarkTodoComplete(ctx context.Context, id string) error {
+	return s.change(ctx, id, domain.TodoEvent{Type: domain.TodoCompleted})
+}
+
+// Scheduling operations
+func (s *Store) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	return s.change(ctx, id, domain.TodoEvent{Type: domain.TodoDueDateSet, DueAt: dueAt})
+}
+
+func (s *Store) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	return s.change(ctx, id, domain.TodoEvent{Type: domain.TodoPrioritySet, Priority: priority})
+}
+
+// Trash operations
+func (s *Store) RestoreTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	if _, err := st.trashed(id); err != nil {
+		return err
+	}
+	return s.commit(ctx, st, domain.TodoEvent{Type: domain.TodoRestored, TodoID: id})
+}
+
+// PurgeTodo permanently removes a todo from the trash together with its subtasks
+// Their events stay in the log
+func (s *Store) PurgeTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	if _, err := st.trashed(id); err != nil {
+		return err
+	}
+	events := []domain.TodoEvent{{Type: domain.TodoPurged, TodoID: id}}
+	for _, todo := range st.descendants(id) {
+		events = append(events, domain.TodoEvent{Type: domain.TodoPurged, TodoID: todo.ID})
+	}
+	return s.commit(ctx, st, events...)
+}
+
+// PurgeTodosDeletedBefore permanently removes every todo that was moved to the trash before cutoff
+func (s *Store) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	purged := make(map[string]struct{})
+	for _, todo := range st.state {
+		if todo.IsDeleted() && todo.DeletedAt.Before(cutoff) {
+			purged[todo.ID] = struct{}{}
+			// Subtasks go with their parent, as with PurgeTodo
+			for _, descendant := range st.descendants(todo.ID) {
+				purged[descendant.ID] = struct{}{}
+			}
+		}
+	}
+	events := make([]domain.TodoEvent, 0, len(purged))
+	for id := range purged {
+		events = append(events, domain.TodoEvent{Type: domain.TodoPurged, TodoID: id})
+	}
+	// Sorted so that replaying the log purges in the same order
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].TodoID < events[j].TodoID
+	})
+	if len(events) == 0 {
+		return 0, nil
+	}
+	if err := s.commit(ctx, st, events...); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// change records an event for a live todo
+func (s *Store) change(ctx context.Context, id string, event domain.TodoEvent) error {
+	st, unlock := s.lock(ctx)
+	defer unlock()
+
+	if _, err := st.live(id); err != nil {
+		return err
+	}
+	event.TodoID = id
+	return s.commit(ctx, st, event)
+}
+
+// commit numbers the events, appends them to the log and applies them
+// Inside a transaction the events are held back until it commits
+// Every event is applied to a scratch copy first, so an invalid event is never logged
+func (s *Store) commit(ctx context.Context, st *stream, events ...domain.TodoEvent) error {
+	now := s.now()
+	pending := make(map[string]*domain.Todo)
+	for i := range events {
+		st.seq++
+		events[i].Seq = st.seq
+		if events[i].At.IsZero() {
+			events[i].At = now
+		}
+
+		current, ok := pending[events[i].TodoID]
+		if !ok {
+			current = st.state[events[i].TodoID]
+		}
+		next, err := events[i].Apply(current)
+		if err != nil {
+			st.seq -= int64(i + 1)
+			return err
+		}
+		pending[events[i].TodoID] = next
+	}
+
+	if tx := s.txFrom(ctx); tx != nil {
+		// Appended when the transaction commits
+		tx.events = append(tx.events, events...)
+	} else if err := s.log.Append(ctx, events); err != nil {
+		st.seq -= int64(len(events))
+		return fmt.Errorf("append events: %w", err)
+	}
+	for id, todo := range pending {
+		if err := s.set(ctx, st, id, todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replay applies the events of the tenant of ctx after the current sequence number of its stream
+func (s *Store) replay(ctx context.Context, st *stream) error {
+	events, err := s.log.Load(ctx, st.seq)
+	if err != nil {
+		return fmt.Errorf("load events: %w", err)
+	}
+	for _, event := range events {
+		next, err := event.Apply(st.state[event.TodoID])
+		if err != nil {
+			return fmt.Errorf("replay event %d: %w", event.Seq, err)
+		}
+		if err := s.set(ctx, st, event.TodoID, next); err != nil {
+			return err
+		}
+		st.seq = event.Seq
+	}
+	return nil
+}
+
+// set stores the new state of a todo, removing it when it was purged
+func (s *Store) set(ctx context.Context, st *stream, id string, todo *domain.Todo) error {
+	if todo == nil {
+		delete(st.state, id)
+		return s.projection.RemoveTodo(ctx, id)
+	}
+	return s.put(ctx, st, todo)
+}
+
+// put stores a todo in the state and a separate copy in the projection,
+// so that callers holding todos from the projection cannot change the state
+func (s *Store) put(ctx context.Context, st *stream, todo *domain.Todo) error {
+	state := *todo
+	st.state[todo.ID] = &state
+	projected := *todo
+	return s.projection.PutTodo(ctx, &projected)
+}
+
+// stream returns the stream of a tenant, creating it on first use
+func (s *Store) stream(tenantID string) *stream {
+	st, ok := s.tenants[tenantID]
+	if !ok {
+		st = &stream{state: make(map[string]*domain.Todo)}
+		s.tenants[tenantID] = st
+	}
+	return st
+}
+
+// live returns a todo unless it is missing or in the trash
+func (s *stream) live(id string) (*domain.Todo, error) {
+	todo, ok := s.state[id]
+	if !ok || todo.IsDeleted() {
+		return nil, fmt.Errorf("todo not found: %s", id)
+	}
+	return todo, nil
+}
+
+// trashed returns a todo that is in the trash
+func (s *stream) trashed(id string) (*domain.Todo, error) {
+	todo, ok := s.state[id]
+	if !ok || !todo.IsDeleted() {
+		return nil, fmt.Errorf("todo not found in trash: %s", id)
+	}
+	return todo, nil
+}
+
+// descendants returns every todo below a todo, trashed ones included
+func (s *stream) descendants(id string) []*domain.Todo {
+	byParent := make(map[string][]*domain.Todo)
+	for _, todo := range s.state {
+		if todo.ParentID != "" {
+			byParent[todo.ParentID] = append(byParent[todo.ParentID], todo)
+		}
+	}
+	todos := make([]*domain.Todo, 0)
+	for queue := []string{id}; len(queue) > 0; queue = queue[1:] {
+		children := byParent[queue[0]]
+		// Sorted so that replaying the log applies the events in the same order
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].ID < children[j].ID
+		})
+		for _, child := range children {
+			todos = append(todos, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return todos
+}
+
+// lastPosition returns the highest position among the live siblings of a new todo
+func (s *stream) lastPosition(userID, parentID string) float64 {
+	last := 0.0
+	for _, todo := range s.state {
+		if todo.IsDeleted() || todo.UserID != userID || todo.ParentID != parentID {
+			continue
+		}
+		if todo.Position > last {
+			last = todo.Position
+		}
+	}
+	return last
+}
+
+// updateEvent picks the event for an update, trying the specific events first
+func updateEvent(current, updated *domain.Todo) domain.TodoEvent {
+	at := updated.UpdatedAt
+	candidates := []domain.TodoEvent{
+		{Type: domain.TodoRenamed, Title: updated.Title},
+		{Type: domain.TodoCompleted},
+		{Type: domain.TodoReopened},
+		{Type: domain.TodoDueDateSet, DueAt: updated.DueAt},
+		{Type: domain.TodoPrioritySet, Priority: updated.Priority},
+	}
+	for _, event := range candidates {
+		event.TodoID = updated.ID
+		event.At = at
+		if next, err := event.Apply(current); err == nil && reflect.DeepEqual(next, updated) {
+			return event
+		}
+	}
+	return domain.TodoEvent{Type: domain.TodoUpdated, TodoID: updated.ID, At: at, Todo: updated}
+}
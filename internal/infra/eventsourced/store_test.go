@@ -0,0 +1,303 @@
+package eventsourced
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// flakyLog is a MemoryLog whose appends fail while fail is set
+type flakyLog struct {
+	*MemoryLog
+	fail bool
+}
+
+func (l *flakyLog) Append(ctx context.Context, events []domain.TodoEvent) error {
+	if l.fail {
+		return errors.New("disk full")
+	}
+	return l.MemoryLog.Append(ctx, events)
+}
+
+func newTestStore(t *testing.T, log EventLog, snapshots SnapshotStore) *Store {
+	t.Helper()
+	store, err := NewStore(context.Background(), log, snapshots, inmemory.NewStore())
+	require.NoError(t, err)
+	return store
+}
+
+// defaultStream returns the stream of the default tenant, which the tests act on unless they name a tenant
+func defaultStream(store *Store) *stream {
+	return store.tenants[tenant.Default]
+}
+
+// createTree creates todo1 with the subtask todo2, which has the subtask todo3
+func createTree(t *testing.T, store *Store) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Parent"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", ParentID: "todo1", Title: "Child"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo3", UserID: "user1", ParentID: "todo2", Title: "Grandchild"}))
+}
+
+func TestStore_DeleteAndPurgeCascadeToSubtasks(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	createTree(t, store)
+
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+	for _, id := range []string{"todo1", "todo2", "todo3"} {
+		_, err := store.GetTodo(ctx, id)
+		assert.Error(t, err, id)
+		require.NotNil(t, defaultStream(store).state[id].DeletedAt, id)
+		// Committed at once, so they share the time they were deleted
+		assert.Equal(t, defaultStream(store).state["todo1"].DeletedAt, defaultStream(store).state[id].DeletedAt, id)
+	}
+
+	require.NoError(t, store.PurgeTodo(ctx, "todo1"))
+	assert.Empty(t, defaultStream(store).state)
+	for _, id := range []string{"todo1", "todo2", "todo3"} {
+		_, err := store.GetDeletedTodo(ctx, id)
+		assert.Error(t, err, id)
+	}
+
+	// Replaying the log comes to the same result
+	replayed := newTestStore(t, log, nil)
+	assert.Empty(t, defaultStream(replayed).state)
+}
+
+func TestStore_PurgeTodosDeletedBeforeCascadesToSubtasks(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, NewMemoryLog(), nil)
+	createTree(t, store)
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+
+	purged, err := store.PurgeTodosDeletedBefore(ctx, time.Now().Add(time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	assert.Empty(t, defaultStream(store).state)
+}
+
+func TestStore_CreateTodoPosition(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	createTree(t, store)
+
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo4", UserID: "user1", Position: 0, PositionSet: true}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo5", UserID: "user1"}))
+
+	// Replaying the log keeps the positions the todos were created at
+	for _, s := range []*Store{store, newTestStore(t, log, nil)} {
+		assert.Equal(t, 1.0, defaultStream(s).state["todo1"].Position)
+		assert.Equal(t, 0.0, defaultStream(s).state["todo4"].Position)
+		assert.Equal(t, 2.0, defaultStream(s).state["todo5"].Position)
+		assert.False(t, defaultStream(s).state["todo4"].PositionSet)
+	}
+}
+
+func TestStore_RebuildAndHistory(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	createTree(t, store)
+
+	todo, err := store.GetTodo(ctx, "todo1")
+	require.NoError(t, err)
+	renamed := *todo
+	renamed.Title = "Renamed"
+	renamed.UpdatedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, store.UpdateTodo(ctx, &renamed))
+	require.NoError(t, store.MarkTodoComplete(ctx, "todo1"))
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+	require.NoError(t, store.RestoreTodo(ctx, "todo1"))
+
+	history, err := store.History(ctx, "todo1")
+	require.NoError(t, err)
+	types := make([]domain.TodoEventType, 0, len(history))
+	for _, event := range history {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []domain.TodoEventType{
+		domain.TodoCreated, domain.TodoRenamed, domain.TodoCompleted, domain.TodoDeleted, domain.TodoRestored,
+	}, types)
+
+	expected := cloneState(store)
+	require.NoError(t, store.Rebuild(ctx))
+	assert.Equal(t, expected, defaultStream(store).state)
+	todo, err = store.GetTodo(ctx, "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, expected["todo1"], todo)
+	// Only todo1 was restored, its subtasks stay in the trash
+	_, err = store.GetDeletedTodo(ctx, "todo2")
+	assert.NoError(t, err)
+}
+
+func TestStore_SnapshotThenMoreEvents(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	snapshots := NewMemorySnapshots()
+	store := newTestStore(t, log, snapshots)
+	createTree(t, store)
+	require.NoError(t, store.Snapshot(ctx))
+	taken := len(log.events[tenant.Default])
+
+	require.NoError(t, store.MarkTodoComplete(ctx, "todo3"))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo4", UserID: "user1", Title: "After the snapshot"}))
+	require.NoError(t, store.DeleteTodo(ctx, "todo2"))
+
+	// The events before the snapshot are left out, so the state can only come from the snapshot
+	tail := &MemoryLog{events: map[string][]domain.TodoEvent{tenant.Default: log.events[tenant.Default][taken:]}}
+	restored := newTestStore(t, tail, snapshots)
+	assert.Equal(t, defaultStream(store).state, defaultStream(restored).state)
+	assert.Equal(t, defaultStream(store).seq, defaultStream(restored).seq)
+	todo, err := restored.GetTodo(ctx, "todo4")
+	require.NoError(t, err)
+	assert.Equal(t, "After the snapshot", todo.Title)
+
+	require.NoError(t, store.Rebuild(ctx))
+	assert.Equal(t, defaultStream(restored).state, defaultStream(store).state)
+}
+
+func TestStore_FailedTransactionAppendsNothing(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	createTree(t, store)
+	expected := cloneState(store)
+	appended := len(log.events[tenant.Default])
+	runner := NewTxRunner(store.projection.(*inmemory.Store), store)
+
+	err := runner.RunInTx(ctx, func(ctx context.Context, _ smallinterface.UserStore, todos smallinterface.TodoStore) error {
+		require.NoError(t, todos.CreateTodo(ctx, &domain.Todo{ID: "todo4", UserID: "user1"}))
+		require.NoError(t, todos.MarkTodoComplete(ctx, "todo1"))
+		return errors.New("abort")
+	})
+
+	require.Error(t, err)
+	assert.Len(t, log.events[tenant.Default], appended)
+	assert.Equal(t, expected, defaultStream(store).state)
+	_, err = store.GetTodo(ctx, "todo4")
+	assert.Error(t, err)
+	todo, err := store.GetTodo(ctx, "todo1")
+	require.NoError(t, err)
+	assert.False(t, todo.Completed)
+}
+
+func TestUpdateEvent(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := at.Add(24 * time.Hour)
+	current := &domain.Todo{ID: "todo1", UserID: "user1", Title: "Title", Priority: domain.PriorityLow}
+
+	tests := map[string]struct {
+		change     func(todo *domain.Todo)
+		expectType domain.TodoEventType
+	}{
+		"Rename": {
+			change:     func(todo *domain.Todo) { todo.Title = "Renamed" },
+			expectType: domain.TodoRenamed,
+		},
+		"Completion": {
+			change: func(todo *domain.Todo) {
+				todo.Completed = true
+				todo.CompletedAt = &at
+			},
+			expectType: domain.TodoCompleted,
+		},
+		"Due date": {
+			change:     func(todo *domain.Todo) { todo.DueAt = &due },
+			expectType: domain.TodoDueDateSet,
+		},
+		"Priority": {
+			change:     func(todo *domain.Todo) { todo.Priority = domain.PriorityHigh },
+			expectType: domain.TodoPrioritySet,
+		},
+		"Rename and priority at once": {
+			change: func(todo *domain.Todo) {
+				todo.Title = "Renamed"
+				todo.Priority = domain.PriorityHigh
+			},
+			expectType: domain.TodoUpdated,
+		},
+		"Field without an event of its own": {
+			change:     func(todo *domain.Todo) { todo.Description = "More detail" },
+			expectType: domain.TodoUpdated,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			updated := *current
+			updated.UpdatedAt = at
+			tt.change(&updated)
+
+			event := updateEvent(current, &updated)
+
+			assert.Equal(t, tt.expectType, event.Type)
+			assert.Equal(t, "todo1", event.TodoID)
+			// Whatever the event, applying it gives the updated todo
+			next, err := event.Apply(current)
+			require.NoError(t, err)
+			assert.Equal(t, &updated, next)
+		})
+	}
+}
+
+func TestStore_BatchPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	log := &flakyLog{MemoryLog: NewMemoryLog()}
+	store := newTestStore(t, log, nil)
+	createTree(t, store)
+	appended := len(log.events[tenant.Default])
+
+	err := store.CreateTodos(ctx, []*domain.Todo{
+		{ID: "todo4", UserID: "user1"},
+		{ID: "", UserID: "user1"},
+		{ID: "todo1", UserID: "user1"},
+	})
+
+	var batchErr *domain.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	require.Len(t, batchErr.Items, 2)
+	assert.Equal(t, 1, batchErr.Items[0].Index)
+	assert.Equal(t, 2, batchErr.Items[1].Index)
+	// Only the item that succeeded is logged
+	require.Len(t, log.events[tenant.Default], appended+1)
+	assert.Equal(t, "todo4", log.events[tenant.Default][appended].TodoID)
+	_, err = store.GetTodo(ctx, "todo4")
+	assert.NoError(t, err)
+
+	// When the append fails, none of the batch is kept
+	expected := cloneState(store)
+	log.fail = true
+	err = store.MarkTodosComplete(ctx, []string{"todo1", "todo4"})
+	require.Error(t, err)
+	assert.Len(t, log.events[tenant.Default], appended+1)
+	assert.Equal(t, expected, defaultStream(store).state)
+	for _, id := range []string{"todo1", "todo4"} {
+		todo, err := store.GetTodo(ctx, id)
+		require.NoError(t, err)
+		assert.False(t, todo.Completed, id)
+	}
+}
+
+// cloneState copies the state of a store, so that it can be compared after the store changed
+func cloneState(store *Store) map[string]*domain.Todo {
+	state := make(map[string]*domain.Todo, len(defaultStream(store).state))
+	for id, todo := range defaultStream(store).state {
+		copied := *todo
+		state[id] = &copied
+	}
+	return state
+}
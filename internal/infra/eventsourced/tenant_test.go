@@ -0,0 +1,119 @@
+package eventsourced
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+var (
+	acme   = tenant.WithID(context.Background(), "acme")
+	globex = tenant.WithID(context.Background(), "globex")
+)
+
+func TestStore_TenantsShareIDs(t *testing.T) {
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	require.NoError(t, store.CreateTodo(acme, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Acme plans"}))
+	// The same ID does not collide in another tenant
+	require.NoError(t, store.CreateTodo(globex, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Globex plans"}))
+	require.NoError(t, store.MarkTodoComplete(globex, "todo1"))
+
+	todo, err := store.GetTodo(acme, "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme plans", todo.Title)
+	assert.False(t, todo.Completed)
+
+	// Each tenant numbers its events from 1
+	assert.Equal(t, int64(1), store.tenants["acme"].seq)
+	assert.Equal(t, int64(2), store.tenants["globex"].seq)
+	assert.Len(t, log.events["acme"], 1)
+	assert.Len(t, log.events["globex"], 2)
+
+	// Contexts without a tenant act on the default one
+	_, err = store.GetTodo(context.Background(), "todo1")
+	assert.Error(t, err)
+}
+
+func TestStore_WritesStayInTheirTenant(t *testing.T) {
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	require.NoError(t, store.CreateTodo(acme, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Acme plans"}))
+
+	todo, err := store.GetTodo(acme, "todo1")
+	require.NoError(t, err)
+	renamed := *todo
+	renamed.Title = "Renamed"
+	assert.Error(t, store.UpdateTodo(globex, &renamed))
+	assert.Error(t, store.MarkTodoComplete(globex, "todo1"))
+	assert.Error(t, store.DeleteTodo(globex, "todo1"))
+	assert.Empty(t, log.events["globex"])
+
+	history, err := store.History(globex, "todo1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	history, err = store.History(acme, "todo1")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestStore_ReplayKeepsTenantsApart(t *testing.T) {
+	log := NewMemoryLog()
+	snapshots := NewMemorySnapshots()
+	store := newTestStore(t, log, snapshots)
+	createTree(t, store)
+	require.NoError(t, store.CreateTodo(acme, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Acme plans"}))
+	require.NoError(t, store.Snapshot(acme))
+	require.NoError(t, store.CreateTodo(acme, &domain.Todo{ID: "todo2", UserID: "user1", Title: "After the snapshot"}))
+	require.NoError(t, store.CreateTodo(globex, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Globex plans"}))
+
+	restored := newTestStore(t, log, snapshots)
+	for _, id := range []string{tenant.Default, "acme", "globex"} {
+		assert.Equal(t, store.tenants[id].state, restored.tenants[id].state, id)
+		assert.Equal(t, store.tenants[id].seq, restored.tenants[id].seq, id)
+	}
+	todo, err := restored.GetTodo(globex, "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, "Globex plans", todo.Title)
+
+	// Rebuilding a tenant leaves the others alone
+	require.NoError(t, store.Rebuild(acme))
+	todo, err = store.GetTodo(context.Background(), "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, "Parent", todo.Title)
+	todo, err = store.GetTodo(acme, "todo2")
+	require.NoError(t, err)
+	assert.Equal(t, "After the snapshot", todo.Title)
+}
+
+func TestStore_TenantTransactions(t *testing.T) {
+	log := NewMemoryLog()
+	store := newTestStore(t, log, nil)
+	require.NoError(t, store.CreateTodo(globex, &domain.Todo{ID: "todo1", UserID: "user1"}))
+	runner := NewTxRunner(store.projection.(*inmemory.Store), store)
+
+	err := runner.RunInTx(acme, func(ctx context.Context, _ smallinterface.UserStore, todos smallinterface.TodoStore) error {
+		require.NoError(t, todos.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1"}))
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+	assert.Empty(t, store.tenants["acme"].state)
+	assert.Empty(t, log.events["acme"])
+
+	// A committed transaction appends to the stream of its own tenant
+	err = runner.RunInTx(acme, func(ctx context.Context, _ smallinterface.UserStore, todos smallinterface.TodoStore) error {
+		return todos.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1"})
+	})
+	require.NoError(t, err)
+	assert.Len(t, log.events["acme"], 1)
+	assert.Len(t, log.events["globex"], 1)
+	assert.Equal(t, int64(1), store.tenants["acme"].seq)
+}
@@ -0,0 +1,139 @@
+package eventsourced
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+type txKey struct{}
+
+// txn holds back the events of a transaction until it commits
+// The store stays locked for as long as the transaction runs, so the store's own
+// lock is always taken before the projection's
+// A transaction acts on the stream of a single tenant, whose sequence number and state it restores on rollback
+type txn struct {
+	store     *Store
+	nested    bool
+	events    []domain.TodoEvent
+	stream    *stream
+	seq       int64
+	state     map[string]*domain.Todo
+	committed bool
+	done      atomic.Bool
+}
+
+// begin locks the store and starts a transaction
+// A transaction started inside another one joins it, and its commit and end do nothing
+func (s *Store) begin(ctx context.Context) (context.Context, *txn) {
+	if s.txFrom(ctx) != nil {
+		return ctx, &txn{store: s, nested: true}
+	}
+
+	s.mu.Lock()
+	st := s.stream(tenant.FromContext(ctx))
+	tx := &txn{store: s, stream: st, seq: st.seq, state: make(map[string]*domain.Todo, len(st.state))}
+	// Events replace todos instead of changing them, so copying the map is enough
+	for id, todo := range st.state {
+		tx.state[id] = todo
+	}
+	return context.WithValue(ctx, txKey{}, tx), tx
+}
+
+// commit appends the events of the transaction to the log in one go
+// It is called last inside the projection's transaction, so a failed append rolls that back too
+func (tx *txn) commit(ctx context.Context) error {
+	if tx.nested {
+		return nil
+	}
+	if len(tx.events) > 0 {
+		if err := tx.store.log.Append(ctx, tx.events); err != nil {
+			return fmt.Errorf("append events: %w", err)
+		}
+	}
+	tx.committed = true
+	return nil
+}
+
+// end unlocks the store, restoring its state unless the transaction committed
+func (tx *txn) end() {
+	if tx.nested {
+		return
+	}
+	s := tx.store
+	if !tx.committed {
+		tx.stream.seq = tx.seq
+		tx.stream.state = tx.state
+	}
+	tx.done.Store(true)
+	s.mu.Unlock()
+}
+
+// txFrom returns the transaction of this store that ctx belongs to, if it is still running
+func (s *Store) txFrom(ctx context.Context) *txn {
+	tx, ok := ctx.Value(txKey{}).(*txn)
+	if !ok || tx.store != s || tx.done.Load() {
+		return nil
+	}
+	return tx
+}
+
+// lock takes the store's lock unless ctx belongs to a transaction, which already holds it,
+// and returns the stream to act on along with the function that releases the lock
+// Inside a transaction that is the stream of the transaction, whatever tenant ctx names
+func (s *Store) lock(ctx context.Context) (*stream, func()) {
+	if tx := s.txFrom(ctx); tx != nil {
+		return tx.stream, func() {}
+	}
+	s.mu.Lock()
+	return s.stream(tenant.FromContext(ctx)), s.mu.Unlock
+}
+
+// TxRunner runs transactions whose todo changes go through an event-sourced Store
+// runner must be the transaction runner of the Store's projection, so that a rollback
+// undoes the projected todos along with the held back events
+type TxRunner struct {
+	runner smallinterface.TxRunner
+	todos  *Store
+}
+
+var _ smallinterface.TxRunner = (*TxRunner)(nil)
+
+// NewTxRunner creates a TxRunner
+func NewTxRunner(runner smallinterface.TxRunner, todos *Store) *TxRunner {
+	return &TxRunner{
+		runner: runner,
+		todos:  todos,
+	}
+}
+
+func (r *TxRunner) RunInTx(ctx context.Context, fn func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error) error {
+	ctx, tx := r.todos.begin(ctx)
+	defer tx.end()
+
+	return r.runner.RunInTx(ctx, func(ctx context.Context, users smallinterface.UserStore, _ smallinterface.TodoStore) error {
+		if err := fn(ctx, users, r.todos); err != nil {
+			return err
+		}
+		return tx.commit(ctx)
+	})
+}
+
+// WithinTx runs fn in a transaction of the wrapped DataStore, routing its todo operations
+// through the event-sourced Store
+func (s *DataStore) WithinTx(ctx context.Context, fn func(ctx context.Context, tx biginterface.DataStore) error) error {
+	ctx, tx := s.todos.begin(ctx)
+	defer tx.end()
+
+	return s.DataStore.WithinTx(ctx, func(ctx context.Context, store biginterface.DataStore) error {
+		if err := fn(ctx, NewDataStore(store, s.todos)); err != nil {
+			return err
+		}
+		return tx.commit(ctx)
+	})
+}
@@ -0,0 +1,138 @@
+// Package file provides implementations that persist data to local files
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// AuditStore is an AuditStore that appends entries to a JSON lines file
+// Entries are never rewritten, so the file can be shipped or tailed as is
+type AuditStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ smallinterface.AuditStore = (*AuditStore)(nil)
+
+// auditRecord is the on-disk form of an audit entry, one per line
+type auditRecord struct {
+	ID         string          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	At         time.Time       `json:"at"`
+}
+
+// NewAuditStore creates an AuditStore writing to path, creating the file if needed
+func NewAuditStore(path string) (*AuditStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return &AuditStore{path: path}, nil
+}
+
+func (s *AuditStore) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.ID == "" {
+		return errors.New("audit entry ID cannot be empty")
+	}
+	line, err := json.Marshal(auditRecord{
+		ID:         entry.ID,
+		Actor:      entry.Actor,
+		Action:     string(entry.Action),
+		EntityType: string(entry.EntityType),
+		EntityID:   entry.EntityID,
+		Before:     entry.Before,
+		After:      entry.After,
+		At:         entry.At,
+	})
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("write audit entry: %w", err)
+	}
+	// The entry only counts as recorded once it is on disk
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync audit log: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *AuditStore) ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error) {
+	return s.scan(func(entry *domain.AuditEntry) bool {
+		return entry.EntityType == entityType && entry.EntityID == entityID
+	})
+}
+
+func (s *AuditStore) ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error) {
+	return s.scan(func(entry *domain.AuditEntry) bool {
+		return entry.Actor == actor
+	})
+}
+
+// scan reads the whole log and returns the entries that match, oldest first
+func (s *AuditStore) scan(match func(entry *domain.AuditEntry) bool) ([]*domain.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	entries := make([]*domain.AuditEntry, 0)
+	dec := json.NewDecoder(f)
+	for {
+		var record auditRecord
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode audit log: %w", err)
+		}
+		entry := &domain.AuditEntry{
+			ID:         record.ID,
+			Actor:      record.Actor,
+			Action:     domain.AuditAction(record.Action),
+			EntityType: domain.AuditEntityType(record.EntityType),
+			EntityID:   record.EntityID,
+			Before:     record.Before,
+			After:      record.After,
+			At:         record.At,
+		}
+		if match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
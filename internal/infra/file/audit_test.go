@@ -0,0 +1,79 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestAuditStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	store, err := NewAuditStore(path)
+	require.NoError(t, err)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []*domain.AuditEntry{
+		{ID: "entry1", Actor: "user1", Action: domain.AuditActionCreate, EntityType: domain.AuditEntityTodo, EntityID: "todo1", After: json.RawMessage(`{"title":"Plan"}`), At: at},
+		{ID: "entry2", Actor: "user2", Action: domain.AuditActionUpdate, EntityType: domain.AuditEntityTodo, EntityID: "todo1", Before: json.RawMessage(`{"title":"Plan"}`), After: json.RawMessage(`{"title":"Plan the week"}`), At: at.Add(time.Minute)},
+		{ID: "entry3", Actor: "user1", Action: domain.AuditActionDelete, EntityType: domain.AuditEntityUser, EntityID: "user3", Before: json.RawMessage(`{"name":"Carol"}`), At: at.Add(2 * time.Minute)},
+	}
+	for _, entry := range entries {
+		require.NoError(t, store.AppendAuditEntry(ctx, entry))
+	}
+	assert.Error(t, store.AppendAuditEntry(ctx, &domain.AuditEntry{Actor: "user1"}))
+
+	// Entries read back as they were appended, oldest first, even after reopening the file
+	reopened, err := NewAuditStore(path)
+	require.NoError(t, err)
+	for _, s := range []*AuditStore{store, reopened} {
+		got, err := s.ListEntityAuditEntries(ctx, domain.AuditEntityTodo, "todo1")
+		require.NoError(t, err)
+		assert.Equal(t, entries[:2], got)
+
+		got, err = s.ListActorAuditEntries(ctx, "user1")
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.AuditEntry{entries[0], entries[2]}, got)
+	}
+
+	tests := map[string]struct {
+		list      func() ([]*domain.AuditEntry, error)
+		expectIDs []string
+	}{
+		"Entity type must match": {
+			list: func() ([]*domain.AuditEntry, error) {
+				return store.ListEntityAuditEntries(ctx, domain.AuditEntityUser, "todo1")
+			},
+			expectIDs: []string{},
+		},
+		"Entity ID must match": {
+			list: func() ([]*domain.AuditEntry, error) {
+				return store.ListEntityAuditEntries(ctx, domain.AuditEntityUser, "user3")
+			},
+			expectIDs: []string{"entry3"},
+		},
+		"Unknown actor": {
+			list:      func() ([]*domain.AuditEntry, error) { return store.ListActorAuditEntries(ctx, "user9") },
+			expectIDs: []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.list()
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got))
+			for _, entry := range got {
+				ids = append(ids, entry.ID)
+			}
+			assert.Equal(t, tt.expectIDs, ids)
+		})
+	}
+}
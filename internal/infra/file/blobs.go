@@ -0,0 +1,259 @@
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// sniffLen is how many leading bytes of a blob are used to detect its content type,
+// which is all that http.DetectContentType considers
+const sniffLen = 512
+
+// tenantsDir is the directory holding the blobs of every tenant but the default one
+// Its name is not that of a shard, so that it never mixes with the blobs of the default tenant
+const tenantsDir = "tenants"
+
+// BlobStore is a BlobStore keeping each blob in a file named after its digest
+// Files are spread over subdirectories named after the first two characters of their digest
+// so that no directory grows too large
+// Each tenant has blobs of its own, kept in tenants/<tenant ID> while the default tenant keeps
+// the top directory, which is where blobs stored before tenants existed already are
+type BlobStore struct {
+	dir     string
+	maxSize int64
+}
+
+var _ smallinterface.BlobStore = (*BlobStore)(nil)
+
+// NewBlobStore creates a BlobStore in dir, creating the directory if needed
+// Blobs larger than maxSize bytes are rejected with domain.ErrBlobTooLarge
+func NewBlobStore(dir string, maxSize int64) (*BlobStore, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("blob size limit must be positive: %d", maxSize)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create blob directory: %w", err)
+	}
+	return &BlobStore{dir: dir, maxSize: maxSize}, nil
+}
+
+// PutBlob writes content to a temporary file while hashing it and moves it in place once complete,
+// so that a blob file is never seen half-written
+// Storing content that is already there refreshes the modification time of its file instead,
+// which keeps it from being collected as an orphan before the new attachment refers to it
+func (s *BlobStore) PutBlob(ctx context.Context, content io.Reader) (*domain.Blob, error) {
+	dir, err := s.tenantDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create blob: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("create blob: %w", err)
+	}
+	discard := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	hash := sha256.New()
+	head := &prefixWriter{limit: sniffLen}
+	size, err := io.Copy(io.MultiWriter(tmp, hash, head), io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		discard()
+		return nil, fmt.Errorf("write blob: %w", err)
+	}
+	if size > s.maxSize {
+		discard()
+		return nil, fmt.Errorf("%w: the limit is %d bytes", domain.ErrBlobTooLarge, s.maxSize)
+	}
+	if err := tmp.Sync(); err != nil {
+		discard()
+		return nil, fmt.Errorf("sync blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("write blob: %w", err)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	path := blobPath(dir, digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("create blob: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		os.Remove(tmp.Name())
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return nil, fmt.Errorf("touch blob: %w", err)
+		}
+	} else if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("create blob: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("create blob: %w", err)
+	}
+	return &domain.Blob{
+		Digest:      digest,
+		Size:        size,
+		ContentType: http.DetectContentType(head.buf),
+		CreatedAt:   info.ModTime(),
+	}, nil
+}
+
+func (s *BlobStore) OpenBlob(ctx context.Context, digest string) (io.ReadCloser, error) {
+	path, err := s.path(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("blob not found: %s", digest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open blob: %w", err)
+	}
+	return f, nil
+}
+
+// ListBlobs returns every blob of the tenant of ctx ordered by digest, with CreatedAt set to the time
+// its file was last written
+// ContentType is left empty, as it would take reading every blob
+func (s *BlobStore) ListBlobs(ctx context.Context) ([]*domain.Blob, error) {
+	dir, err := s.tenantDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		// The tenant has not stored any blob yet
+		return []*domain.Blob{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list blobs: %w", err)
+	}
+	blobs := make([]*domain.Blob, 0)
+	for _, shard := range shards {
+		// Temporary files of uploads in progress sit next to the shards, and so do
+		// the blobs of the other tenants in the top directory
+		if !shard.IsDir() || shard.Name() == tenantsDir {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, shard.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("list blobs: %w", err)
+		}
+		for _, entry := range entries {
+			if !isDigest(entry.Name()) || entry.Name()[:2] != shard.Name() {
+				continue
+			}
+			info, err := entry.Info()
+			if errors.Is(err, fs.ErrNotExist) {
+				// Deleted since the directory was read
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("list blobs: %w", err)
+			}
+			blobs = append(blobs, &domain.Blob{Digest: entry.Name(), Size: info.Size(), CreatedAt: info.ModTime()})
+		}
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].Digest < blobs[j].Digest
+	})
+	return blobs, nil
+}
+
+func (s *BlobStore) DeleteBlob(ctx context.Context, digest string) error {
+	path, err := s.path(ctx, digest)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("blob not found: %s", digest)
+	}
+	if err != nil {
+		return fmt.Errorf("delete blob: %w", err)
+	}
+	return nil
+}
+
+// tenantDir returns the directory holding the blobs of the tenant of ctx
+func (s *BlobStore) tenantDir(ctx context.Context) (string, error) {
+	id := tenant.FromContext(ctx)
+	if id == tenant.Default {
+		return s.dir, nil
+	}
+	// Keeps tenant IDs from naming directories outside the store
+	if err := tenant.Validate(id); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, tenantsDir, id), nil
+}
+
+// path returns the file of the blob with the given digest in the tenant of ctx
+func (s *BlobStore) path(ctx context.Context, digest string) (string, error) {
+	if !isDigest(digest) {
+		return "", fmt.Errorf("invalid blob digest: %q", digest)
+	}
+	dir, err := s.tenantDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return blobPath(dir, digest), nil
+}
+
+func blobPath(dir, digest string) string {
+	return filepath.Join(dir, digest[:2], digest)
+}
+
+// isDigest reports whether name is a lowercase hex SHA-256 digest
+// Checking it keeps digests from callers from naming files outside the store
+func isDigest(name string) bool {
+	if len(name) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range name {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// prefixWriter keeps the first limit bytes written to it and discards the rest
+type prefixWriter struct {
+	buf   []byte
+	limit int
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	if room := w.limit - len(w.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		w.buf = append(w.buf, p[:room]...)
+	}
+	return len(p), nil
+}
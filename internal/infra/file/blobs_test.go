@@ -0,0 +1,123 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewBlobStore(dir, 1024)
+	require.NoError(t, err)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+	blob, err := store.PutBlob(ctx, bytes.NewReader(png))
+	require.NoError(t, err)
+	assert.Len(t, blob.Digest, 64)
+	assert.Equal(t, int64(len(png)), blob.Size)
+	assert.Equal(t, "image/png", blob.ContentType)
+
+	// The same content is stored once
+	again, err := store.PutBlob(ctx, bytes.NewReader(png))
+	require.NoError(t, err)
+	assert.Equal(t, blob.Digest, again.Digest)
+	text, err := store.PutBlob(ctx, strings.NewReader("Meeting notes"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", text.ContentType)
+
+	r, err := store.OpenBlob(ctx, blob.Digest)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, png, content)
+
+	blobs, err := store.ListBlobs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, blobs, 2)
+
+	require.NoError(t, store.DeleteBlob(ctx, blob.Digest))
+	_, err = store.OpenBlob(ctx, blob.Digest)
+	assert.EqualError(t, err, "blob not found: "+blob.Digest)
+	assert.Error(t, store.DeleteBlob(ctx, blob.Digest))
+}
+
+func TestBlobStore_RejectsTooLargeBlobs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewBlobStore(dir, 16)
+	require.NoError(t, err)
+
+	_, err = store.PutBlob(ctx, strings.NewReader(strings.Repeat("x", 17)))
+	assert.ErrorIs(t, err, domain.ErrBlobTooLarge)
+
+	// Nothing is left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = store.PutBlob(ctx, strings.NewReader(strings.Repeat("x", 16)))
+	assert.NoError(t, err)
+}
+
+func TestBlobStore_RejectsInvalidDigests(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewBlobStore(t.TempDir(), 16)
+	require.NoError(t, err)
+
+	for _, digest := range []string{"", "../../etc/passwd", strings.Repeat("A", 64), strings.Repeat("a", 63)} {
+		_, err := store.OpenBlob(ctx, digest)
+		assert.Error(t, err, digest)
+		assert.Error(t, store.DeleteBlob(ctx, digest), digest)
+	}
+}
+
+func TestBlobStore_KeepsTenantsApart(t *testing.T) {
+	ctx := context.Background()
+	acme := tenant.WithID(ctx, "acme")
+	store, err := NewBlobStore(t.TempDir(), 1024)
+	require.NoError(t, err)
+
+	shared, err := store.PutBlob(ctx, strings.NewReader("Meeting notes"))
+	require.NoError(t, err)
+	// The same content in another tenant is a blob of its own
+	again, err := store.PutBlob(acme, strings.NewReader("Meeting notes"))
+	require.NoError(t, err)
+	assert.Equal(t, shared.Digest, again.Digest)
+	own, err := store.PutBlob(acme, strings.NewReader("Budget"))
+	require.NoError(t, err)
+
+	blobs, err := store.ListBlobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+	assert.Equal(t, shared.Digest, blobs[0].Digest)
+	blobs, err = store.ListBlobs(acme)
+	require.NoError(t, err)
+	assert.Len(t, blobs, 2)
+	blobs, err = store.ListBlobs(tenant.WithID(ctx, "globex"))
+	require.NoError(t, err)
+	assert.Empty(t, blobs)
+
+	require.NoError(t, store.DeleteBlob(acme, shared.Digest))
+	r, err := store.OpenBlob(ctx, shared.Digest)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	_, err = store.OpenBlob(ctx, own.Digest)
+	assert.Error(t, err)
+	assert.Error(t, store.DeleteBlob(ctx, own.Digest))
+
+	// Tenant IDs cannot name directories outside the store
+	_, err = store.ListBlobs(tenant.WithID(ctx, "../acme"))
+	assert.Error(t, err)
+}
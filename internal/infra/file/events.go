@@ -0,0 +1,265 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/eventsourced"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// EventLog is an event log kept in a JSON lines file, one event per line
+// The events of every tenant share the file, each line naming its tenant
+type EventLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ eventsourced.EventLog = (*EventLog)(nil)
+
+// eventRecord is the on-disk form of a todo event
+// Tenant is left out for the default tenant, so that logs written before tenants existed belong to it
+type eventRecord struct {
+	Tenant   string          `json:"tenant,omitempty"`
+	Seq      int64           `json:"seq"`
+	Type     string          `json:"type"`
+	TodoID   string          `json:"todo_id"`
+	At       time.Time       `json:"at"`
+	Todo     *domain.Todo    `json:"todo,omitempty"`
+	Title    string          `json:"title,omitempty"`
+	DueAt    *time.Time      `json:"due_at,omitempty"`
+	Priority domain.Priority `json:"priority,omitempty"`
+}
+
+// NewEventLog creates an EventLog writing to path, creating the file if needed
+func NewEventLog(path string) (*EventLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	return &EventLog{path: path}, nil
+}
+
+// Append writes all events with a single write so that a batch is not split by other writers
+func (l *EventLog) Append(ctx context.Context, events []domain.TodoEvent) error {
+	tenantID := tenant.FromContext(ctx)
+	if tenantID == tenant.Default {
+		tenantID = ""
+	}
+	var data []byte
+	for _, event := range events {
+		line, err := json.Marshal(eventRecord{
+			Tenant:   tenantID,
+			Seq:      event.Seq,
+			Type:     string(event.Type),
+			TodoID:   event.TodoID,
+			At:       event.At,
+			Todo:     event.Todo,
+			Title:    event.Title,
+			DueAt:    event.DueAt,
+			Priority: event.Priority,
+		})
+		if err != nil {
+			return fmt.Errorf("encode event: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open event log: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("write events: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync event log: %w", err)
+	}
+	return f.Close()
+}
+
+func (l *EventLog) Load(ctx context.Context, after int64) ([]domain.TodoEvent, error) {
+	tenantID := tenant.FromContext(ctx)
+	return l.scan(func(record eventRecord, event domain.TodoEvent) bool {
+		return recordTenant(record) == tenantID && event.Seq > after
+	})
+}
+
+func (l *EventLog) LoadTodo(ctx context.Context, todoID string) ([]domain.TodoEvent, error) {
+	tenantID := tenant.FromContext(ctx)
+	return l.scan(func(record eventRecord, event domain.TodoEvent) bool {
+		return recordTenant(record) == tenantID && event.TodoID == todoID
+	})
+}
+
+func (l *EventLog) Tenants(ctx context.Context) ([]string, error) {
+	seen := make(map[string]struct{})
+	tenants := make([]string, 0)
+	_, err := l.scan(func(record eventRecord, event domain.TodoEvent) bool {
+		id := recordTenant(record)
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			tenants = append(tenants, id)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// recordTenant returns the tenant an event on disk belongs to
+func recordTenant(record eventRecord) string {
+	if record.Tenant == "" {
+		return tenant.Default
+	}
+	return record.Tenant
+}
+
+// scan reads the whole log and returns the events that match, in log order
+func (l *EventLog) scan(match func(record eventRecord, event domain.TodoEvent) bool) ([]domain.TodoEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	defer f.Close()
+
+	events := make([]domain.TodoEvent, 0)
+	dec := json.NewDecoder(f)
+	for {
+		var record eventRecord
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode event log: %w", err)
+		}
+		event := domain.TodoEvent{
+			Seq:      record.Seq,
+			Type:     domain.TodoEventType(record.Type),
+			TodoID:   record.TodoID,
+			At:       record.At,
+			Todo:     record.Todo,
+			Title:    record.Title,
+			DueAt:    record.DueAt,
+			Priority: record.Priority,
+		}
+		if match(record, event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// Snapshots keeps the latest snapshot of an event-sourced store in a JSON file for each tenant
+// The default tenant keeps path itself, and every other tenant keeps a file named after its ID
+// in the directory named path followed by ".tenants"
+type Snapshots struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ eventsourced.SnapshotStore = (*Snapshots)(nil)
+
+// snapshotRecord is the on-disk form of a snapshot
+type snapshotRecord struct {
+	Seq     int64          `json:"seq"`
+	TakenAt time.Time      `json:"taken_at"`
+	Todos   []*domain.Todo `json:"todos"`
+}
+
+// NewSnapshots creates a snapshot store that keeps its snapshot at path
+func NewSnapshots(path string) *Snapshots {
+	return &Snapshots{path: path}
+}
+
+// SaveSnapshot replaces the snapshot through a temporary file,
+// so a crash never leaves a partially written snapshot behind
+func (s *Snapshots) SaveSnapshot(ctx context.Context, snapshot *eventsourced.Snapshot) error {
+	data, err := json.Marshal(snapshotRecord{
+		Seq:     snapshot.Seq,
+		TakenAt: snapshot.TakenAt,
+		Todos:   snapshot.Todos,
+	})
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	path, err := s.tenantPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create snapshot directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replace snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *Snapshots) LoadSnapshot(ctx context.Context) (*eventsourced.Snapshot, error) {
+	path, err := s.tenantPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	var record snapshotRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("decode snapshot: %w", err)
+	}
+	return &eventsourced.Snapshot{Seq: record.Seq, TakenAt: record.TakenAt, Todos: record.Todos}, nil
+}
+
+// tenantPath returns the file holding the snapshot of the tenant of ctx
+func (s *Snapshots) tenantPath(ctx context.Context) (string, error) {
+	id := tenant.FromContext(ctx)
+	if id == tenant.Default {
+		return s.path, nil
+	}
+	// Keeps tenant IDs from naming files outside the directory, or the temporary file of another tenant
+	if err := tenant.Validate(id); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.path+".tenants", id), nil
+}
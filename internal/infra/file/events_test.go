@@ -0,0 +1,168 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/eventsourced"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+func TestEventLog(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	log, err := NewEventLog(path)
+	require.NoError(t, err)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := at.Add(24 * time.Hour)
+	events := []domain.TodoEvent{
+		{Seq: 1, Type: domain.TodoCreated, TodoID: "todo1", At: at, Todo: &domain.Todo{ID: "todo1", UserID: "user1", Title: "Plan", CreatedAt: at, UpdatedAt: at}},
+		{Seq: 2, Type: domain.TodoCreated, TodoID: "todo2", At: at, Todo: &domain.Todo{ID: "todo2", UserID: "user1", Title: "Write", CreatedAt: at, UpdatedAt: at}},
+	}
+	require.NoError(t, log.Append(ctx, events))
+	require.NoError(t, log.Append(ctx, []domain.TodoEvent{
+		{Seq: 3, Type: domain.TodoRenamed, TodoID: "todo1", At: at, Title: "Plan the week"},
+		{Seq: 4, Type: domain.TodoDueDateSet, TodoID: "todo1", At: at, DueAt: &due},
+		{Seq: 5, Type: domain.TodoPrioritySet, TodoID: "todo2", At: at, Priority: domain.PriorityHigh},
+	}))
+
+	loaded, err := log.Load(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 5)
+	assert.Equal(t, events, loaded[:2])
+	assert.Equal(t, "Plan the week", loaded[2].Title)
+	assert.Equal(t, &due, loaded[3].DueAt)
+	assert.Equal(t, domain.PriorityHigh, loaded[4].Priority)
+
+	after, err := log.Load(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, loaded[3:], after)
+	todo1, err := log.LoadTodo(ctx, "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TodoEvent{loaded[0], loaded[2], loaded[3]}, todo1)
+
+	// Reopening keeps the events and appends after them
+	reopened, err := NewEventLog(path)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Append(ctx, []domain.TodoEvent{{Seq: 6, Type: domain.TodoCompleted, TodoID: "todo2", At: at}}))
+	loaded, err = reopened.Load(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, 6)
+	assert.Equal(t, int64(6), loaded[5].Seq)
+}
+
+func TestEventLog_ReplaysIntoStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	log, err := NewEventLog(filepath.Join(dir, "events.jsonl"))
+	require.NoError(t, err)
+	snapshots := NewSnapshots(filepath.Join(dir, "snapshot.json"))
+	store, err := eventsourced.NewStore(ctx, log, snapshots, inmemory.NewStore())
+	require.NoError(t, err)
+
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Plan"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", ParentID: "todo1", Title: "Write"}))
+	require.NoError(t, store.Snapshot(ctx))
+	require.NoError(t, store.MarkTodoComplete(ctx, "todo2"))
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+
+	// A store over the same files comes back to the same todos, from the snapshot and the events after it
+	reopened, err := NewEventLog(filepath.Join(dir, "events.jsonl"))
+	require.NoError(t, err)
+	restored, err := eventsourced.NewStore(ctx, reopened, NewSnapshots(filepath.Join(dir, "snapshot.json")), inmemory.NewStore())
+	require.NoError(t, err)
+	for _, id := range []string{"todo1", "todo2"} {
+		expected, err := store.GetDeletedTodo(ctx, id)
+		require.NoError(t, err)
+		todo, err := restored.GetDeletedTodo(ctx, id)
+		require.NoError(t, err)
+		// Times come back from the file without their location, so the todos are compared as JSON
+		assert.Equal(t, encode(t, expected), encode(t, todo), id)
+	}
+	history, err := restored.History(ctx, "todo2")
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+}
+
+// encode returns v as JSON
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSnapshots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	snapshots := NewSnapshots(path)
+
+	snapshot, err := snapshots.LoadSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	saved := &eventsourced.Snapshot{Seq: 7, TakenAt: at, Todos: []*domain.Todo{{ID: "todo1", UserID: "user1", Title: "Plan", CreatedAt: at, UpdatedAt: at}}}
+	require.NoError(t, snapshots.SaveSnapshot(ctx, saved))
+	snapshot, err = NewSnapshots(path).LoadSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, saved, snapshot)
+	// The temporary file is renamed over the snapshot
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestEventLog_KeepsTenantsApart(t *testing.T) {
+	acme := tenant.WithID(context.Background(), "acme")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	log, err := NewEventLog(path)
+	require.NoError(t, err)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, log.Append(context.Background(), []domain.TodoEvent{
+		{Seq: 1, Type: domain.TodoCreated, TodoID: "todo1", At: at, Todo: &domain.Todo{ID: "todo1", UserID: "user1", Title: "Default plans"}},
+	}))
+	require.NoError(t, log.Append(acme, []domain.TodoEvent{
+		{Seq: 1, Type: domain.TodoCreated, TodoID: "todo1", At: at, Todo: &domain.Todo{ID: "todo1", UserID: "user1", Title: "Acme plans"}},
+		{Seq: 2, Type: domain.TodoCompleted, TodoID: "todo1", At: at},
+	}))
+
+	tenants, err := log.Tenants(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{tenant.Default, "acme"}, tenants)
+	events, err := log.Load(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Default plans", events[0].Todo.Title)
+	events, err = log.LoadTodo(acme, "todo1")
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	// The events of the default tenant do not name it, as they did before tenants existed
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `{"seq":1,`)
+	assert.Contains(t, string(data), `{"tenant":"acme","seq":1,`)
+
+	snapshots := NewSnapshots(filepath.Join(dir, "snapshot.json"))
+	require.NoError(t, snapshots.SaveSnapshot(acme, &eventsourced.Snapshot{Seq: 2, TakenAt: at}))
+	snapshot, err := snapshots.LoadSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+	snapshot, err = snapshots.LoadSnapshot(acme)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), snapshot.Seq)
+	_, err = snapshots.LoadSnapshot(tenant.WithID(context.Background(), "../acme"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.AttachmentStore = (*Store)(nil)
+
+// Attachment-related operations
+func (s *state) GetAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	attachment, ok := s.attachments[id]
+	if !ok {
+		return nil, fmt.Errorf("attachment not found: %s", id)
+	}
+	return attachment, nil
+}
+
+// ListTodoAttachments returns the attachments of a todo from the oldest to the newest
+func (s *state) ListTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error) {
+	ids := s.todoAttachments[todoID]
+	attachments := make([]*domain.Attachment, 0, len(ids))
+	for id := range ids {
+		attachments = append(attachments, s.attachments[id])
+	}
+	sortAttachments(attachments)
+	return attachments, nil
+}
+
+// ListBlobAttachments returns the attachments whose content is a blob, including those of todos in the trash
+func (s *state) ListBlobAttachments(ctx context.Context, digest string) ([]*domain.Attachment, error) {
+	attachments := make([]*domain.Attachment, 0)
+	for _, attachment := range s.attachments {
+		if attachment.Digest == digest {
+			attachments = append(attachments, attachment)
+		}
+	}
+	sortAttachments(attachments)
+	return attachments, nil
+}
+
+func (s *state) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	if attachment.ID == "" {
+		return fmt.Errorf("attachment ID cannot be empty")
+	}
+	if _, ok := s.todos[attachment.TodoID]; !ok {
+		return fmt.Errorf("todo not found: %s", attachment.TodoID)
+	}
+	s.putAttachment(attachment)
+	return nil
+}
+
+func (s *state) DeleteAttachment(ctx context.Context, id string) error {
+	attachment, ok := s.attachments[id]
+	if !ok {
+		return fmt.Errorf("attachment not found: %s", id)
+	}
+	unset(s, s.attachments, id)
+	s.removeFromIndex(s.todoAttachments, attachment.TodoID, id)
+	return nil
+}
+
+func (s *state) putAttachment(attachment *domain.Attachment) {
+	set(s, s.attachments, attachment.ID, attachment)
+	s.addToIndex(s.todoAttachments, attachment.TodoID, attachment.ID)
+}
+
+// deleteTodoAttachments removes the attachments of a purged todo
+// Their blobs are left to be collected once no attachment refers to them
+func (s *state) deleteTodoAttachments(todoID string) {
+	for id := range s.todoAttachments[todoID] {
+		unset(s, s.attachments, id)
+	}
+	unset(s, s.todoAttachments, todoID)
+}
+
+func sortAttachments(attachments []*domain.Attachment) {
+	sort.Slice(attachments, func(i, j int) bool {
+		if !attachments[i].CreatedAt.Equal(attachments[j].CreatedAt) {
+			return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+		}
+		return attachments[i].ID < attachments[j].ID
+	})
+}
@@ -0,0 +1,46 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.AuditStore = (*Store)(nil)
+
+// Audit-related operations
+func (s *state) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.ID == "" {
+		return errors.New("audit entry ID cannot be empty")
+	}
+	if s.buffering {
+		n := len(s.auditLog)
+		s.undo = append(s.undo, func() {
+			s.auditLog = s.auditLog[:n]
+		})
+	}
+	s.auditLog = append(s.auditLog, entry)
+	return nil
+}
+
+func (s *state) ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error) {
+	entries := make([]*domain.AuditEntry, 0)
+	for _, entry := range s.auditLog {
+		if entry.EntityType == entityType && entry.EntityID == entityID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *state) ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error) {
+	entries := make([]*domain.AuditEntry, 0)
+	for _, entry := range s.auditLog {
+		if entry.Actor == actor {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,69 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Batch operations
+// Items are applied in order, so an item may depend on an earlier one, such as a subtask
+// created in the same batch as its parent
+func (s *state) CreateUsers(ctx context.Context, users []*domain.User) error {
+	var batchErr domain.BatchError
+	for i, user := range users {
+		if err := s.CreateUser(ctx, user); err != nil {
+			batchErr.Add(i, user.ID, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (s *state) DeleteUsers(ctx context.Context, ids []string) error {
+	return eachID(ids, func(id string) error {
+		return s.DeleteUser(ctx, id)
+	})
+}
+
+func (s *state) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	var batchErr domain.BatchError
+	for i, todo := range todos {
+		if err := s.CreateTodo(ctx, todo); err != nil {
+			batchErr.Add(i, todo.ID, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (s *state) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	var batchErr domain.BatchError
+	for i, todo := range todos {
+		if err := s.UpdateTodo(ctx, todo); err != nil {
+			batchErr.Add(i, todo.ID, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (s *state) DeleteTodos(ctx context.Context, ids []string) error {
+	return eachID(ids, func(id string) error {
+		return s.DeleteTodo(ctx, id)
+	})
+}
+
+func (s *state) MarkTodosComplete(ctx context.Context, ids []string) error {
+	return eachID(ids, func(id string) error {
+		return s.MarkTodoComplete(ctx, id)
+	})
+}
+
+// eachID runs fn for every ID, collecting the failures in a *domain.BatchError
+func eachID(ids []string, fn func(id string) error) error {
+	var batchErr domain.BatchError
+	for i, id := range ids {
+		if err := fn(id); err != nil {
+			batchErr.Add(i, id, err)
+		}
+	}
+	return batchErr.Err()
+}
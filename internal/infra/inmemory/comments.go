@@ -0,0 +1,97 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.CommentStore = (*Store)(nil)
+
+// Comment-related operations
+func (s *state) GetComment(ctx context.Context, id string) (*domain.Comment, error) {
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, fmt.Errorf("comment not found: %s", id)
+	}
+	return comment, nil
+}
+
+// ListTodoComments returns the comments of a todo from the oldest to the newest
+func (s *state) ListTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error) {
+	ids := s.todoComments[todoID]
+	comments := make([]*domain.Comment, 0, len(ids))
+	for id := range ids {
+		comments = append(comments, s.comments[id])
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	return comments, nil
+}
+
+// CreateComment stores a comment on a todo, which may be in the trash but must not be purged
+func (s *state) CreateComment(ctx context.Context, comment *domain.Comment) error {
+	if comment.ID == "" {
+		return fmt.Errorf("comment ID cannot be empty")
+	}
+	if _, ok := s.todos[comment.TodoID]; !ok {
+		return fmt.Errorf("todo not found: %s", comment.TodoID)
+	}
+	s.putComment(comment)
+	return nil
+}
+
+func (s *state) UpdateComment(ctx context.Context, comment *domain.Comment) error {
+	existing, ok := s.comments[comment.ID]
+	if !ok {
+		return fmt.Errorf("comment not found: %s", comment.ID)
+	}
+	if existing.TodoID != comment.TodoID {
+		return fmt.Errorf("comment %s cannot be moved to another todo", comment.ID)
+	}
+	s.putComment(comment)
+	return nil
+}
+
+func (s *state) DeleteComment(ctx context.Context, id string) error {
+	comment, ok := s.comments[id]
+	if !ok {
+		return fmt.Errorf("comment not found: %s", id)
+	}
+	s.removeComment(comment)
+	return nil
+}
+
+func (s *state) putComment(comment *domain.Comment) {
+	set(s, s.comments, comment.ID, comment)
+	s.addToIndex(s.todoComments, comment.TodoID, comment.ID)
+}
+
+func (s *state) removeComment(comment *domain.Comment) {
+	unset(s, s.comments, comment.ID)
+	s.removeFromIndex(s.todoComments, comment.TodoID, comment.ID)
+}
+
+// deleteTodoComments removes the comments of a purged todo
+func (s *state) deleteTodoComments(todoID string) {
+	for id := range s.todoComments[todoID] {
+		unset(s, s.comments, id)
+	}
+	unset(s, s.todoComments, todoID)
+}
+
+// deleteAuthorComments removes the comments written by a purged user
+func (s *state) deleteAuthorComments(authorID string) {
+	for _, comment := range s.comments {
+		if comment.AuthorID == authorID {
+			s.removeComment(comment)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestStore_CommentsFollowTheirTodo(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := fixtureStore(t)
+	require.NoError(t, store.CreateComment(ctx, &domain.Comment{ID: "comment2", TodoID: "todo1", AuthorID: "user1", Body: "Later", CreatedAt: now.Add(time.Minute), UpdatedAt: now}))
+
+	comments, err := store.ListTodoComments(ctx, "todo1")
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "comment1", comments[0].ID)
+	assert.Equal(t, "comment2", comments[1].ID)
+
+	// Comments stay with a trashed todo so that restoring it brings them back
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+	comments, err = store.ListTodoComments(ctx, "todo1")
+	require.NoError(t, err)
+	assert.Len(t, comments, 2)
+
+	require.NoError(t, store.PurgeTodo(ctx, "todo1"))
+	comments, err = store.ListTodoComments(ctx, "todo1")
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+	_, err = store.GetComment(ctx, "comment1")
+	assert.Error(t, err)
+	assert.Error(t, store.CreateComment(ctx, &domain.Comment{ID: "comment3", TodoID: "todo1", AuthorID: "user1", Body: "Too late"}))
+}
@@ -0,0 +1,491 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// Store is an implementation that satisfies both interfaces
+// Each tenant has a state of its own, and every operation only sees the tenant of its context (see package tenant),
+// so the same ID can be used by several tenants without them ever seeing each other's data
+// It is safe for concurrent use: reads share a lock, writes and transactions hold it exclusively
+type Store struct {
+	mu sync.RWMutex
+	// tenants only gains entries under the exclusive lock, so reads may look it up while sharing it
+	tenants map[string]*state
+}
+
+// emptyState is what reads of a tenant without any data see
+// It is never written to, so reads of every such tenant can share it
+var emptyState = newState()
+
+// NewStore creates a new in-memory store
+func NewStore() *Store {
+	return &Store{tenants: make(map[string]*state)}
+}
+
+// lock takes the lock for an operation and returns the state of the tenant of ctx together with the function that releases it
+// Operations called with the context of a running transaction already hold it and act on the transaction's state,
+// whatever tenant ctx names
+// Only writes create the state of a tenant, so reads naming unknown tenants leave no trace
+func (s *Store) lock(ctx context.Context, write bool) (*state, func()) {
+	if tx, ok := s.runningTx(ctx); ok {
+		return tx.state, func() {}
+	}
+	if write {
+		s.mu.Lock()
+		return s.tenantState(tenant.FromContext(ctx)), s.mu.Unlock
+	}
+	s.mu.RLock()
+	st, ok := s.tenants[tenant.FromContext(ctx)]
+	if !ok {
+		st = emptyState
+	}
+	return st, s.mu.RUnlock
+}
+
+// tenantState returns the state of a tenant, creating it on first use
+// The caller must hold the exclusive lock
+func (s *Store) tenantState(id string) *state {
+	st, ok := s.tenants[id]
+	if !ok {
+		st = newState()
+		s.tenants[id] = st
+	}
+	return st
+}
+
+// User and Todo operations
+func (s *Store) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetUser(ctx, id)
+}
+
+func (s *Store) ListUsers(ctx context.Context) ([]*domain.User, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUsers(ctx)
+}
+
+func (s *Store) CreateUser(ctx context.Context, user *domain.User) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateUser(ctx, user)
+}
+
+func (s *Store) UpdateUser(ctx context.Context, user *domain.User) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.UpdateUser(ctx, user)
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteUser(ctx, id)
+}
+
+func (s *Store) GetTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetTodo(ctx, id)
+}
+
+func (s *Store) ListTodos(ctx context.Context) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodos(ctx)
+}
+
+func (s *Store) ListUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUserTodos(ctx, userID)
+}
+
+func (s *Store) ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListArchivedTodos(ctx, userID)
+}
+
+func (s *Store) ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListAssignedTodos(ctx, assigneeID)
+}
+
+func (s *Store) CreateTodo(ctx context.Context, todo *domain.Todo) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateTodo(ctx, todo)
+}
+
+func (s *Store) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.UpdateTodo(ctx, todo)
+}
+
+func (s *Store) DeleteTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteTodo(ctx, id)
+}
+
+func (s *Store) MarkTodoComplete(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.MarkTodoComplete(ctx, id)
+}
+
+func (s *Store) ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListSubtasks(ctx, parentID)
+}
+
+func (s *Store) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.SetTodoDueDate(ctx, id, dueAt)
+}
+
+func (s *Store) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.SetTodoPriority(ctx, id, priority)
+}
+
+func (s *Store) ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListOverdueTodos(ctx, userID, now)
+}
+
+func (s *Store) ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodosDueBetween(ctx, userID, from, to)
+}
+
+func (s *Store) ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUserTodosByPriority(ctx, userID)
+}
+
+// Trash operations
+func (s *Store) GetDeletedUser(ctx context.Context, id string) (*domain.User, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetDeletedUser(ctx, id)
+}
+
+func (s *Store) ListDeletedUsers(ctx context.Context) ([]*domain.User, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListDeletedUsers(ctx)
+}
+
+func (s *Store) RestoreUser(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.RestoreUser(ctx, id)
+}
+
+func (s *Store) PurgeUser(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.PurgeUser(ctx, id)
+}
+
+func (s *Store) GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetDeletedTodo(ctx, id)
+}
+
+func (s *Store) ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListDeletedTodos(ctx, userID)
+}
+
+func (s *Store) RestoreTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.RestoreTodo(ctx, id)
+}
+
+func (s *Store) PurgeTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.PurgeTodo(ctx, id)
+}
+
+func (s *Store) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.PurgeTodosDeletedBefore(ctx, cutoff)
+}
+
+// Batch operations
+func (s *Store) CreateUsers(ctx context.Context, users []*domain.User) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateUsers(ctx, users)
+}
+
+func (s *Store) DeleteUsers(ctx context.Context, ids []string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteUsers(ctx, ids)
+}
+
+func (s *Store) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateTodos(ctx, todos)
+}
+
+func (s *Store) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.UpdateTodos(ctx, todos)
+}
+
+func (s *Store) DeleteTodos(ctx context.Context, ids []string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteTodos(ctx, ids)
+}
+
+func (s *Store) MarkTodosComplete(ctx context.Context, ids []string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.MarkTodosComplete(ctx, ids)
+}
+
+// Tag operations
+func (s *Store) AddTodoTag(ctx context.Context, todoID string, tag string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.AddTodoTag(ctx, todoID, tag)
+}
+
+func (s *Store) RemoveTodoTag(ctx context.Context, todoID string, tag string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.RemoveTodoTag(ctx, todoID, tag)
+}
+
+func (s *Store) ListTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodoTags(ctx, todoID)
+}
+
+func (s *Store) ListUserTags(ctx context.Context, userID string) ([]*domain.Tag, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUserTags(ctx, userID)
+}
+
+func (s *Store) ListTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodosWithAllTags(ctx, userID, tags)
+}
+
+func (s *Store) ListTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodosWithAnyTag(ctx, userID, tags)
+}
+
+// Project operations
+func (s *Store) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetProject(ctx, id)
+}
+
+func (s *Store) ListUserProjects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUserProjects(ctx, userID)
+}
+
+func (s *Store) CreateProject(ctx context.Context, project *domain.Project) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateProject(ctx, project)
+}
+
+func (s *Store) UpdateProject(ctx context.Context, project *domain.Project) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.UpdateProject(ctx, project)
+}
+
+func (s *Store) DeleteProject(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteProject(ctx, id)
+}
+
+// Token operations
+func (s *Store) GetToken(ctx context.Context, id string) (*domain.Token, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetToken(ctx, id)
+}
+
+func (s *Store) ListUserTokens(ctx context.Context, userID string) ([]*domain.Token, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUserTokens(ctx, userID)
+}
+
+func (s *Store) CreateToken(ctx context.Context, token *domain.Token) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateToken(ctx, token)
+}
+
+func (s *Store) RevokeToken(ctx context.Context, id string, revokedAt time.Time) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.RevokeToken(ctx, id, revokedAt)
+}
+
+// Sharing-related operations
+func (s *Store) GetShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) (*domain.Share, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetShare(ctx, resourceType, resourceID, userID)
+}
+
+func (s *Store) ListResourceShares(ctx context.Context, resourceType domain.ShareResourceType, resourceID string) ([]*domain.Share, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListResourceShares(ctx, resourceType, resourceID)
+}
+
+func (s *Store) ListUserShares(ctx context.Context, userID string) ([]*domain.Share, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListUserShares(ctx, userID)
+}
+
+func (s *Store) PutShare(ctx context.Context, share *domain.Share) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.PutShare(ctx, share)
+}
+
+func (s *Store) DeleteShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteShare(ctx, resourceType, resourceID, userID)
+}
+
+// Comment-related operations
+func (s *Store) GetComment(ctx context.Context, id string) (*domain.Comment, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetComment(ctx, id)
+}
+
+func (s *Store) ListTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodoComments(ctx, todoID)
+}
+
+func (s *Store) CreateComment(ctx context.Context, comment *domain.Comment) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateComment(ctx, comment)
+}
+
+func (s *Store) UpdateComment(ctx context.Context, comment *domain.Comment) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.UpdateComment(ctx, comment)
+}
+
+func (s *Store) DeleteComment(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteComment(ctx, id)
+}
+
+// Attachment-related operations
+func (s *Store) GetAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.GetAttachment(ctx, id)
+}
+
+func (s *Store) ListTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListTodoAttachments(ctx, todoID)
+}
+
+func (s *Store) ListBlobAttachments(ctx context.Context, digest string) ([]*domain.Attachment, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListBlobAttachments(ctx, digest)
+}
+
+func (s *Store) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.CreateAttachment(ctx, attachment)
+}
+
+func (s *Store) DeleteAttachment(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.DeleteAttachment(ctx, id)
+}
+
+// Audit-related operations
+func (s *Store) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.AppendAuditEntry(ctx, entry)
+}
+
+func (s *Store) ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListEntityAuditEntries(ctx, entityType, entityID)
+}
+
+func (s *Store) ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error) {
+	st, unlock := s.lock(ctx, false)
+	defer unlock()
+	return st.ListActorAuditEntries(ctx, actor)
+}
+
+// Projection operations
+func (s *Store) PutTodo(ctx context.Context, todo *domain.Todo) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.PutTodo(ctx, todo)
+}
+
+func (s *Store) RemoveTodo(ctx context.Context, id string) error {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.RemoveTodo(ctx, id)
+}
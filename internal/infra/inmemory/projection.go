@@ -0,0 +1,37 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Projection operations
+// These let the store serve as a read model for todos whose state is kept elsewhere,
+// such as in an event log, so they store todos exactly as given
+
+// PutTodo stores a todo as is, whether it is live, archived or in the trash
+func (s *state) PutTodo(ctx context.Context, todo *domain.Todo) error {
+	if todo.ID == "" {
+		return fmt.Errorf("todo ID cannot be empty")
+	}
+	existing, existed := s.todos[todo.ID]
+	kind := domain.ChangeCreated
+	if existed {
+		kind = todoChangeKind(existing, todo)
+	}
+	s.putTodo(todo)
+	s.publishTodo(kind, todo)
+	return nil
+}
+
+// RemoveTodo removes a todo together with its tag links, wherever it is
+func (s *state) RemoveTodo(ctx context.Context, id string) error {
+	todo, ok := s.todos[id]
+	if !ok {
+		return fmt.Errorf("todo not found: %s", id)
+	}
+	s.purgeTodo(todo)
+	return nil
+}
@@ -0,0 +1,64 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.ProjectStore = (*Store)(nil)
+
+// Project-related operations
+func (s *state) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	project, ok := s.projects[id]
+	if !ok {
+		return nil, fmt.Errorf("project not found: %s", id)
+	}
+	return project, nil
+}
+
+func (s *state) ListUserProjects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	projects := make([]*domain.Project, 0)
+	for _, project := range s.projects {
+		if project.UserID == userID {
+			projects = append(projects, project)
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return projects[i].ID < projects[j].ID
+	})
+	return projects, nil
+}
+
+func (s *state) CreateProject(ctx context.Context, project *domain.Project) error {
+	if project.ID == "" {
+		return fmt.Errorf("project ID cannot be empty")
+	}
+	set(s, s.projects, project.ID, project)
+	return nil
+}
+
+func (s *state) UpdateProject(ctx context.Context, project *domain.Project) error {
+	if _, ok := s.projects[project.ID]; !ok {
+		return fmt.Errorf("project not found: %s", project.ID)
+	}
+	set(s, s.projects, project.ID, project)
+	return nil
+}
+
+func (s *state) DeleteProject(ctx context.Context, id string) error {
+	if _, ok := s.projects[id]; !ok {
+		return fmt.Errorf("project not found: %s", id)
+	}
+	unset(s, s.projects, id)
+	s.deleteShares(func(share *domain.Share) bool {
+		return share.ResourceType == domain.ShareResourceProject && share.ResourceID == id
+	})
+	return nil
+}
@@ -0,0 +1,96 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.SharingStore = (*Store)(nil)
+
+// shareKey identifies a share: a resource can be shared with a user only once
+type shareKey struct {
+	resourceType domain.ShareResourceType
+	resourceID   string
+	userID       string
+}
+
+func keyOf(share *domain.Share) shareKey {
+	return shareKey{resourceType: share.ResourceType, resourceID: share.ResourceID, userID: share.UserID}
+}
+
+// Sharing-related operations
+func (s *state) GetShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) (*domain.Share, error) {
+	share, ok := s.shares[shareKey{resourceType: resourceType, resourceID: resourceID, userID: userID}]
+	if !ok {
+		return nil, fmt.Errorf("share not found: %s %s with %s", resourceType, resourceID, userID)
+	}
+	return share, nil
+}
+
+// ListResourceShares returns the shares of a todo or project, ordered by the user they are shared with
+func (s *state) ListResourceShares(ctx context.Context, resourceType domain.ShareResourceType, resourceID string) ([]*domain.Share, error) {
+	return s.filterShares(func(share *domain.Share) bool {
+		return share.ResourceType == resourceType && share.ResourceID == resourceID
+	}), nil
+}
+
+// ListUserShares returns the shares granted to a user
+func (s *state) ListUserShares(ctx context.Context, userID string) ([]*domain.Share, error) {
+	return s.filterShares(func(share *domain.Share) bool {
+		return share.UserID == userID
+	}), nil
+}
+
+// PutShare creates a share, or replaces the share of the same resource with the same user
+func (s *state) PutShare(ctx context.Context, share *domain.Share) error {
+	if !share.ResourceType.IsValid() {
+		return fmt.Errorf("invalid share resource type: %q", share.ResourceType)
+	}
+	if share.ResourceID == "" || share.UserID == "" {
+		return fmt.Errorf("share resource ID and user ID cannot be empty")
+	}
+	set(s, s.shares, keyOf(share), share)
+	return nil
+}
+
+func (s *state) DeleteShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) error {
+	key := shareKey{resourceType: resourceType, resourceID: resourceID, userID: userID}
+	if _, ok := s.shares[key]; !ok {
+		return fmt.Errorf("share not found: %s %s with %s", resourceType, resourceID, userID)
+	}
+	unset(s, s.shares, key)
+	return nil
+}
+
+func (s *state) filterShares(match func(share *domain.Share) bool) []*domain.Share {
+	shares := make([]*domain.Share, 0)
+	for _, share := range s.shares {
+		if match(share) {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		a, b := shares[i], shares[j]
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.ResourceID != b.ResourceID {
+			return a.ResourceID < b.ResourceID
+		}
+		return a.UserID < b.UserID
+	})
+	return shares
+}
+
+// deleteShares removes the shares that match, such as those of a purged todo
+func (s *state) deleteShares(match func(share *domain.Share) bool) {
+	for key, share := range s.shares {
+		if match(share) {
+			unset(s, s.shares, key)
+		}
+	}
+}
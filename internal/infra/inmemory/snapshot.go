@@ -0,0 +1,356 @@
+package inmemory
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// SnapshotVersion is the version of the format Snapshot writes
+// Restore also reads version 1, which predates tenants, into the default tenant, and rejects any other version
+const SnapshotVersion = 2
+
+// singleTenantSnapshotVersion is the version whose data is the content of a single tenant
+const singleTenantSnapshotVersion = 1
+
+// ErrSnapshotChecksum is returned by Restore when the data of a snapshot does not match its checksum
+var ErrSnapshotChecksum = errors.New("snapshot checksum mismatch")
+
+// snapshotFile is the format of a snapshot
+// Checksum is the hex SHA-256 of Data in compact JSON, so that indentation does not change it
+type snapshotFile struct {
+	Version  int             `json:"version"`
+	Checksum string          `json:"checksum"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// snapshotTenants is the data of a snapshot: the content of every tenant by its ID
+type snapshotTenants map[string]*snapshotData
+
+// snapshotData is the content of a tenant, with every list sorted so that equal stores give equal snapshots
+type snapshotData struct {
+	Users       []*domain.User       `json:"users"`
+	Todos       []*domain.Todo       `json:"todos"`
+	Projects    []*domain.Project    `json:"projects"`
+	Tags        []snapshotTag        `json:"tags"`
+	Tokens      []*domain.Token      `json:"tokens"`
+	Shares      []*domain.Share      `json:"shares"`
+	Comments    []*domain.Comment    `json:"comments"`
+	Attachments []*domain.Attachment `json:"attachments"`
+	AuditLog    []*domain.AuditEntry `json:"audit_log"`
+}
+
+// snapshotTag is a tag together with the todos it is attached to
+type snapshotTag struct {
+	domain.Tag
+	TodoIDs []string `json:"todo_ids"`
+}
+
+// Snapshot operations
+
+// Snapshot writes the whole content of the store, including the trash and the audit log of every tenant,
+// as indented JSON that Restore reads back
+func (s *Store) Snapshot(w io.Writer) error {
+	s.mu.RLock()
+	tenants := make(snapshotTenants, len(s.tenants))
+	for id, st := range s.tenants {
+		tenants[id] = st.snapshot()
+	}
+	// Map keys are sorted, so equal stores still give equal snapshots
+	data, err := json.Marshal(tenants)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	file, err := json.MarshalIndent(snapshotFile{
+		Version:  SnapshotVersion,
+		Checksum: hex.EncodeToString(sum[:]),
+		Data:     data,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(file, '\n'))
+	return err
+}
+
+// Restore replaces the content of the store with a snapshot
+// The store is left as it was when the snapshot is invalid
+// Watchers are not notified, as the changes that led to the snapshot are unknown
+func (s *Store) Restore(r io.Reader) error {
+	var file snapshotFile
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	if file.Version != SnapshotVersion && file.Version != singleTenantSnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d, want %d", file.Version, SnapshotVersion)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, file.Data); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	sum := sha256.Sum256(compact.Bytes())
+	if hex.EncodeToString(sum[:]) != file.Checksum {
+		return ErrSnapshotChecksum
+	}
+
+	tenants := make(snapshotTenants)
+	dec = json.NewDecoder(&compact)
+	dec.DisallowUnknownFields()
+	var err error
+	if file.Version == singleTenantSnapshotVersion {
+		data := &snapshotData{}
+		err = dec.Decode(data)
+		tenants[tenant.Default] = data
+	} else {
+		err = dec.Decode(&tenants)
+	}
+	if err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	restored := make(map[string]*state, len(tenants))
+	for id, data := range tenants {
+		if err := tenant.Validate(id); err != nil {
+			return fmt.Errorf("snapshot: %w", err)
+		}
+		if data == nil {
+			return fmt.Errorf("snapshot: tenant %s has no data", id)
+		}
+		st := newState()
+		if err := st.restore(data); err != nil {
+			return fmt.Errorf("tenant %s: %w", id, err)
+		}
+		restored[id] = st
+	}
+	// Watchers keep watching their tenant, including the tenants the snapshot leaves empty
+	for id, old := range s.tenants {
+		st, ok := restored[id]
+		if !ok {
+			st = newState()
+			restored[id] = st
+		}
+		st.feed = old.feed
+	}
+	s.tenants = restored
+	return nil
+}
+
+func (s *state) snapshot() *snapshotData {
+	data := &snapshotData{
+		Users:       make([]*domain.User, 0, len(s.users)),
+		Todos:       make([]*domain.Todo, 0, len(s.todos)),
+		Projects:    make([]*domain.Project, 0, len(s.projects)),
+		Tags:        make([]snapshotTag, 0),
+		Tokens:      make([]*domain.Token, 0, len(s.tokens)),
+		Comments:    make([]*domain.Comment, 0, len(s.comments)),
+		Attachments: make([]*domain.Attachment, 0, len(s.attachments)),
+		AuditLog:    append(make([]*domain.AuditEntry, 0, len(s.auditLog)), s.auditLog...),
+	}
+	for _, user := range s.users {
+		data.Users = append(data.Users, user)
+	}
+	sort.Slice(data.Users, func(i, j int) bool {
+		return data.Users[i].ID < data.Users[j].ID
+	})
+	for _, todo := range s.todos {
+		data.Todos = append(data.Todos, todo)
+	}
+	sort.Slice(data.Todos, func(i, j int) bool {
+		return data.Todos[i].ID < data.Todos[j].ID
+	})
+	for _, project := range s.projects {
+		data.Projects = append(data.Projects, project)
+	}
+	sort.Slice(data.Projects, func(i, j int) bool {
+		return data.Projects[i].ID < data.Projects[j].ID
+	})
+	for _, token := range s.tokens {
+		data.Tokens = append(data.Tokens, token)
+	}
+	sort.Slice(data.Tokens, func(i, j int) bool {
+		return data.Tokens[i].ID < data.Tokens[j].ID
+	})
+	data.Shares = s.filterShares(func(*domain.Share) bool { return true })
+	for _, comment := range s.comments {
+		data.Comments = append(data.Comments, comment)
+	}
+	sort.Slice(data.Comments, func(i, j int) bool {
+		return data.Comments[i].ID < data.Comments[j].ID
+	})
+	for _, attachment := range s.attachments {
+		data.Attachments = append(data.Attachments, attachment)
+	}
+	sort.Slice(data.Attachments, func(i, j int) bool {
+		return data.Attachments[i].ID < data.Attachments[j].ID
+	})
+
+	for _, tags := range s.userTags {
+		for _, links := range tags {
+			tag := snapshotTag{Tag: *links.tag, TodoIDs: make([]string, 0, len(links.todos))}
+			for todoID := range links.todos {
+				tag.TodoIDs = append(tag.TodoIDs, todoID)
+			}
+			sort.Strings(tag.TodoIDs)
+			data.Tags = append(data.Tags, tag)
+		}
+	}
+	sort.Slice(data.Tags, func(i, j int) bool {
+		if data.Tags[i].UserID != data.Tags[j].UserID {
+			return data.Tags[i].UserID < data.Tags[j].UserID
+		}
+		return data.Tags[i].Name < data.Tags[j].Name
+	})
+	return data
+}
+
+// restore fills an empty state from a snapshot, checking that its entities refer to each other consistently
+func (s *state) restore(data *snapshotData) error {
+	for _, user := range data.Users {
+		if user.ID == "" {
+			return errors.New("snapshot: user ID cannot be empty")
+		}
+		if _, ok := s.users[user.ID]; ok {
+			return fmt.Errorf("snapshot: duplicate user %s", user.ID)
+		}
+		s.users[user.ID] = user
+	}
+	for _, project := range data.Projects {
+		if project.ID == "" {
+			return errors.New("snapshot: project ID cannot be empty")
+		}
+		if _, ok := s.projects[project.ID]; ok {
+			return fmt.Errorf("snapshot: duplicate project %s", project.ID)
+		}
+		s.projects[project.ID] = project
+	}
+	for _, todo := range data.Todos {
+		if todo.ID == "" {
+			return errors.New("snapshot: todo ID cannot be empty")
+		}
+		if _, ok := s.todos[todo.ID]; ok {
+			return fmt.Errorf("snapshot: duplicate todo %s", todo.ID)
+		}
+		if _, ok := s.users[todo.UserID]; !ok {
+			return fmt.Errorf("snapshot: todo %s belongs to unknown user %s", todo.ID, todo.UserID)
+		}
+		if _, ok := s.users[todo.AssigneeID]; todo.AssigneeID != "" && !ok {
+			return fmt.Errorf("snapshot: todo %s is assigned to unknown user %s", todo.ID, todo.AssigneeID)
+		}
+		s.putTodo(todo)
+	}
+	for _, todo := range data.Todos {
+		if todo.ParentID == "" {
+			continue
+		}
+		if parent, ok := s.todos[todo.ParentID]; !ok || parent.UserID != todo.UserID {
+			return fmt.Errorf("snapshot: todo %s has unknown parent %s", todo.ID, todo.ParentID)
+		}
+	}
+
+	for _, tag := range data.Tags {
+		byName, ok := s.userTags[tag.UserID]
+		if !ok {
+			byName = make(map[string]*tagLinks)
+			s.userTags[tag.UserID] = byName
+		}
+		if _, ok := byName[tag.Name]; ok {
+			return fmt.Errorf("snapshot: duplicate tag %q of user %s", tag.Name, tag.UserID)
+		}
+		copied := tag.Tag
+		links := &tagLinks{tag: &copied, todos: make(map[string]struct{}, len(tag.TodoIDs))}
+		for _, todoID := range tag.TodoIDs {
+			todo, ok := s.todos[todoID]
+			if !ok || todo.UserID != tag.UserID {
+				return fmt.Errorf("snapshot: tag %q is attached to unknown todo %s", tag.Name, todoID)
+			}
+			links.todos[todoID] = struct{}{}
+			s.addToIndex(s.todoTags, todoID, tag.Name)
+		}
+		byName[tag.Name] = links
+	}
+
+	for _, token := range data.Tokens {
+		if token.ID == "" {
+			return errors.New("snapshot: token ID cannot be empty")
+		}
+		if _, ok := s.tokens[token.ID]; ok {
+			return fmt.Errorf("snapshot: duplicate token %s", token.ID)
+		}
+		if _, ok := s.users[token.UserID]; !ok {
+			return fmt.Errorf("snapshot: token %s belongs to unknown user %s", token.ID, token.UserID)
+		}
+		s.tokens[token.ID] = token
+	}
+
+	for _, share := range data.Shares {
+		if _, ok := s.shares[keyOf(share)]; ok {
+			return fmt.Errorf("snapshot: duplicate share of %s %s with %s", share.ResourceType, share.ResourceID, share.UserID)
+		}
+		if _, ok := s.users[share.UserID]; !ok {
+			return fmt.Errorf("snapshot: %s %s is shared with unknown user %s", share.ResourceType, share.ResourceID, share.UserID)
+		}
+		var ownerID string
+		switch share.ResourceType {
+		case domain.ShareResourceTodo:
+			if todo, ok := s.todos[share.ResourceID]; ok {
+				ownerID = todo.UserID
+			}
+		case domain.ShareResourceProject:
+			if project, ok := s.projects[share.ResourceID]; ok {
+				ownerID = project.UserID
+			}
+		}
+		if ownerID == "" || ownerID != share.OwnerID {
+			return fmt.Errorf("snapshot: share of unknown %s %s", share.ResourceType, share.ResourceID)
+		}
+		s.shares[keyOf(share)] = share
+	}
+
+	for _, comment := range data.Comments {
+		if comment.ID == "" {
+			return errors.New("snapshot: comment ID cannot be empty")
+		}
+		if _, ok := s.comments[comment.ID]; ok {
+			return fmt.Errorf("snapshot: duplicate comment %s", comment.ID)
+		}
+		if _, ok := s.todos[comment.TodoID]; !ok {
+			return fmt.Errorf("snapshot: comment %s is attached to unknown todo %s", comment.ID, comment.TodoID)
+		}
+		if _, ok := s.users[comment.AuthorID]; !ok {
+			return fmt.Errorf("snapshot: comment %s was written by unknown user %s", comment.ID, comment.AuthorID)
+		}
+		s.putComment(comment)
+	}
+
+	for _, attachment := range data.Attachments {
+		if attachment.ID == "" {
+			return errors.New("snapshot: attachment ID cannot be empty")
+		}
+		if _, ok := s.attachments[attachment.ID]; ok {
+			return fmt.Errorf("snapshot: duplicate attachment %s", attachment.ID)
+		}
+		if _, ok := s.todos[attachment.TodoID]; !ok {
+			return fmt.Errorf("snapshot: attachment %s is attached to unknown todo %s", attachment.ID, attachment.TodoID)
+		}
+		s.putAttachment(attachment)
+	}
+
+	s.auditLog = data.AuditLog
+	return nil
+}
@@ -0,0 +1,168 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+func fixtureStore(t *testing.T) *Store {
+	t.Helper()
+	store := NewStore()
+	fillFixture(t, context.Background(), store)
+	return store
+}
+
+// fillFixture fills the tenant of ctx with users, todos and everything attached to them
+func fillFixture(t *testing.T, ctx context.Context, store *Store) {
+	t.Helper()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, store.CreateUser(ctx, &domain.User{ID: "user1", Name: "John", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateUser(ctx, &domain.User{ID: "user2", Name: "Jane", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateProject(ctx, &domain.Project{ID: "project1", UserID: "user1", Name: "Work", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project1", AssigneeID: "user2", Title: "Parent", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", ParentID: "todo1", Title: "Child", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo3", UserID: "user2", Title: "Trashed", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.AddTodoTag(ctx, "todo1", "urgent"))
+	require.NoError(t, store.AddTodoTag(ctx, "todo2", "urgent"))
+	require.NoError(t, store.DeleteTodo(ctx, "todo3"))
+	require.NoError(t, store.PutShare(ctx, &domain.Share{ResourceType: domain.ShareResourceProject, ResourceID: "project1", OwnerID: "user1", UserID: "user2", Role: domain.ShareEditor, CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateComment(ctx, &domain.Comment{ID: "comment1", TodoID: "todo1", AuthorID: "user2", Body: "On it", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateAttachment(ctx, &domain.Attachment{ID: "attachment1", TodoID: "todo1", UploaderID: "user1", Name: "plan.pdf", ContentType: "application/pdf", Size: 4, Digest: "digest1", CreatedAt: now}))
+	require.NoError(t, store.AppendAuditEntry(ctx, &domain.AuditEntry{ID: "entry1", Actor: "admin", Action: domain.AuditActionCreate, EntityType: domain.AuditEntityUser, EntityID: "user1", At: now}))
+}
+
+func TestStore_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	var snapshot bytes.Buffer
+	require.NoError(t, fixtureStore(t).Snapshot(&snapshot))
+
+	restored := NewStore()
+	require.NoError(t, restored.Restore(bytes.NewReader(snapshot.Bytes())))
+
+	var again bytes.Buffer
+	require.NoError(t, restored.Snapshot(&again))
+	assert.Equal(t, snapshot.String(), again.String())
+
+	subtasks, err := restored.ListSubtasks(ctx, "todo1")
+	require.NoError(t, err)
+	require.Len(t, subtasks, 1)
+	assert.Equal(t, "todo2", subtasks[0].ID)
+	tagged, err := restored.ListTodosWithAllTags(ctx, "user1", []string{"urgent"})
+	require.NoError(t, err)
+	assert.Len(t, tagged, 2)
+	assigned, err := restored.ListAssignedTodos(ctx, "user2")
+	require.NoError(t, err)
+	require.Len(t, assigned, 1)
+	assert.Equal(t, "todo1", assigned[0].ID)
+	trashed, err := restored.GetDeletedTodo(ctx, "todo3")
+	require.NoError(t, err)
+	assert.True(t, trashed.IsDeleted())
+	shares, err := restored.ListUserShares(ctx, "user2")
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	assert.Equal(t, domain.ShareEditor, shares[0].Role)
+	comments, err := restored.ListTodoComments(ctx, "todo1")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "On it", comments[0].Body)
+	attachments, err := restored.ListBlobAttachments(ctx, "digest1")
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "attachment1", attachments[0].ID)
+	entries, err := restored.ListActorAuditEntries(ctx, "admin")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestStore_RestoreSingleTenantSnapshot(t *testing.T) {
+	ctx := context.Background()
+	snapshot := withChecksum(t, 1, `{"users":[{"id":"user1","name":"John"}],"todos":[{"id":"todo1","user_id":"user1","title":"Migrated"}],"projects":[],"tags":[],"audit_log":[]}`)
+
+	store := NewStore()
+	require.NoError(t, store.Restore(strings.NewReader(snapshot)))
+
+	// Snapshots from before tenants hold the data of the default tenant
+	todo, err := store.GetTodo(tenant.WithID(ctx, tenant.Default), "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, "Migrated", todo.Title)
+	_, err = store.GetTodo(tenant.WithID(ctx, "acme"), "todo1")
+	assert.Error(t, err)
+}
+
+func TestStore_RestoreRejectsInvalidSnapshots(t *testing.T) {
+	var snapshot bytes.Buffer
+	require.NoError(t, fixtureStore(t).Snapshot(&snapshot))
+	valid := snapshot.String()
+
+	tests := map[string]struct {
+		snapshot  string
+		expectErr string
+	}{
+		"Tampered data": {
+			snapshot:  strings.Replace(valid, `"Parent"`, `"Tampered"`, 1),
+			expectErr: ErrSnapshotChecksum.Error(),
+		},
+		"Unsupported version": {
+			snapshot:  strings.Replace(valid, `"version": 2`, `"version": 3`, 1),
+			expectErr: "unsupported snapshot version 3",
+		},
+		"Not a snapshot": {
+			snapshot:  `{"users": []}`,
+			expectErr: "decode snapshot",
+		},
+		"Todo of an unknown user": {
+			snapshot:  withChecksum(t, 2, `{"default":{"users":[],"todos":[{"id":"todo1","user_id":"ghost"}],"projects":[],"tags":[],"audit_log":[]}}`),
+			expectErr: "todo todo1 belongs to unknown user ghost",
+		},
+		"Todo assigned to an unknown user": {
+			snapshot:  withChecksum(t, 2, `{"default":{"users":[{"id":"user1"}],"todos":[{"id":"todo1","user_id":"user1","assignee_id":"ghost"}],"projects":[],"tags":[],"audit_log":[]}}`),
+			expectErr: "todo todo1 is assigned to unknown user ghost",
+		},
+		"Comment of an unknown todo": {
+			snapshot:  withChecksum(t, 2, `{"default":{"users":[{"id":"user1"}],"todos":[],"projects":[],"tags":[],"comments":[{"id":"comment1","todo_id":"ghost","author_id":"user1"}],"audit_log":[]}}`),
+			expectErr: "comment comment1 is attached to unknown todo ghost",
+		},
+		"Invalid tenant ID": {
+			snapshot:  withChecksum(t, 2, `{"Acme":{"users":[],"todos":[],"projects":[],"tags":[],"audit_log":[]}}`),
+			expectErr: `invalid tenant ID "Acme"`,
+		},
+		"Share of an unknown todo": {
+			snapshot:  withChecksum(t, 2, `{"default":{"users":[{"id":"user1"},{"id":"user2"}],"todos":[],"projects":[],"tags":[],"shares":[{"resource_type":"todo","resource_id":"ghost","owner_id":"user1","user_id":"user2","role":"viewer"}],"audit_log":[]}}`),
+			expectErr: "share of unknown todo ghost",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := fixtureStore(t)
+			err := store.Restore(strings.NewReader(tt.snapshot))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectErr)
+
+			// The store keeps its content
+			_, err = store.GetTodo(context.Background(), "todo1")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// withChecksum wraps compact snapshot data in a snapshot of the given version with a valid checksum
+func withChecksum(t *testing.T, version int, data string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(data))
+	file, err := json.Marshal(snapshotFile{Version: version, Checksum: hex.EncodeToString(sum[:]), Data: json.RawMessage(data)})
+	require.NoError(t, err)
+	return string(file)
+}
@@ -3,129 +3,531 @@ package inmemory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/changefeed"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
 )
 
-// Store is an implementation that satisfies both interfaces
-type Store struct {
-	users map[string]*domain.User
-	todos map[string]*domain.Todo
+// state holds the data of a Store
+// Its methods do no locking of their own; Store serializes access to them
+type state struct {
+	users    map[string]*domain.User
+	todos    map[string]*domain.Todo
+	projects map[string]*domain.Project
+	tokens   map[string]*domain.Token
+	shares   map[shareKey]*domain.Share
+	comments map[string]*domain.Comment
+	// attachments holds only metadata, the content is in a BlobStore
+	attachments map[string]*domain.Attachment
+
+	// userTodos indexes todo IDs by their owner so per-user queries
+	// do not have to scan every todo in the store
+	userTodos map[string]map[string]struct{}
+
+	// children indexes subtask IDs by their parent todo
+	children map[string]map[string]struct{}
+
+	// assigneeTodos indexes todo IDs by their assignee
+	assigneeTodos map[string]map[string]struct{}
+
+	// todoComments and todoAttachments index comment and attachment IDs by their todo
+	todoComments    map[string]map[string]struct{}
+	todoAttachments map[string]map[string]struct{}
+
+	// todoTags and userTags link todos and tags in both directions
+	todoTags map[string]map[string]struct{}
+	userTags map[string]map[string]*tagLinks
+
+	// auditLog holds audit entries in the order they were appended
+	auditLog []*domain.AuditEntry
+
+	// feed publishes a change after every successful write to users and todos
+	// Inside a transaction changes wait in pending until it commits,
+	// and undo logs how to reverse its writes should it roll back
+	feed      *changefeed.Feed
+	buffering bool
+	pending   []domain.Change
+	undo      []func()
 }
 
 var _ biginterface.DataStore = (*Store)(nil)
 var _ smallinterface.UserStore = (*Store)(nil)
 var _ smallinterface.TodoStore = (*Store)(nil)
 
-// NewStore creates a new in-memory store
-func NewStore() *Store {
-	return &Store{
-		users: make(map[string]*domain.User),
-		todos: make(map[string]*domain.Todo),
+// state is handed to transactions as their DataStore
+var _ biginterface.DataStore = (*state)(nil)
+
+func newState() *state {
+	return &state{
+		users:           make(map[string]*domain.User),
+		todos:           make(map[string]*domain.Todo),
+		projects:        make(map[string]*domain.Project),
+		tokens:          make(map[string]*domain.Token),
+		shares:          make(map[shareKey]*domain.Share),
+		comments:        make(map[string]*domain.Comment),
+		attachments:     make(map[string]*domain.Attachment),
+		userTodos:       make(map[string]map[string]struct{}),
+		children:        make(map[string]map[string]struct{}),
+		assigneeTodos:   make(map[string]map[string]struct{}),
+		todoComments:    make(map[string]map[string]struct{}),
+		todoAttachments: make(map[string]map[string]struct{}),
+		todoTags:        make(map[string]map[string]struct{}),
+		userTags:        make(map[string]map[string]*tagLinks),
+		feed:            changefeed.NewFeed(changeHistorySize, maxPendingChanges),
 	}
 }
 
 // User-related operations
-func (s *Store) GetUser(ctx context.Context, id string) (*domain.User, error) {
-	user, ok := s.users[id]
+func (s *state) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	user, ok := s.liveUser(id)
 	if !ok {
 		return nil, fmt.Errorf("user not found: %s", id)
 	}
 	return user, nil
 }
 
-func (s *Store) ListUsers(ctx context.Context) ([]*domain.User, error) {
+func (s *state) ListUsers(ctx context.Context) ([]*domain.User, error) {
 	users := make([]*domain.User, 0, len(s.users))
 	for _, user := range s.users {
-		users = append(users, user)
+		if !user.IsDeleted() {
+			users = append(users, user)
+		}
 	}
 	return users, nil
 }
 
-func (s *Store) CreateUser(ctx context.Context, user *domain.User) error {
+func (s *state) CreateUser(ctx context.Context, user *domain.User) error {
 	if user.ID == "" {
 		return fmt.Errorf("user ID cannot be empty")
 	}
-	s.users[user.ID] = user
+	set(s, s.users, user.ID, user)
+	s.publishUser(domain.ChangeCreated, user)
 	return nil
 }
 
-func (s *Store) UpdateUser(ctx context.Context, user *domain.User) error {
-	if _, ok := s.users[user.ID]; !ok {
+func (s *state) UpdateUser(ctx context.Context, user *domain.User) error {
+	if _, ok := s.liveUser(user.ID); !ok {
 		return fmt.Errorf("user not found: %s", user.ID)
 	}
-	s.users[user.ID] = user
+	set(s, s.users, user.ID, user)
+	s.publishUser(domain.ChangeUpdated, user)
 	return nil
 }
 
-func (s *Store) DeleteUser(ctx context.Context, id string) error {
-	if _, ok := s.users[id]; !ok {
+// DeleteUser moves a user to the trash
+func (s *state) DeleteUser(ctx context.Context, id string) error {
+	user, ok := s.liveUser(id)
+	if !ok {
 		return fmt.Errorf("user not found: %s", id)
 	}
-	delete(s.users, id)
+	updated := *user
+	now := time.Now()
+	updated.DeletedAt = &now
+	set(s, s.users, id, &updated)
+	s.publishUser(domain.ChangeDeleted, &updated)
 	return nil
 }
 
 // Todo-related operations
-func (s *Store) GetTodo(ctx context.Context, id string) (*domain.Todo, error) {
-	todo, ok := s.todos[id]
+func (s *state) GetTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	todo, ok := s.liveTodo(id)
 	if !ok {
 		return nil, fmt.Errorf("todo not found: %s", id)
 	}
 	return todo, nil
 }
 
-func (s *Store) ListTodos(ctx context.Context) ([]*domain.Todo, error) {
+func (s *state) ListTodos(ctx context.Context) ([]*domain.Todo, error) {
 	todos := make([]*domain.Todo, 0, len(s.todos))
 	for _, todo := range s.todos {
-		todos = append(todos, todo)
+		if !todo.IsDeleted() && !todo.IsArchived() {
+			todos = append(todos, todo)
+		}
 	}
 	return todos, nil
 }
 
-func (s *Store) ListUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
-	todos := make([]*domain.Todo, 0)
-	for _, todo := range s.todos {
-		if todo.UserID == userID {
-			todos = append(todos, todo)
+// ListUserTodos returns a user's todos that are not archived in their manual order,
+// with each todo directly followed by its subtasks
+func (s *state) ListUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	todos := s.filterUserTodos(userID, func(todo *domain.Todo) bool {
+		return !todo.IsArchived()
+	})
+	return orderAsTree(todos), nil
+}
+
+// ListArchivedTodos returns a user's archived todos in the same order as ListUserTodos
+func (s *state) ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	todos := s.filterUserTodos(userID, func(todo *domain.Todo) bool {
+		return todo.IsArchived()
+	})
+	return orderAsTree(todos), nil
+}
+
+// ListAssignedTodos returns the todos assigned to a user that are neither archived nor deleted,
+// ordered by owner and then as ListUserTodos orders them
+func (s *state) ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error) {
+	byOwner := make(map[string][]*domain.Todo)
+	owners := make([]string, 0)
+	for id := range s.assigneeTodos[assigneeID] {
+		todo := s.todos[id]
+		if todo.IsDeleted() || todo.IsArchived() {
+			continue
+		}
+		if _, ok := byOwner[todo.UserID]; !ok {
+			owners = append(owners, todo.UserID)
 		}
+		byOwner[todo.UserID] = append(byOwner[todo.UserID], todo)
+	}
+	sort.Strings(owners)
+
+	todos := make([]*domain.Todo, 0)
+	for _, owner := range owners {
+		todos = append(todos, orderAsTree(byOwner[owner])...)
 	}
 	return todos, nil
 }
 
-func (s *Store) CreateTodo(ctx context.Context, todo *domain.Todo) error {
+// CreateTodo appends the todo after its siblings unless its position is set
+func (s *state) CreateTodo(ctx context.Context, todo *domain.Todo) error {
 	if todo.ID == "" {
 		return fmt.Errorf("todo ID cannot be empty")
 	}
-	s.todos[todo.ID] = todo
+	if !todo.PositionSet {
+		todo.Position = s.lastPosition(todo.UserID, todo.ParentID) + 1
+	}
+	todo.PositionSet = false
+	s.putTodo(todo)
+	s.publishTodo(domain.ChangeCreated, todo)
 	return nil
 }
 
-func (s *Store) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
-	if _, ok := s.todos[todo.ID]; !ok {
+func (s *state) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
+	if _, ok := s.liveTodo(todo.ID); !ok {
 		return fmt.Errorf("todo not found: %s", todo.ID)
 	}
-	s.todos[todo.ID] = todo
+	s.putTodo(todo)
+	s.publishTodo(domain.ChangeUpdated, todo)
 	return nil
 }
 
-func (s *Store) DeleteTodo(ctx context.Context, id string) error {
-	if _, ok := s.todos[id]; !ok {
+// DeleteTodo moves a todo to the trash together with its live subtasks, all at the same time,
+// so that no live todo is ever left under a trashed parent
+// Their indexes and tags are kept so that they can be restored as they were
+func (s *state) DeleteTodo(ctx context.Context, id string) error {
+	todo, ok := s.liveTodo(id)
+	if !ok {
 		return fmt.Errorf("todo not found: %s", id)
 	}
-	delete(s.todos, id)
+	now := time.Now()
+	for _, t := range append([]*domain.Todo{todo}, s.descendants(id)...) {
+		if t.IsDeleted() {
+			continue
+		}
+		deleted := s.changeTodo(t, func(todo *domain.Todo) {
+			todo.DeletedAt = &now
+		})
+		s.publishTodo(domain.ChangeDeleted, deleted)
+	}
 	return nil
 }
 
-func (s *Store) MarkTodoComplete(ctx context.Context, id string) error {
-	todo, ok := s.todos[id]
+func (s *state) MarkTodoComplete(ctx context.Context, id string) error {
+	todo, ok := s.liveTodo(id)
 	if !ok {
 		return fmt.Errorf("todo not found: %s", id)
 	}
-	todo.Completed = true
-	todo.UpdatedAt = time.Now()
+	now := time.Now()
+	completed := s.changeTodo(todo, func(todo *domain.Todo) {
+		todo.Completed = true
+		todo.CompletedAt = &now
+		todo.UpdatedAt = now
+	})
+	s.publishTodo(domain.ChangeCompleted, completed)
 	return nil
 }
+
+func (s *state) ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error) {
+	return s.subtasks(parentID), nil
+}
+
+// Scheduling operations
+func (s *state) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	todo, ok := s.liveTodo(id)
+	if !ok {
+		return fmt.Errorf("todo not found: %s", id)
+	}
+	updated := s.changeTodo(todo, func(todo *domain.Todo) {
+		todo.DueAt = dueAt
+		todo.UpdatedAt = time.Now()
+	})
+	s.publishTodo(domain.ChangeUpdated, updated)
+	return nil
+}
+
+func (s *state) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	todo, ok := s.liveTodo(id)
+	if !ok {
+		return fmt.Errorf("todo not found: %s", id)
+	}
+	updated := s.changeTodo(todo, func(todo *domain.Todo) {
+		todo.Priority = priority
+		todo.UpdatedAt = time.Now()
+	})
+	s.publishTodo(domain.ChangeUpdated, updated)
+	return nil
+}
+
+func (s *state) ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error) {
+	todos := s.filterUserTodos(userID, func(todo *domain.Todo) bool {
+		return !todo.IsArchived() && todo.IsOverdue(now)
+	})
+	sortByDueDate(todos)
+	return todos, nil
+}
+
+func (s *state) ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error) {
+	todos := s.filterUserTodos(userID, func(todo *domain.Todo) bool {
+		return !todo.IsArchived() && !todo.Completed && todo.DueAt != nil && !todo.DueAt.Before(from) && todo.DueAt.Before(to)
+	})
+	sortByDueDate(todos)
+	return todos, nil
+}
+
+func (s *state) ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	todos := s.filterUserTodos(userID, func(todo *domain.Todo) bool {
+		return !todo.IsArchived()
+	})
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].Priority != todos[j].Priority {
+			return todos[i].Priority > todos[j].Priority
+		}
+		return dueBefore(todos[i], todos[j])
+	})
+	return todos, nil
+}
+
+// liveUser returns a user unless it is missing or in the trash
+func (s *state) liveUser(id string) (*domain.User, bool) {
+	user, ok := s.users[id]
+	if !ok || user.IsDeleted() {
+		return nil, false
+	}
+	return user, true
+}
+
+// liveTodo returns a todo unless it is missing or in the trash
+func (s *state) liveTodo(id string) (*domain.Todo, bool) {
+	todo, ok := s.todos[id]
+	if !ok || todo.IsDeleted() {
+		return nil, false
+	}
+	return todo, true
+}
+
+// putTodo stores a todo and keeps the indexes in sync,
+// including when an update moves the todo to another user or parent
+func (s *state) putTodo(todo *domain.Todo) {
+	if existing, ok := s.todos[todo.ID]; ok {
+		s.unindexTodo(existing)
+		if existing.UserID != todo.UserID {
+			s.moveTags(todo.ID, existing.UserID, todo.UserID)
+		}
+	}
+	set(s, s.todos, todo.ID, todo)
+	s.addToIndex(s.userTodos, todo.UserID, todo.ID)
+	if todo.ParentID != "" {
+		s.addToIndex(s.children, todo.ParentID, todo.ID)
+	}
+	if todo.AssigneeID != "" {
+		s.addToIndex(s.assigneeTodos, todo.AssigneeID, todo.ID)
+	}
+}
+
+// changeTodo stores a changed copy of a todo and returns it
+// change must leave the fields the indexes are keyed by alone
+func (s *state) changeTodo(todo *domain.Todo, change func(todo *domain.Todo)) *domain.Todo {
+	updated := *todo
+	change(&updated)
+	set(s, s.todos, updated.ID, &updated)
+	return &updated
+}
+
+func (s *state) unindexTodo(todo *domain.Todo) {
+	s.removeFromIndex(s.userTodos, todo.UserID, todo.ID)
+	if todo.ParentID != "" {
+		s.removeFromIndex(s.children, todo.ParentID, todo.ID)
+	}
+	if todo.AssigneeID != "" {
+		s.removeFromIndex(s.assigneeTodos, todo.AssigneeID, todo.ID)
+	}
+}
+
+func (s *state) addToIndex(index map[string]map[string]struct{}, key, id string) {
+	ids, ok := index[key]
+	if !ok {
+		ids = make(map[string]struct{})
+		set(s, index, key, ids)
+	}
+	set(s, ids, id, struct{}{})
+}
+
+func (s *state) removeFromIndex(index map[string]map[string]struct{}, key, id string) {
+	ids, ok := index[key]
+	if !ok {
+		return
+	}
+	unset(s, ids, id)
+	if len(ids) == 0 {
+		unset(s, index, key)
+	}
+}
+
+// filterUserTodos walks only the todos owned by userID, skipping trashed ones
+func (s *state) filterUserTodos(userID string, keep func(*domain.Todo) bool) []*domain.Todo {
+	ids := s.userTodos[userID]
+	todos := make([]*domain.Todo, 0, len(ids))
+	for id := range ids {
+		if todo := s.todos[id]; !todo.IsDeleted() && keep(todo) {
+			todos = append(todos, todo)
+		}
+	}
+	return todos
+}
+
+// subtasks returns the direct subtasks of a todo sorted by position, skipping trashed ones
+func (s *state) subtasks(parentID string) []*domain.Todo {
+	ids := s.children[parentID]
+	todos := make([]*domain.Todo, 0, len(ids))
+	for id := range ids {
+		if todo := s.todos[id]; !todo.IsDeleted() {
+			todos = append(todos, todo)
+		}
+	}
+	sortByPosition(todos)
+	return todos
+}
+
+// descendants returns every todo below a todo, trashed ones included, parents before their subtasks
+func (s *state) descendants(id string) []*domain.Todo {
+	todos := make([]*domain.Todo, 0)
+	for queue := []string{id}; len(queue) > 0; queue = queue[1:] {
+		children := make([]*domain.Todo, 0, len(s.children[queue[0]]))
+		for childID := range s.children[queue[0]] {
+			children = append(children, s.todos[childID])
+		}
+		sortByPosition(children)
+		for _, child := range children {
+			todos = append(todos, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return todos
+}
+
+// lastPosition returns the highest position among the todos sharing a parent,
+// or among a user's top-level todos when parentID is empty
+func (s *state) lastPosition(userID, parentID string) float64 {
+	var siblings []*domain.Todo
+	if parentID != "" {
+		siblings = s.subtasks(parentID)
+	} else {
+		siblings = s.filterUserTodos(userID, func(todo *domain.Todo) bool {
+			return todo.ParentID == ""
+		})
+	}
+
+	last := 0.0
+	for _, sibling := range siblings {
+		if sibling.Position > last {
+			last = sibling.Position
+		}
+	}
+	return last
+}
+
+// orderAsTree sorts todos depth-first: siblings by position, each followed by its own subtasks
+// Todos whose parent is not in the list are treated as top-level
+func orderAsTree(todos []*domain.Todo) []*domain.Todo {
+	present := make(map[string]struct{}, len(todos))
+	for _, todo := range todos {
+		present[todo.ID] = struct{}{}
+	}
+	byParent := make(map[string][]*domain.Todo)
+	for _, todo := range todos {
+		parentID := todo.ParentID
+		if _, ok := present[parentID]; !ok {
+			parentID = ""
+		}
+		byParent[parentID] = append(byParent[parentID], todo)
+	}
+
+	ordered := make([]*domain.Todo, 0, len(todos))
+	visited := make(map[string]struct{}, len(todos))
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		siblings := byParent[parentID]
+		sortByPosition(siblings)
+		for _, todo := range siblings {
+			ordered = append(ordered, todo)
+			visited[todo.ID] = struct{}{}
+			walk(todo.ID)
+		}
+	}
+	walk("")
+
+	// Todos caught in a parent cycle are unreachable from the top level, keep them anyway
+	if len(ordered) < len(todos) {
+		rest := make([]*domain.Todo, 0, len(todos)-len(ordered))
+		for _, todo := range todos {
+			if _, ok := visited[todo.ID]; !ok {
+				rest = append(rest, todo)
+			}
+		}
+		sortByPosition(rest)
+		ordered = append(ordered, rest...)
+	}
+	return ordered
+}
+
+// sortByPosition orders todos by their position among their siblings,
+// falling back to creation order for equal positions
+func sortByPosition(todos []*domain.Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].Position != todos[j].Position {
+			return todos[i].Position < todos[j].Position
+		}
+		if !todos[i].CreatedAt.Equal(todos[j].CreatedAt) {
+			return todos[i].CreatedAt.Before(todos[j].CreatedAt)
+		}
+		return todos[i].ID < todos[j].ID
+	})
+}
+
+// sortByDueDate orders todos by due date, earliest first
+func sortByDueDate(todos []*domain.Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return dueBefore(todos[i], todos[j])
+	})
+}
+
+// dueBefore reports whether a is due before b
+// Todos without a due date come last, ties are broken by ID for a stable order
+func dueBefore(a, b *domain.Todo) bool {
+	switch {
+	case a.DueAt == nil && b.DueAt == nil:
+		return a.ID < b.ID
+	case a.DueAt == nil:
+		return false
+	case b.DueAt == nil:
+		return true
+	case !a.DueAt.Equal(*b.DueAt):
+		return a.DueAt.Before(*b.DueAt)
+	default:
+		return a.ID < b.ID
+	}
+}
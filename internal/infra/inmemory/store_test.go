@@ -0,0 +1,59 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// treeStore returns a store holding todo1 with the subtask todo2
+func treeStore(t *testing.T) *Store {
+	t.Helper()
+	ctx := context.Background()
+	store := NewStore()
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Parent"}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo2", UserID: "user1", ParentID: "todo1", Title: "Child"}))
+	return store
+}
+
+func TestStore_CreateTodoPosition(t *testing.T) {
+	tests := map[string]struct {
+		todo           *domain.Todo
+		expectPosition float64
+	}{
+		"Unset position is after the siblings": {
+			todo:           &domain.Todo{ID: "todo3", UserID: "user1"},
+			expectPosition: 2,
+		},
+		"Unset position of a subtask is after its siblings": {
+			todo:           &domain.Todo{ID: "todo3", UserID: "user1", ParentID: "todo1"},
+			expectPosition: 2,
+		},
+		"Position 0 is kept when set": {
+			todo:           &domain.Todo{ID: "todo3", UserID: "user1", Position: 0, PositionSet: true},
+			expectPosition: 0,
+		},
+		"Position before the first sibling is kept when set": {
+			todo:           &domain.Todo{ID: "todo3", UserID: "user1", Position: -1, PositionSet: true},
+			expectPosition: -1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := treeStore(t)
+
+			require.NoError(t, store.CreateTodo(ctx, tt.todo))
+
+			todo, err := store.GetTodo(ctx, "todo3")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectPosition, todo.Position)
+			assert.False(t, todo.PositionSet)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.TagStore = (*Store)(nil)
+
+// tagLinks holds a tag together with the todos it is attached to
+type tagLinks struct {
+	tag   *domain.Tag
+	todos map[string]struct{}
+}
+
+// Tag-related operations
+func (s *state) AddTodoTag(ctx context.Context, todoID string, tag string) error {
+	todo, ok := s.liveTodo(todoID)
+	if !ok {
+		return fmt.Errorf("todo not found: %s", todoID)
+	}
+	if tag == "" {
+		return fmt.Errorf("tag cannot be empty")
+	}
+	s.linkTag(todo.UserID, todoID, tag, time.Now())
+	return nil
+}
+
+func (s *state) RemoveTodoTag(ctx context.Context, todoID string, tag string) error {
+	todo, ok := s.liveTodo(todoID)
+	if !ok {
+		return fmt.Errorf("todo not found: %s", todoID)
+	}
+	if _, ok := s.todoTags[todoID][tag]; !ok {
+		return fmt.Errorf("tag not found on todo %s: %s", todoID, tag)
+	}
+	s.unlinkTag(todo.UserID, todoID, tag)
+	return nil
+}
+
+func (s *state) ListTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error) {
+	todo, ok := s.liveTodo(todoID)
+	if !ok {
+		return nil, fmt.Errorf("todo not found: %s", todoID)
+	}
+	tags := make([]*domain.Tag, 0, len(s.todoTags[todoID]))
+	for name := range s.todoTags[todoID] {
+		tags = append(tags, s.userTags[todo.UserID][name].tag)
+	}
+	sortTags(tags)
+	return tags, nil
+}
+
+func (s *state) ListUserTags(ctx context.Context, userID string) ([]*domain.Tag, error) {
+	tags := make([]*domain.Tag, 0, len(s.userTags[userID]))
+	for _, links := range s.userTags[userID] {
+		if s.hasLiveTodo(links) {
+			tags = append(tags, links.tag)
+		}
+	}
+	sortTags(tags)
+	return tags, nil
+}
+
+func (s *state) ListTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	if len(tags) == 0 {
+		return []*domain.Todo{}, nil
+	}
+	// Walk the smallest tag's todos and check the remaining tags against them
+	var smallest map[string]struct{}
+	for _, name := range tags {
+		links, ok := s.userTags[userID][name]
+		if !ok {
+			return []*domain.Todo{}, nil
+		}
+		if smallest == nil || len(links.todos) < len(smallest) {
+			smallest = links.todos
+		}
+	}
+
+	todos := make([]*domain.Todo, 0, len(smallest))
+	for todoID := range smallest {
+		if todo, ok := s.liveTodo(todoID); ok && !todo.IsArchived() && s.hasAllTags(todoID, tags) {
+			todos = append(todos, todo)
+		}
+	}
+	sortTodosByID(todos)
+	return todos, nil
+}
+
+func (s *state) ListTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	seen := make(map[string]struct{})
+	todos := make([]*domain.Todo, 0)
+	for _, name := range tags {
+		links, ok := s.userTags[userID][name]
+		if !ok {
+			continue
+		}
+		for todoID := range links.todos {
+			todo, ok := s.liveTodo(todoID)
+			if _, dup := seen[todoID]; dup || !ok || todo.IsArchived() {
+				continue
+			}
+			seen[todoID] = struct{}{}
+			todos = append(todos, todo)
+		}
+	}
+	sortTodosByID(todos)
+	return todos, nil
+}
+
+// hasLiveTodo reports whether a tag is attached to at least one todo outside the trash
+func (s *state) hasLiveTodo(links *tagLinks) bool {
+	for todoID := range links.todos {
+		if _, ok := s.liveTodo(todoID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *state) hasAllTags(todoID string, tags []string) bool {
+	for _, name := range tags {
+		if _, ok := s.todoTags[todoID][name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *state) linkTag(userID, todoID, name string, now time.Time) {
+	byName, ok := s.userTags[userID]
+	if !ok {
+		byName = make(map[string]*tagLinks)
+		set(s, s.userTags, userID, byName)
+	}
+	links, ok := byName[name]
+	if !ok {
+		links = &tagLinks{
+			tag:   &domain.Tag{UserID: userID, Name: name, CreatedAt: now},
+			todos: make(map[string]struct{}),
+		}
+		set(s, byName, name, links)
+	}
+	set(s, links.todos, todoID, struct{}{})
+	s.addToIndex(s.todoTags, todoID, name)
+}
+
+// unlinkTag detaches a tag from a todo and forgets the tag
+// once no todo of the user carries it anymore
+func (s *state) unlinkTag(userID, todoID, name string) {
+	if links, ok := s.userTags[userID][name]; ok {
+		unset(s, links.todos, todoID)
+		if len(links.todos) == 0 {
+			unset(s, s.userTags[userID], name)
+			if len(s.userTags[userID]) == 0 {
+				unset(s, s.userTags, userID)
+			}
+		}
+	}
+	s.removeFromIndex(s.todoTags, todoID, name)
+}
+
+// unlinkAllTags removes every tag link of a todo, e.g. when it is purged
+func (s *state) unlinkAllTags(todo *domain.Todo) {
+	for name := range s.todoTags[todo.ID] {
+		s.unlinkTag(todo.UserID, todo.ID, name)
+	}
+}
+
+// moveTags re-homes a todo's tag links when its owner changes
+func (s *state) moveTags(todoID, fromUserID, toUserID string) {
+	names := make([]string, 0, len(s.todoTags[todoID]))
+	for name := range s.todoTags[todoID] {
+		names = append(names, name)
+	}
+	now := time.Now()
+	for _, name := range names {
+		s.unlinkTag(fromUserID, todoID, name)
+		s.linkTag(toUserID, todoID, name, now)
+	}
+}
+
+func sortTags(tags []*domain.Tag) {
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+}
+
+func sortTodosByID(todos []*domain.Todo) {
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].ID < todos[j].ID
+	})
+}
@@ -0,0 +1,345 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+var (
+	acme   = tenant.WithID(context.Background(), "acme")
+	globex = tenant.WithID(context.Background(), "globex")
+)
+
+// tenantSnapshot returns the content of a tenant as JSON, to tell whether it changed
+func tenantSnapshot(t *testing.T, store *Store, ctx context.Context) string {
+	t.Helper()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	st, ok := store.tenants[tenant.FromContext(ctx)]
+	if !ok {
+		st = emptyState
+	}
+	data, err := json.Marshal(st.snapshot())
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestStore_TenantsShareIDs(t *testing.T) {
+	store := NewStore()
+	fillFixture(t, acme, store)
+	// The same IDs do not collide in another tenant
+	fillFixture(t, globex, store)
+
+	todo, err := store.GetTodo(acme, "todo1")
+	require.NoError(t, err)
+	updated := *todo
+	updated.Title = "Acme plans"
+	require.NoError(t, store.UpdateTodo(acme, &updated))
+
+	todo, err = store.GetTodo(globex, "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, "Parent", todo.Title)
+
+	// Contexts without a tenant act on the default one
+	_, err = store.GetTodo(context.Background(), "todo1")
+	assert.Error(t, err)
+	require.NoError(t, store.CreateUser(context.Background(), &domain.User{ID: "user1"}))
+	user, err := store.GetUser(tenant.WithID(context.Background(), tenant.Default), "user1")
+	require.NoError(t, err)
+	assert.Empty(t, user.Name)
+}
+
+func TestStore_ReadsOnlySeeTheirTenant(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := now.Add(-time.Hour)
+	store := NewStore()
+	fillFixture(t, acme, store)
+	require.NoError(t, store.CreateUser(acme, &domain.User{ID: "user3"}))
+	require.NoError(t, store.DeleteUser(acme, "user3"))
+	require.NoError(t, store.SetTodoDueDate(acme, "todo1", &due))
+	require.NoError(t, store.CreateTodo(acme, &domain.Todo{ID: "todo4", UserID: "user1", ArchivedAt: &now}))
+	require.NoError(t, store.CreateToken(acme, &domain.Token{ID: "token1", UserID: "user1", Role: domain.RoleUser}))
+
+	tests := map[string]func(ctx context.Context) (interface{}, error){
+		"GetUser":         func(ctx context.Context) (interface{}, error) { return store.GetUser(ctx, "user1") },
+		"ListUsers":       func(ctx context.Context) (interface{}, error) { return store.ListUsers(ctx) },
+		"GetDeletedUser":  func(ctx context.Context) (interface{}, error) { return store.GetDeletedUser(ctx, "user3") },
+		"ListDeletedUser": func(ctx context.Context) (interface{}, error) { return store.ListDeletedUsers(ctx) },
+		"GetTodo":         func(ctx context.Context) (interface{}, error) { return store.GetTodo(ctx, "todo1") },
+		"ListTodos":       func(ctx context.Context) (interface{}, error) { return store.ListTodos(ctx) },
+		"ListUserTodos":   func(ctx context.Context) (interface{}, error) { return store.ListUserTodos(ctx, "user1") },
+		"ListSubtasks":    func(ctx context.Context) (interface{}, error) { return store.ListSubtasks(ctx, "todo1") },
+		"ListArchivedTodos": func(ctx context.Context) (interface{}, error) {
+			return store.ListArchivedTodos(ctx, "user1")
+		},
+		"ListAssignedTodos": func(ctx context.Context) (interface{}, error) {
+			return store.ListAssignedTodos(ctx, "user2")
+		},
+		"ListOverdueTodos": func(ctx context.Context) (interface{}, error) {
+			return store.ListOverdueTodos(ctx, "user1", now)
+		},
+		"ListTodosDueBetween": func(ctx context.Context) (interface{}, error) {
+			return store.ListTodosDueBetween(ctx, "user1", due, now)
+		},
+		"ListUserTodosByPriority": func(ctx context.Context) (interface{}, error) {
+			return store.ListUserTodosByPriority(ctx, "user1")
+		},
+		"GetDeletedTodo":   func(ctx context.Context) (interface{}, error) { return store.GetDeletedTodo(ctx, "todo3") },
+		"ListDeletedTodos": func(ctx context.Context) (interface{}, error) { return store.ListDeletedTodos(ctx, "user2") },
+		"ListTodoTags":     func(ctx context.Context) (interface{}, error) { return store.ListTodoTags(ctx, "todo1") },
+		"ListUserTags":     func(ctx context.Context) (interface{}, error) { return store.ListUserTags(ctx, "user1") },
+		"ListTodosWithAllTags": func(ctx context.Context) (interface{}, error) {
+			return store.ListTodosWithAllTags(ctx, "user1", []string{"urgent"})
+		},
+		"ListTodosWithAnyTag": func(ctx context.Context) (interface{}, error) {
+			return store.ListTodosWithAnyTag(ctx, "user1", []string{"urgent"})
+		},
+		"GetProject":       func(ctx context.Context) (interface{}, error) { return store.GetProject(ctx, "project1") },
+		"ListUserProjects": func(ctx context.Context) (interface{}, error) { return store.ListUserProjects(ctx, "user1") },
+		"GetToken":         func(ctx context.Context) (interface{}, error) { return store.GetToken(ctx, "token1") },
+		"ListUserTokens":   func(ctx context.Context) (interface{}, error) { return store.ListUserTokens(ctx, "user1") },
+		"GetShare": func(ctx context.Context) (interface{}, error) {
+			return store.GetShare(ctx, domain.ShareResourceProject, "project1", "user2")
+		},
+		"ListResourceShares": func(ctx context.Context) (interface{}, error) {
+			return store.ListResourceShares(ctx, domain.ShareResourceProject, "project1")
+		},
+		"ListUserShares":   func(ctx context.Context) (interface{}, error) { return store.ListUserShares(ctx, "user2") },
+		"GetComment":       func(ctx context.Context) (interface{}, error) { return store.GetComment(ctx, "comment1") },
+		"ListTodoComments": func(ctx context.Context) (interface{}, error) { return store.ListTodoComments(ctx, "todo1") },
+		"GetAttachment":    func(ctx context.Context) (interface{}, error) { return store.GetAttachment(ctx, "attachment1") },
+		"ListTodoAttachments": func(ctx context.Context) (interface{}, error) {
+			return store.ListTodoAttachments(ctx, "todo1")
+		},
+		"ListBlobAttachments": func(ctx context.Context) (interface{}, error) {
+			return store.ListBlobAttachments(ctx, "digest1")
+		},
+		"ListEntityAuditEntries": func(ctx context.Context) (interface{}, error) {
+			return store.ListEntityAuditEntries(ctx, domain.AuditEntityUser, "user1")
+		},
+		"ListActorAuditEntries": func(ctx context.Context) (interface{}, error) {
+			return store.ListActorAuditEntries(ctx, "admin")
+		},
+	}
+
+	for name, read := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := read(acme)
+			require.NoError(t, err)
+			assert.NotEmpty(t, result)
+
+			result, err = read(globex)
+			if err == nil {
+				assert.Empty(t, result)
+			}
+		})
+	}
+
+	// Reads of a tenant without data leave no trace of it
+	assert.NotContains(t, store.tenants, "globex")
+}
+
+// writeTime is the time the fixture writes set
+var writeTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// fixtureWrites holds a write of every kind the store supports, each of which changes a store filled by fillFixture
+var fixtureWrites = map[string]func(ctx context.Context, store *Store) error{
+	"CreateUser": func(ctx context.Context, store *Store) error {
+		return store.CreateUser(ctx, &domain.User{ID: "user3"})
+	},
+	"UpdateUser": func(ctx context.Context, store *Store) error {
+		return store.UpdateUser(ctx, &domain.User{ID: "user1", Name: "Renamed"})
+	},
+	"DeleteUser": func(ctx context.Context, store *Store) error { return store.DeleteUser(ctx, "user2") },
+	"RestoreUser": func(ctx context.Context, store *Store) error {
+		if err := store.DeleteUser(ctx, "user2"); err != nil {
+			return err
+		}
+		return store.RestoreUser(ctx, "user2")
+	},
+	"PurgeUser": func(ctx context.Context, store *Store) error {
+		if err := store.DeleteUser(ctx, "user2"); err != nil {
+			return err
+		}
+		return store.PurgeUser(ctx, "user2")
+	},
+	"CreateTodo": func(ctx context.Context, store *Store) error {
+		return store.CreateTodo(ctx, &domain.Todo{ID: "todo4", UserID: "user1"})
+	},
+	"UpdateTodo": func(ctx context.Context, store *Store) error {
+		return store.UpdateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Renamed"})
+	},
+	"DeleteTodo":       func(ctx context.Context, store *Store) error { return store.DeleteTodo(ctx, "todo1") },
+	"MarkTodoComplete": func(ctx context.Context, store *Store) error { return store.MarkTodoComplete(ctx, "todo1") },
+	"SetTodoDueDate": func(ctx context.Context, store *Store) error {
+		return store.SetTodoDueDate(ctx, "todo1", &writeTime)
+	},
+	"SetTodoPriority": func(ctx context.Context, store *Store) error {
+		return store.SetTodoPriority(ctx, "todo1", domain.PriorityHigh)
+	},
+	"RestoreTodo": func(ctx context.Context, store *Store) error { return store.RestoreTodo(ctx, "todo3") },
+	"PurgeTodo":   func(ctx context.Context, store *Store) error { return store.PurgeTodo(ctx, "todo3") },
+	"PurgeTodosDeletedBefore": func(ctx context.Context, store *Store) error {
+		purged, err := store.PurgeTodosDeletedBefore(ctx, time.Now().Add(time.Hour))
+		if err == nil && purged == 0 {
+			err = errors.New("nothing purged")
+		}
+		return err
+	},
+	"CreateUsers": func(ctx context.Context, store *Store) error {
+		return store.CreateUsers(ctx, []*domain.User{{ID: "user3"}})
+	},
+	"DeleteUsers": func(ctx context.Context, store *Store) error { return store.DeleteUsers(ctx, []string{"user2"}) },
+	"CreateTodos": func(ctx context.Context, store *Store) error {
+		return store.CreateTodos(ctx, []*domain.Todo{{ID: "todo4", UserID: "user1"}})
+	},
+	"UpdateTodos": func(ctx context.Context, store *Store) error {
+		return store.UpdateTodos(ctx, []*domain.Todo{{ID: "todo1", UserID: "user1", Title: "Renamed"}})
+	},
+	"DeleteTodos": func(ctx context.Context, store *Store) error { return store.DeleteTodos(ctx, []string{"todo1"}) },
+	"MarkTodosComplete": func(ctx context.Context, store *Store) error {
+		return store.MarkTodosComplete(ctx, []string{"todo1"})
+	},
+	"AddTodoTag":    func(ctx context.Context, store *Store) error { return store.AddTodoTag(ctx, "todo1", "later") },
+	"RemoveTodoTag": func(ctx context.Context, store *Store) error { return store.RemoveTodoTag(ctx, "todo1", "urgent") },
+	"CreateProject": func(ctx context.Context, store *Store) error {
+		return store.CreateProject(ctx, &domain.Project{ID: "project2", UserID: "user1"})
+	},
+	"UpdateProject": func(ctx context.Context, store *Store) error {
+		return store.UpdateProject(ctx, &domain.Project{ID: "project1", UserID: "user1", Name: "Renamed"})
+	},
+	"DeleteProject": func(ctx context.Context, store *Store) error { return store.DeleteProject(ctx, "project1") },
+	"CreateToken": func(ctx context.Context, store *Store) error {
+		return store.CreateToken(ctx, &domain.Token{ID: "token1", UserID: "user1"})
+	},
+	"RevokeToken": func(ctx context.Context, store *Store) error {
+		if err := store.CreateToken(ctx, &domain.Token{ID: "token1", UserID: "user1"}); err != nil {
+			return err
+		}
+		return store.RevokeToken(ctx, "token1", writeTime)
+	},
+	"PutShare": func(ctx context.Context, store *Store) error {
+		return store.PutShare(ctx, &domain.Share{ResourceType: domain.ShareResourceTodo, ResourceID: "todo1", OwnerID: "user1", UserID: "user2", Role: domain.ShareViewer})
+	},
+	"DeleteShare": func(ctx context.Context, store *Store) error {
+		return store.DeleteShare(ctx, domain.ShareResourceProject, "project1", "user2")
+	},
+	"CreateComment": func(ctx context.Context, store *Store) error {
+		return store.CreateComment(ctx, &domain.Comment{ID: "comment2", TodoID: "todo1", AuthorID: "user1"})
+	},
+	"UpdateComment": func(ctx context.Context, store *Store) error {
+		return store.UpdateComment(ctx, &domain.Comment{ID: "comment1", TodoID: "todo1", AuthorID: "user2", Body: "Done"})
+	},
+	"DeleteComment": func(ctx context.Context, store *Store) error { return store.DeleteComment(ctx, "comment1") },
+	"CreateAttachment": func(ctx context.Context, store *Store) error {
+		return store.CreateAttachment(ctx, &domain.Attachment{ID: "attachment2", TodoID: "todo1", Digest: "digest2"})
+	},
+	"DeleteAttachment": func(ctx context.Context, store *Store) error {
+		return store.DeleteAttachment(ctx, "attachment1")
+	},
+	"AppendAuditEntry": func(ctx context.Context, store *Store) error {
+		return store.AppendAuditEntry(ctx, &domain.AuditEntry{ID: "entry2", Actor: "admin"})
+	},
+	"WithinTx": func(ctx context.Context, store *Store) error {
+		return store.WithinTx(ctx, func(ctx context.Context, tx biginterface.DataStore) error {
+			return tx.CreateUser(ctx, &domain.User{ID: "user3"})
+		})
+	},
+	"RunInTx": func(ctx context.Context, store *Store) error {
+		return store.RunInTx(ctx, func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error {
+			return todos.CreateTodo(ctx, &domain.Todo{ID: "todo4", UserID: "user1"})
+		})
+	},
+}
+
+func TestStore_WritesStayInTheirTenant(t *testing.T) {
+	for name, write := range fixtureWrites {
+		t.Run(name, func(t *testing.T) {
+			store := NewStore()
+			fillFixture(t, acme, store)
+			fillFixture(t, globex, store)
+			acmeBefore := tenantSnapshot(t, store, acme)
+			globexBefore := tenantSnapshot(t, store, globex)
+
+			require.NoError(t, write(acme, store))
+
+			assert.NotEqual(t, acmeBefore, tenantSnapshot(t, store, acme))
+			assert.Equal(t, globexBefore, tenantSnapshot(t, store, globex))
+		})
+	}
+}
+
+func TestStore_TenantTransactions(t *testing.T) {
+	store := NewStore()
+	fillFixture(t, acme, store)
+	fillFixture(t, globex, store)
+	globexBefore := tenantSnapshot(t, store, globex)
+
+	err := store.WithinTx(acme, func(ctx context.Context, tx biginterface.DataStore) error {
+		if err := tx.DeleteTodo(ctx, "todo1"); err != nil {
+			return err
+		}
+		// A context naming another tenant still acts on the tenant of the transaction
+		if err := store.CreateUser(tenant.WithID(ctx, "globex"), &domain.User{ID: "user3"}); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+
+	_, err = store.GetTodo(acme, "todo1")
+	assert.NoError(t, err)
+	_, err = store.GetUser(acme, "user3")
+	assert.Error(t, err)
+	assert.Equal(t, globexBefore, tenantSnapshot(t, store, globex))
+}
+
+func TestStore_WatchOnlySeesItsTenant(t *testing.T) {
+	ctx, cancel := context.WithCancel(globex)
+	defer cancel()
+	store := NewStore()
+	changes, err := store.Watch(ctx, domain.ChangeFilter{})
+	require.NoError(t, err)
+
+	require.NoError(t, store.CreateUser(acme, &domain.User{ID: "user1", Name: "Acme"}))
+	require.NoError(t, store.CreateUser(globex, &domain.User{ID: "user1", Name: "Globex"}))
+
+	select {
+	case change := <-changes:
+		require.NotNil(t, change.User)
+		assert.Equal(t, "Globex", change.User.Name)
+	case <-time.After(time.Second):
+		t.Fatal("no change received")
+	}
+}
+
+func TestStore_SnapshotKeepsTenantsApart(t *testing.T) {
+	store := NewStore()
+	fillFixture(t, acme, store)
+	require.NoError(t, store.CreateUser(globex, &domain.User{ID: "user1", Name: "Globex"}))
+
+	var snapshot bytes.Buffer
+	require.NoError(t, store.Snapshot(&snapshot))
+	restored := NewStore()
+	require.NoError(t, restored.Restore(bytes.NewReader(snapshot.Bytes())))
+
+	assert.Equal(t, tenantSnapshot(t, store, acme), tenantSnapshot(t, restored, acme))
+	assert.Equal(t, tenantSnapshot(t, store, globex), tenantSnapshot(t, restored, globex))
+	user, err := restored.GetUser(globex, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, "Globex", user.Name)
+	_, err = restored.GetTodo(globex, "todo1")
+	assert.Error(t, err)
+}
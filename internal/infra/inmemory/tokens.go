@@ -0,0 +1,74 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+var _ smallinterface.TokenStore = (*Store)(nil)
+
+// Token operations
+func (s *state) GetToken(ctx context.Context, id string) (*domain.Token, error) {
+	token, ok := s.tokens[id]
+	if !ok {
+		return nil, fmt.Errorf("token not found: %s", id)
+	}
+	return token, nil
+}
+
+// ListUserTokens returns a user's tokens, revoked ones included, oldest first
+func (s *state) ListUserTokens(ctx context.Context, userID string) ([]*domain.Token, error) {
+	tokens := make([]*domain.Token, 0)
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+		}
+		return tokens[i].ID < tokens[j].ID
+	})
+	return tokens, nil
+}
+
+func (s *state) CreateToken(ctx context.Context, token *domain.Token) error {
+	if token.ID == "" {
+		return fmt.Errorf("token ID cannot be empty")
+	}
+	if _, ok := s.tokens[token.ID]; ok {
+		return fmt.Errorf("token already exists: %s", token.ID)
+	}
+	set(s, s.tokens, token.ID, token)
+	return nil
+}
+
+// RevokeToken revokes a token
+// Revoking a revoked token keeps the time it was first revoked
+func (s *state) RevokeToken(ctx context.Context, id string, revokedAt time.Time) error {
+	token, ok := s.tokens[id]
+	if !ok {
+		return fmt.Errorf("token not found: %s", id)
+	}
+	if !token.IsRevoked() {
+		revoked := *token
+		revoked.RevokedAt = &revokedAt
+		set(s, s.tokens, id, &revoked)
+	}
+	return nil
+}
+
+// deleteUserTokens removes the tokens of a purged user
+func (s *state) deleteUserTokens(userID string) {
+	for id, token := range s.tokens {
+		if token.UserID == userID {
+			unset(s, s.tokens, id)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Trash operations for users
+func (s *state) GetDeletedUser(ctx context.Context, id string) (*domain.User, error) {
+	user, ok := s.users[id]
+	if !ok || !user.IsDeleted() {
+		return nil, fmt.Errorf("user not found in trash: %s", id)
+	}
+	return user, nil
+}
+
+func (s *state) ListDeletedUsers(ctx context.Context) ([]*domain.User, error) {
+	users := make([]*domain.User, 0)
+	for _, user := range s.users {
+		if user.IsDeleted() {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].DeletedAt.Before(*users[j].DeletedAt)
+	})
+	return users, nil
+}
+
+func (s *state) RestoreUser(ctx context.Context, id string) error {
+	user, ok := s.users[id]
+	if !ok || !user.IsDeleted() {
+		return fmt.Errorf("user not found in trash: %s", id)
+	}
+	updated := *user
+	updated.DeletedAt = nil
+	updated.UpdatedAt = time.Now()
+	set(s, s.users, id, &updated)
+	s.publishUser(domain.ChangeRestored, &updated)
+	return nil
+}
+
+// PurgeUser permanently removes a user from the trash
+func (s *state) PurgeUser(ctx context.Context, id string) error {
+	user, ok := s.users[id]
+	if !ok || !user.IsDeleted() {
+		return fmt.Errorf("user not found in trash: %s", id)
+	}
+	unset(s, s.users, id)
+	s.deleteUserTokens(id)
+	s.deleteShares(func(share *domain.Share) bool {
+		return share.OwnerID == id || share.UserID == id
+	})
+	s.unassignTodos(id)
+	s.deleteAuthorComments(id)
+	s.publishPurge(domain.ChangeEntityUser, id, id)
+	return nil
+}
+
+// unassignTodos clears the assignee of every todo, trashed ones included, assigned to a user
+func (s *state) unassignTodos(assigneeID string) {
+	for id := range s.assigneeTodos[assigneeID] {
+		updated := *s.todos[id]
+		updated.AssigneeID = ""
+		s.putTodo(&updated)
+		if !updated.IsDeleted() {
+			s.publishTodo(domain.ChangeUpdated, &updated)
+		}
+	}
+}
+
+// Trash operations for todos
+func (s *state) GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	todo, ok := s.todos[id]
+	if !ok || !todo.IsDeleted() {
+		return nil, fmt.Errorf("todo not found in trash: %s", id)
+	}
+	return todo, nil
+}
+
+func (s *state) ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	todos := make([]*domain.Todo, 0)
+	for id := range s.userTodos[userID] {
+		if todo := s.todos[id]; todo.IsDeleted() {
+			todos = append(todos, todo)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].DeletedAt.Before(*todos[j].DeletedAt)
+	})
+	return todos, nil
+}
+
+func (s *state) RestoreTodo(ctx context.Context, id string) error {
+	todo, ok := s.todos[id]
+	if !ok || !todo.IsDeleted() {
+		return fmt.Errorf("todo not found in trash: %s", id)
+	}
+	restored := s.changeTodo(todo, func(todo *domain.Todo) {
+		todo.DeletedAt = nil
+		todo.UpdatedAt = time.Now()
+	})
+	s.publishTodo(domain.ChangeRestored, restored)
+	return nil
+}
+
+// PurgeTodo permanently removes a todo from the trash together with its subtasks and their tag links
+func (s *state) PurgeTodo(ctx context.Context, id string) error {
+	todo, ok := s.todos[id]
+	if !ok || !todo.IsDeleted() {
+		return fmt.Errorf("todo not found in trash: %s", id)
+	}
+	s.purgeTree(todo)
+	return nil
+}
+
+// PurgeTodosDeletedBefore permanently removes every todo that was moved to the trash before cutoff
+func (s *state) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	purged := 0
+	// Subtasks purged along with their parent are removed from the map before they are reached
+	for _, todo := range s.todos {
+		if todo.IsDeleted() && todo.DeletedAt.Before(cutoff) {
+			purged += s.purgeTree(todo)
+		}
+	}
+	return purged, nil
+}
+
+// purgeTree purges a todo and its subtasks, subtasks first, and returns how many todos it removed
+// Subtasks go with their parent so that none is left pointing at a parent that no longer exists
+func (s *state) purgeTree(todo *domain.Todo) int {
+	purged := 1
+	for childID := range s.children[todo.ID] {
+		purged += s.purgeTree(s.todos[childID])
+	}
+	s.purgeTodo(todo)
+	return purged
+}
+
+func (s *state) purgeTodo(todo *domain.Todo) {
+	s.unindexTodo(todo)
+	s.unlinkAllTags(todo)
+	s.deleteShares(func(share *domain.Share) bool {
+		return share.ResourceType == domain.ShareResourceTodo && share.ResourceID == todo.ID
+	})
+	s.deleteTodoComments(todo.ID)
+	s.deleteTodoAttachments(todo.ID)
+	unset(s, s.todos, todo.ID)
+	s.publishPurge(domain.ChangeEntityTodo, todo.ID, todo.UserID)
+}
@@ -0,0 +1,83 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// subtaskStore returns a store holding todo1 with the subtask todo2, which has the subtask todo3
+func subtaskStore(t *testing.T) *Store {
+	t.Helper()
+	store := treeStore(t)
+	require.NoError(t, store.CreateTodo(context.Background(), &domain.Todo{ID: "todo3", UserID: "user1", ParentID: "todo2", Title: "Grandchild"}))
+	return store
+}
+
+func TestStore_DeleteTodoTrashesSubtasks(t *testing.T) {
+	ctx := context.Background()
+	store := subtaskStore(t)
+
+	require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+
+	for _, id := range []string{"todo1", "todo2", "todo3"} {
+		_, err := store.GetTodo(ctx, id)
+		assert.Error(t, err, id)
+		trashed, err := store.GetDeletedTodo(ctx, id)
+		require.NoError(t, err, id)
+		assert.NotNil(t, trashed.DeletedAt, id)
+	}
+	todos, err := store.ListUserTodos(ctx, "user1")
+	require.NoError(t, err)
+	assert.Empty(t, todos)
+
+	// Restoring keeps the tree, as the trashed subtasks stay indexed under their parent
+	require.NoError(t, store.RestoreTodo(ctx, "todo1"))
+	require.NoError(t, store.RestoreTodo(ctx, "todo2"))
+	subtasks, err := store.ListSubtasks(ctx, "todo1")
+	require.NoError(t, err)
+	require.Len(t, subtasks, 1)
+	assert.Equal(t, "todo2", subtasks[0].ID)
+}
+
+func TestStore_PurgeTodoPurgesSubtasks(t *testing.T) {
+	tests := map[string]struct {
+		purge func(ctx context.Context, store *Store) error
+	}{
+		"PurgeTodo": {
+			purge: func(ctx context.Context, store *Store) error {
+				return store.PurgeTodo(ctx, "todo1")
+			},
+		},
+		"PurgeTodosDeletedBefore": {
+			purge: func(ctx context.Context, store *Store) error {
+				purged, err := store.PurgeTodosDeletedBefore(ctx, time.Now().Add(time.Hour))
+				assert.Equal(t, 3, purged)
+				return err
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := subtaskStore(t)
+			require.NoError(t, store.DeleteTodo(ctx, "todo1"))
+
+			require.NoError(t, tt.purge(ctx, store))
+
+			for _, id := range []string{"todo1", "todo2", "todo3"} {
+				_, err := store.GetDeletedTodo(ctx, id)
+				assert.Error(t, err, id)
+			}
+			st := store.tenants[tenant.Default]
+			assert.Empty(t, st.children)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package inmemory
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+var _ smallinterface.TxRunner = (*Store)(nil)
+
+type txKey struct{}
+
+// txn marks the context of a running transaction, which acts on the state of a single tenant
+// done is set when the transaction ends, so a context that outlives it takes the lock again
+type txn struct {
+	store *Store
+	state *state
+	done  atomic.Bool
+}
+
+// Transaction operations
+
+// RunInTx runs fn while holding the store's write lock
+// Every write fn makes is undone when it returns an error, and watchers only see
+// the changes once the transaction commits
+// Calls into the store with the context fn receives join the transaction, so tag and
+// project operations made through the Store itself are part of it as well
+func (s *Store) RunInTx(ctx context.Context, fn func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error) error {
+	return s.runInTx(ctx, func(ctx context.Context, tx *state) error {
+		return fn(ctx, tx, tx)
+	})
+}
+
+// WithinTx is RunInTx for the big interface, handing fn the whole DataStore
+func (s *Store) WithinTx(ctx context.Context, fn func(ctx context.Context, tx biginterface.DataStore) error) error {
+	return s.runInTx(ctx, func(ctx context.Context, tx *state) error {
+		return fn(ctx, tx)
+	})
+}
+
+// WithinTx joins the transaction the state is already part of
+func (s *state) WithinTx(ctx context.Context, fn func(ctx context.Context, tx biginterface.DataStore) error) error {
+	return fn(ctx, s)
+}
+
+// runInTx rolls back by replaying the undo log of the writes fn made, newest first
+// Only the state of the tenant of ctx is rolled back, so the other tenants are left alone
+// A transaction started inside another one joins it
+func (s *Store) runInTx(ctx context.Context, fn func(ctx context.Context, tx *state) error) error {
+	if tx, ok := s.runningTx(ctx); ok {
+		return fn(ctx, tx.state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	live := s.tenantState(tenantID)
+	tx := &txn{store: s, state: live}
+	defer tx.done.Store(true)
+
+	live.buffering = true
+	committed := false
+	// Also rolls back when fn panics
+	defer func() {
+		if !committed {
+			live.rollback()
+			live.buffering = false
+			live.pending = nil
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx), live); err != nil {
+		return err
+	}
+
+	committed = true
+	changes := live.pending
+	live.buffering = false
+	live.pending = nil
+	live.undo = nil
+	for _, change := range changes {
+		live.feed.Publish(change)
+	}
+	return nil
+}
+
+// runningTx returns the transaction of this store ctx belongs to, if it is still running
+func (s *Store) runningTx(ctx context.Context) (*txn, bool) {
+	tx, ok := ctx.Value(txKey{}).(*txn)
+	if !ok || tx.store != s || tx.done.Load() {
+		return nil, false
+	}
+	return tx, true
+}
@@ -0,0 +1,166 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+// tenantIndexes returns the indexes of a tenant as JSON, as the snapshot leaves them out
+func tenantIndexes(t *testing.T, store *Store, ctx context.Context) string {
+	t.Helper()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	st := store.tenants[tenant.FromContext(ctx)]
+	data, err := json.Marshal([]map[string]map[string]struct{}{
+		st.userTodos, st.children, st.assigneeTodos, st.todoComments, st.todoAttachments, st.todoTags,
+	})
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestStore_RollbackUndoesEveryWrite(t *testing.T) {
+	for name, write := range fixtureWrites {
+		t.Run(name, func(t *testing.T) {
+			store := NewStore()
+			fillFixture(t, acme, store)
+			before := tenantSnapshot(t, store, acme)
+			indexesBefore := tenantIndexes(t, store, acme)
+
+			err := store.WithinTx(acme, func(ctx context.Context, tx biginterface.DataStore) error {
+				require.NoError(t, write(ctx, store))
+				return errors.New("rollback")
+			})
+
+			require.EqualError(t, err, "rollback")
+			assert.Equal(t, before, tenantSnapshot(t, store, acme))
+			assert.Equal(t, indexesBefore, tenantIndexes(t, store, acme))
+			assert.Empty(t, store.tenants["acme"].undo)
+		})
+	}
+}
+
+func TestStore_RollbackUndoesWritesInReverse(t *testing.T) {
+	store := NewStore()
+	fillFixture(t, acme, store)
+	before := tenantSnapshot(t, store, acme)
+	indexesBefore := tenantIndexes(t, store, acme)
+
+	// Writes to the same entries, so undoing them in the wrong order leaves a trace
+	err := store.WithinTx(acme, func(ctx context.Context, tx biginterface.DataStore) error {
+		require.NoError(t, tx.CreateTodo(ctx, &domain.Todo{ID: "todo4", UserID: "user1", ParentID: "todo1"}))
+		require.NoError(t, tx.AddTodoTag(ctx, "todo4", "later"))
+		require.NoError(t, tx.MarkTodoComplete(ctx, "todo1"))
+		require.NoError(t, tx.SetTodoPriority(ctx, "todo1", domain.PriorityHigh))
+		require.NoError(t, tx.RemoveTodoTag(ctx, "todo4", "later"))
+		require.NoError(t, tx.DeleteTodo(ctx, "todo4"))
+		require.NoError(t, tx.PurgeTodo(ctx, "todo4"))
+		require.NoError(t, tx.DeleteTodo(ctx, "todo1"))
+		require.NoError(t, tx.RestoreTodo(ctx, "todo1"))
+		require.NoError(t, tx.AppendAuditEntry(ctx, &domain.AuditEntry{ID: "entry2", Actor: "admin"}))
+		return errors.New("rollback")
+	})
+
+	require.EqualError(t, err, "rollback")
+	assert.Equal(t, before, tenantSnapshot(t, store, acme))
+	assert.Equal(t, indexesBefore, tenantIndexes(t, store, acme))
+
+	// Writes after the rollback are not undone by a later one
+	require.NoError(t, store.MarkTodoComplete(acme, "todo1"))
+	err = store.WithinTx(acme, func(ctx context.Context, tx biginterface.DataStore) error {
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+	todo, err := store.GetTodo(acme, "todo1")
+	require.NoError(t, err)
+	assert.True(t, todo.Completed)
+}
+
+// Run with -race, readers go through the fields of what they got while writers change the same todos and users
+func TestStore_ReadsDuringWrites(t *testing.T) {
+	ctx := context.Background()
+	store := fixtureStore(t)
+	due := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	writes := []func() error{
+		func() error { return store.MarkTodoComplete(ctx, "todo1") },
+		func() error { return store.SetTodoPriority(ctx, "todo1", domain.PriorityHigh) },
+		func() error { return store.SetTodoDueDate(ctx, "todo2", &due) },
+		func() error {
+			if err := store.DeleteTodo(ctx, "todo2"); err != nil {
+				return err
+			}
+			return store.RestoreTodo(ctx, "todo2")
+		},
+		func() error {
+			if err := store.DeleteUser(ctx, "user2"); err != nil {
+				return err
+			}
+			return store.RestoreUser(ctx, "user2")
+		},
+		func() error {
+			return store.WithinTx(ctx, func(ctx context.Context, tx biginterface.DataStore) error {
+				if err := tx.SetTodoPriority(ctx, "todo1", domain.PriorityLow); err != nil {
+					return err
+				}
+				return errors.New("rollback")
+			})
+		},
+	}
+	reads := []func() error{
+		func() error {
+			todos, err := store.ListUserTodos(ctx, "user1")
+			for _, todo := range todos {
+				describeTodo(todo)
+			}
+			return err
+		},
+		func() error {
+			todo, err := store.GetTodo(ctx, "todo1")
+			if err == nil {
+				describeTodo(todo)
+			}
+			return err
+		},
+		func() error {
+			users, err := store.ListUsers(ctx)
+			for _, user := range users {
+				_ = fmt.Sprint(user.Name, user.UpdatedAt, user.DeletedAt)
+			}
+			return err
+		},
+	}
+
+	var wg sync.WaitGroup
+	for _, op := range append(writes, reads...) {
+		wg.Add(1)
+		go func(op func() error) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_ = op()
+			}
+		}(op)
+	}
+	wg.Wait()
+
+	todo, err := store.GetTodo(ctx, "todo1")
+	require.NoError(t, err)
+	assert.True(t, todo.Completed)
+	assert.Equal(t, domain.PriorityHigh, todo.Priority)
+}
+
+// describeTodo reads the fields of a todo the writes change
+func describeTodo(todo *domain.Todo) string {
+	return fmt.Sprint(todo.Completed, todo.CompletedAt, todo.Priority, todo.DueAt, todo.DeletedAt, todo.UpdatedAt)
+}
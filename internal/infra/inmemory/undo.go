@@ -0,0 +1,44 @@
+package inmemory
+
+// Writes to a state go through the helpers below, which log how to undo them while a transaction runs
+// Stored entities are never changed in place, a write stores a changed copy instead, so putting back
+// the map entries a transaction overwrote is enough to roll it back
+// It also means that an entity handed to a reader never changes under it
+
+// set stores value under key in m
+func set[K comparable, V any](s *state, m map[K]V, key K, value V) {
+	remember(s, m, key)
+	m[key] = value
+}
+
+// unset removes key from m
+func unset[K comparable, V any](s *state, m map[K]V, key K) {
+	if _, ok := m[key]; !ok {
+		return
+	}
+	remember(s, m, key)
+	delete(m, key)
+}
+
+// remember logs how to put back the entry of key in m as it is now
+func remember[K comparable, V any](s *state, m map[K]V, key K) {
+	if !s.buffering {
+		return
+	}
+	previous, existed := m[key]
+	s.undo = append(s.undo, func() {
+		if existed {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+	})
+}
+
+// rollback undoes the writes of the running transaction, newest first
+func (s *state) rollback() {
+	for i := len(s.undo) - 1; i >= 0; i-- {
+		s.undo[i]()
+	}
+	s.undo = nil
+}
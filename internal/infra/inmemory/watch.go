@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+const (
+	// changeHistorySize is how many changes are retained for watchers that resume
+	changeHistorySize = 1024
+	// maxPendingChanges is how far a watcher may fall behind before it is dropped
+	maxPendingChanges = 256
+)
+
+var _ smallinterface.ChangeWatcher = (*Store)(nil)
+
+// Change notification operations
+func (s *state) Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error) {
+	return s.feed.Watch(ctx, filter)
+}
+
+// Watch takes the exclusive lock, as watching a tenant without any data yet creates its state and feed
+func (s *Store) Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error) {
+	st, unlock := s.lock(ctx, true)
+	defer unlock()
+	return st.Watch(ctx, filter)
+}
+
+// publishUser publishes a copy of the user, so watchers never share it with the store
+func (s *state) publishUser(kind domain.ChangeKind, user *domain.User) {
+	copied := *user
+	s.publish(domain.Change{
+		Kind:     kind,
+		Entity:   domain.ChangeEntityUser,
+		EntityID: user.ID,
+		UserID:   user.ID,
+		User:     &copied,
+		At:       time.Now(),
+	})
+}
+
+// publishTodo publishes a copy of the todo, so watchers never share it with the store
+func (s *state) publishTodo(kind domain.ChangeKind, todo *domain.Todo) {
+	copied := *todo
+	s.publish(domain.Change{
+		Kind:     kind,
+		Entity:   domain.ChangeEntityTodo,
+		EntityID: todo.ID,
+		UserID:   todo.UserID,
+		Todo:     &copied,
+		At:       time.Now(),
+	})
+}
+
+func (s *state) publishPurge(entity domain.ChangeEntity, id, userID string) {
+	s.publish(domain.Change{
+		Kind:     domain.ChangePurged,
+		Entity:   entity,
+		EntityID: id,
+		UserID:   userID,
+		At:       time.Now(),
+	})
+}
+
+// publish hands a change to the feed, or holds it back until the running transaction commits
+func (s *state) publish(change domain.Change) {
+	if s.buffering {
+		s.pending = append(s.pending, change)
+		return
+	}
+	s.feed.Publish(change)
+}
+
+// todoChangeKind tells a plain update from a completion or a move into or out of the trash
+func todoChangeKind(before, after *domain.Todo) domain.ChangeKind {
+	switch {
+	case !before.IsDeleted() && after.IsDeleted():
+		return domain.ChangeDeleted
+	case before.IsDeleted() && !after.IsDeleted():
+		return domain.ChangeRestored
+	case !before.Completed && after.Completed:
+		return domain.ChangeCompleted
+	}
+	return domain.ChangeUpdated
+}
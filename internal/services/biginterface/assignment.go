@@ -0,0 +1,62 @@
+package biginterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// AssignTodo makes a user responsible for a Todo, replacing its previous assignee
+// The assignee does not have to be the owner, and assigning a Todo does not share it
+func (s *TodoService) AssignTodo(ctx context.Context, id string, assigneeID string) error {
+	todo, err := s.store.GetTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeShared(ctx, todo, domain.ShareEditor); err != nil {
+		return err
+	}
+	if _, err := s.store.GetUser(ctx, assigneeID); err != nil {
+		return domain.ErrUserNotFound
+	}
+	if todo.AssigneeID == assigneeID {
+		return nil
+	}
+
+	updated := *todo
+	updated.AssigneeID = assigneeID
+	updated.UpdatedAt = s.now()
+	return s.store.UpdateTodo(ctx, &updated)
+}
+
+// UnassignTodo clears the assignee of a Todo
+func (s *TodoService) UnassignTodo(ctx context.Context, id string) error {
+	todo, err := s.store.GetTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeShared(ctx, todo, domain.ShareEditor); err != nil {
+		return err
+	}
+	if todo.AssigneeID == "" {
+		return nil
+	}
+
+	updated := *todo
+	updated.AssigneeID = ""
+	updated.UpdatedAt = s.now()
+	return s.store.UpdateTodo(ctx, &updated)
+}
+
+// GetAssignedTodos retrieves the Todos assigned to a user, whoever owns them
+// Archived Todos are left out
+func (s *TodoService) GetAssignedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.GetUser(ctx, userID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return s.store.ListAssignedTodos(ctx, userID)
+}
@@ -0,0 +1,162 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// orphanedBlobGracePeriod is how long a blob no attachment refers to is kept
+// It covers uploads whose blob is stored but whose attachment is not created yet
+const orphanedBlobGracePeriod = time.Hour
+
+// AttachmentService is a service that provides attachment-related operations
+type AttachmentService struct {
+	store     biginterface.DataStore   // Using the same big interface
+	blobStore smallinterface.BlobStore // Blobs are not in the DataStore, so they need a store of their own
+	now       func() time.Time
+	newID     func() string
+}
+
+// NewAttachmentService creates a new AttachmentService
+func NewAttachmentService(store biginterface.DataStore, blobStore smallinterface.BlobStore) *AttachmentService {
+	return &AttachmentService{
+		store:     store,
+		blobStore: blobStore,
+		now:       time.Now,
+		newID:     domain.NewID,
+	}
+}
+
+// UploadAttachment stores content and attaches it to a Todo under name
+// Attaching needs the access of an editor, and the content type is sniffed from the content
+func (s *AttachmentService) UploadAttachment(ctx context.Context, todoID string, uploaderID string, name string, content io.Reader) (*domain.Attachment, error) {
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, `/\`) {
+		return nil, errors.New("invalid attachment name")
+	}
+	if err := auth.Authorize(ctx, uploaderID); err != nil {
+		return nil, err
+	}
+	todo, err := s.store.GetTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeSharedTodo(ctx, s.store, todo, domain.ShareEditor); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.GetUser(ctx, uploaderID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	// A failure after this leaves an orphaned blob behind, which CollectOrphanedBlobs removes
+	blob, err := s.blobStore.PutBlob(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	attachment := &domain.Attachment{
+		ID:          s.newID(),
+		TodoID:      todo.ID,
+		UploaderID:  uploaderID,
+		Name:        name,
+		ContentType: blob.ContentType,
+		Size:        blob.Size,
+		Digest:      blob.Digest,
+		CreatedAt:   s.now(),
+	}
+	if err := s.store.CreateAttachment(ctx, attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// GetTodoAttachments retrieves the attachments of a Todo from the oldest to the newest
+func (s *AttachmentService) GetTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error) {
+	todo, err := s.store.GetTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeSharedTodo(ctx, s.store, todo, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return s.store.ListTodoAttachments(ctx, todo.ID)
+}
+
+// DownloadAttachment retrieves an attachment together with its content, which the caller must close
+func (s *AttachmentService) DownloadAttachment(ctx context.Context, id string) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.authorizeAttachment(ctx, id, domain.ShareViewer)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobStore.OpenBlob(ctx, attachment.Digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment detaches an attachment from its Todo
+// Its blob is removed by CollectOrphanedBlobs once no other attachment has the same content
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, id string) error {
+	if _, err := s.authorizeAttachment(ctx, id, domain.ShareEditor); err != nil {
+		return err
+	}
+	return s.store.DeleteAttachment(ctx, id)
+}
+
+// CollectOrphanedBlobs removes the blobs no attachment refers to anymore, such as those of
+// deleted attachments and of purged Todos, and returns how many were removed
+// Blobs of attachments whose Todo is in the trash are kept so that restoring it brings them back
+// Only the blobs and attachments of the tenant of ctx are considered, so each tenant is collected on its own
+func (s *AttachmentService) CollectOrphanedBlobs(ctx context.Context) (int, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return 0, err
+	}
+	blobs, err := s.blobStore.ListBlobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := s.now().Add(-orphanedBlobGracePeriod)
+	removed := 0
+	for _, blob := range blobs {
+		if !blob.CreatedAt.Before(cutoff) {
+			continue
+		}
+		attachments, err := s.store.ListBlobAttachments(ctx, blob.Digest)
+		if err != nil {
+			return removed, err
+		}
+		if len(attachments) > 0 {
+			continue
+		}
+		if err := s.blobStore.DeleteBlob(ctx, blob.Digest); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// authorizeAttachment retrieves an attachment when the principal of ctx has the access of required to its Todo
+// Attachments of a Todo in the trash are not accessible until it is restored
+func (s *AttachmentService) authorizeAttachment(ctx context.Context, id string, required domain.ShareRole) (*domain.Attachment, error) {
+	attachment, err := s.store.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.store.GetTodo(ctx, attachment.TodoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeSharedTodo(ctx, s.store, todo, required); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
@@ -0,0 +1,290 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/file"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+	smallmocks "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/tenant"
+)
+
+type attachmentMocks struct {
+	store     *mocks.MockDataStore
+	blobStore *smallmocks.MockBlobStore
+}
+
+func newAttachmentService(ctrl *gomock.Controller) (*AttachmentService, attachmentMocks) {
+	m := attachmentMocks{
+		store:     mocks.NewMockDataStore(ctrl),
+		blobStore: smallmocks.NewMockBlobStore(ctrl),
+	}
+	return NewAttachmentService(m.store, m.blobStore), m
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	todo := &domain.Todo{ID: "todo1", UserID: "user1"}
+	blob := &domain.Blob{Digest: "digest1", Size: 4, ContentType: "image/png"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		name       string
+		setupMocks func(m attachmentMocks)
+		expectErr  error
+		expectMsg  string
+	}{
+		"Success: Owner attaches a screenshot": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			name:      "screenshot.png",
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				m.store.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				m.blobStore.EXPECT().PutBlob(gomock.Any(), gomock.Any()).Return(blob, nil)
+				m.store.EXPECT().CreateAttachment(gomock.Any(), &domain.Attachment{
+					ID:          "attachment1",
+					TodoID:      "todo1",
+					UploaderID:  "user1",
+					Name:        "screenshot.png",
+					ContentType: "image/png",
+					Size:        4,
+					Digest:      "digest1",
+					CreatedAt:   now,
+				}).Return(nil)
+			},
+		},
+		"Error: Viewer attaches a file": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			name:      "screenshot.png",
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				m.store.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: File exceeds the size limit": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			name:      "video.mp4",
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				m.store.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				m.blobStore.EXPECT().PutBlob(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%w: the limit is 4 bytes", domain.ErrBlobTooLarge))
+			},
+			expectErr: domain.ErrBlobTooLarge,
+		},
+		"Error: Name with a path": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			name:       "../screenshot.png",
+			setupMocks: func(m attachmentMocks) {},
+			expectMsg:  "invalid attachment name",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service, m := newAttachmentService(ctrl)
+			tt.setupMocks(m)
+			service.now = func() time.Time { return now }
+			service.newID = func() string { return "attachment1" }
+
+			attachment, err := service.UploadAttachment(auth.WithPrincipal(context.Background(), tt.principal), "todo1", tt.principal.UserID, tt.name, strings.NewReader("data"))
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectMsg != "":
+				assert.EqualError(t, err, tt.expectMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "attachment1", attachment.ID)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_DownloadAttachment(t *testing.T) {
+	attachment := &domain.Attachment{ID: "attachment1", TodoID: "todo1", Name: "notes.txt", Digest: "digest1"}
+
+	tests := map[string]struct {
+		setupMocks func(m attachmentMocks)
+		expectErr  bool
+	}{
+		"Success: Viewer downloads an attachment": {
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetAttachment(gomock.Any(), "attachment1").Return(attachment, nil)
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				m.store.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+				m.blobStore.EXPECT().OpenBlob(gomock.Any(), "digest1").Return(io.NopCloser(strings.NewReader("Meeting notes")), nil)
+			},
+		},
+		"Error: Todo is in the trash": {
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetAttachment(gomock.Any(), "attachment1").Return(attachment, nil)
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(nil, errors.New("todo not found: todo1"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service, m := newAttachmentService(ctrl)
+			tt.setupMocks(m)
+
+			ctx := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "user2", Role: domain.RoleUser})
+			result, content, err := service.DownloadAttachment(ctx, "attachment1")
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer content.Close()
+			data, err := io.ReadAll(content)
+			require.NoError(t, err)
+			assert.Equal(t, attachment, result)
+			assert.Equal(t, "Meeting notes", string(data))
+		})
+	}
+}
+
+func TestAttachmentService_DeleteAttachment(t *testing.T) {
+	attachment := &domain.Attachment{ID: "attachment1", TodoID: "todo1", UploaderID: "user1", Digest: "digest1"}
+	todo := &domain.Todo{ID: "todo1", UserID: "user1"}
+
+	tests := map[string]struct {
+		setupMocks func(m attachmentMocks)
+		expectErr  error
+	}{
+		"Success: Editor deletes an attachment": {
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetAttachment(gomock.Any(), "attachment1").Return(attachment, nil)
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				m.store.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				m.store.EXPECT().DeleteAttachment(gomock.Any(), "attachment1").Return(nil)
+			},
+		},
+		"Error: Viewer deletes an attachment": {
+			setupMocks: func(m attachmentMocks) {
+				m.store.EXPECT().GetAttachment(gomock.Any(), "attachment1").Return(attachment, nil)
+				m.store.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				m.store.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service, m := newAttachmentService(ctrl)
+			tt.setupMocks(m)
+
+			ctx := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "user2", Role: domain.RoleUser})
+			err := service.DeleteAttachment(ctx, "attachment1")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_CollectOrphanedBlobs(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		principal     domain.Principal
+		setupMocks    func(m attachmentMocks)
+		expectRemoved int
+		expectErr     error
+	}{
+		"Success: Only old blobs without attachments are removed": {
+			principal: domain.Principal{UserID: "admin", Role: domain.RoleAdmin},
+			setupMocks: func(m attachmentMocks) {
+				m.blobStore.EXPECT().ListBlobs(gomock.Any()).Return([]*domain.Blob{
+					{Digest: "orphaned", CreatedAt: now.Add(-2 * time.Hour)},
+					{Digest: "attached", CreatedAt: now.Add(-2 * time.Hour)},
+					{Digest: "uploading", CreatedAt: now.Add(-time.Minute)},
+				}, nil)
+				m.store.EXPECT().ListBlobAttachments(gomock.Any(), "orphaned").Return([]*domain.Attachment{}, nil)
+				m.store.EXPECT().ListBlobAttachments(gomock.Any(), "attached").Return([]*domain.Attachment{{ID: "attachment1"}}, nil)
+				m.blobStore.EXPECT().DeleteBlob(gomock.Any(), "orphaned").Return(nil)
+			},
+			expectRemoved: 1,
+		},
+		"Error: Not an admin": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			setupMocks: func(m attachmentMocks) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service, m := newAttachmentService(ctrl)
+			tt.setupMocks(m)
+			service.now = func() time.Time { return now }
+
+			removed, err := service.CollectOrphanedBlobs(auth.WithPrincipal(context.Background(), tt.principal))
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectRemoved, removed)
+		})
+	}
+}
+
+func TestAttachmentService_CollectOrphanedBlobsKeepsOtherTenants(t *testing.T) {
+	admin := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "admin", Role: domain.RoleAdmin})
+	acme := tenant.WithID(admin, "acme")
+	globex := tenant.WithID(admin, "globex")
+	store := inmemory.NewStore()
+	blobs, err := file.NewBlobStore(t.TempDir(), 1024)
+	require.NoError(t, err)
+	service := NewAttachmentService(store, blobs)
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	orphaned, err := blobs.PutBlob(acme, strings.NewReader("Old notes"))
+	require.NoError(t, err)
+	attached, err := blobs.PutBlob(globex, strings.NewReader("Budget"))
+	require.NoError(t, err)
+	require.NoError(t, store.CreateUser(globex, &domain.User{ID: "user1"}))
+	require.NoError(t, store.CreateTodo(globex, &domain.Todo{ID: "todo1", UserID: "user1"}))
+	require.NoError(t, store.CreateAttachment(globex, &domain.Attachment{ID: "attachment1", TodoID: "todo1", Digest: attached.Digest}))
+
+	removed, err := service.CollectOrphanedBlobs(acme)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = blobs.OpenBlob(acme, orphaned.Digest)
+	assert.Error(t, err)
+	content, err := blobs.OpenBlob(globex, attached.Digest)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+}
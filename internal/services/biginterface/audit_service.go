@@ -0,0 +1,53 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// AuditService is a service that answers questions about the audit log
+// Entries are recorded by the audit store decorators, not by this service
+// The audit log spans every user, so only admins may read it
+type AuditService struct {
+	store biginterface.DataStore // Using the same big interface
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(store biginterface.DataStore) *AuditService {
+	return &AuditService{
+		store: store,
+	}
+}
+
+// GetEntityHistory retrieves every recorded change of an entity, oldest first
+// History is kept after the entity is purged
+func (s *AuditService) GetEntityHistory(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !entityType.IsValid() {
+		return nil, fmt.Errorf("invalid entity type: %s", entityType)
+	}
+	if entityID == "" {
+		return nil, errors.New("entity ID cannot be empty")
+	}
+
+	return s.store.ListEntityAuditEntries(ctx, entityType, entityID)
+}
+
+// GetActorActivity retrieves every change made by an actor, oldest first
+func (s *AuditService) GetActorActivity(ctx context.Context, actor string) ([]*domain.AuditEntry, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if actor == "" {
+		return nil, errors.New("actor cannot be empty")
+	}
+
+	return s.store.ListActorAuditEntries(ctx, actor)
+}
@@ -0,0 +1,119 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestAuditService_GetEntityHistory(t *testing.T) {
+	tests := map[string]struct {
+		entityType     domain.AuditEntityType
+		entityID       string
+		setupAuditFunc func(mock *mocks.MockDataStore)
+		expectEntries  int
+		expectErr      error
+	}{
+		"Success: Get history of a todo": {
+			entityType: domain.AuditEntityTodo,
+			entityID:   "todo1",
+			setupAuditFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					ListEntityAuditEntries(gomock.Any(), domain.AuditEntityTodo, "todo1").
+					Return([]*domain.AuditEntry{
+						{ID: "entry1", Action: domain.AuditActionCreate, EntityType: domain.AuditEntityTodo, EntityID: "todo1"},
+						{ID: "entry2", Action: domain.AuditActionComplete, EntityType: domain.AuditEntityTodo, EntityID: "todo1"},
+					}, nil)
+			},
+			expectEntries: 2,
+			expectErr:     nil,
+		},
+		"Error: Unknown entity type": {
+			entityType:     domain.AuditEntityType("tag"),
+			entityID:       "work",
+			setupAuditFunc: func(mock *mocks.MockDataStore) {},
+			expectErr:      errors.New("invalid entity type: tag"),
+		},
+		"Error: Empty entity ID": {
+			entityType:     domain.AuditEntityUser,
+			entityID:       "",
+			setupAuditFunc: func(mock *mocks.MockDataStore) {},
+			expectErr:      errors.New("entity ID cannot be empty"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupAuditFunc(mockStore)
+
+			service := NewAuditService(mockStore)
+
+			entries, err := service.GetEntityHistory(auth.WithSystem(context.Background()), tt.entityType, tt.entityID)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, entries, tt.expectEntries)
+			}
+		})
+	}
+}
+
+func TestAuditService_GetActorActivity(t *testing.T) {
+	tests := map[string]struct {
+		actor          string
+		setupAuditFunc func(mock *mocks.MockDataStore)
+		expectEntries  int
+		expectErr      error
+	}{
+		"Success: Get activity of an actor": {
+			actor: "user1",
+			setupAuditFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					ListActorAuditEntries(gomock.Any(), "user1").
+					Return([]*domain.AuditEntry{{ID: "entry1", Actor: "user1"}}, nil)
+			},
+			expectEntries: 1,
+			expectErr:     nil,
+		},
+		"Error: Empty actor": {
+			actor:          "",
+			setupAuditFunc: func(mock *mocks.MockDataStore) {},
+			expectErr:      errors.New("actor cannot be empty"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupAuditFunc(mockStore)
+
+			service := NewAuditService(mockStore)
+
+			entries, err := service.GetActorActivity(auth.WithSystem(context.Background()), tt.actor)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, entries, tt.expectEntries)
+			}
+		})
+	}
+}
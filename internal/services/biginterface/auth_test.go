@@ -0,0 +1,154 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestTodoService_Authorization(t *testing.T) {
+	owner := domain.Principal{UserID: "user1", Role: domain.RoleUser}
+	other := domain.Principal{UserID: "user2", Role: domain.RoleUser}
+	admin := domain.Principal{UserID: "root", Role: domain.RoleAdmin}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		call       func(ctx context.Context, service *TodoService) error
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+	}{
+		"Success: Owner lists their Todos": {
+			principal: owner,
+			call: func(ctx context.Context, service *TodoService) error {
+				_, err := service.GetUserTodos(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockStore.EXPECT().ListUserTodos(gomock.Any(), "user1").Return([]*domain.Todo{}, nil)
+			},
+		},
+		"Error: Another user lists the Todos": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				_, err := service.GetUserTodos(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+		"Success: Admin completes another user's Todo": {
+			principal: admin,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CompleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mockStore.EXPECT().MarkTodoComplete(gomock.Any(), "todo1").Return(nil)
+			},
+		},
+		"Error: Another user completes the Todo": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CompleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Another user sets the priority": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.SetPriority(ctx, "todo1", domain.PriorityHigh)
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Another user creates a Todo for the owner": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CreateTodo(ctx, &domain.Todo{UserID: "user1", Title: "Todo"})
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			allowTx(mockStore)
+			tt.setupMocks(mockStore)
+
+			service := NewTodoService(mockStore)
+			err := tt.call(auth.WithPrincipal(context.Background(), tt.principal), service)
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
+
+func TestUserService_Authorization(t *testing.T) {
+	tests := map[string]struct {
+		principal  domain.Principal
+		call       func(ctx context.Context, service *UserService) error
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+	}{
+		"Success: User reads themselves": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			call: func(ctx context.Context, service *UserService) error {
+				_, err := service.GetUser(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+		},
+		"Error: User reads another user": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			call: func(ctx context.Context, service *UserService) error {
+				_, err := service.GetUser(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+		"Error: User creates a user": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			call: func(ctx context.Context, service *UserService) error {
+				return service.CreateUser(ctx, &domain.User{ID: "user3", Name: "User", Email: "user3@example.com"})
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupMocks(mockStore)
+
+			service := NewUserService(mockStore)
+			err := tt.call(auth.WithPrincipal(context.Background(), tt.principal), service)
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Batch operations run in a single transaction
+// In domain.BatchAllOrNothing mode a failed item rolls back the whole batch and the
+// *domain.BatchError is returned along with the result; in domain.BatchBestEffort mode
+// the other items are kept and the failures are only reported in the result
+// An error without a result means the batch could not run at all
+
+// CreateUsers creates several users at once
+func (s *UserService) CreateUsers(ctx context.Context, users []*domain.User, mode domain.BatchMode) (*domain.BatchResult, error) {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.runBatch(ctx, ids, mode, func(ctx context.Context, tx *UserService, batchErr *domain.BatchError) error {
+		return mergeBatchError(batchErr, tx.store.CreateUsers(ctx, users), nil)
+	})
+}
+
+// DeleteUsers moves several users to the trash at once
+func (s *UserService) DeleteUsers(ctx context.Context, ids []string, mode domain.BatchMode) (*domain.BatchResult, error) {
+	return s.runBatch(ctx, ids, mode, func(ctx context.Context, tx *UserService, batchErr *domain.BatchError) error {
+		allowed := make([]string, 0, len(ids))
+		positions := make([]int, 0, len(ids))
+		for i, id := range ids {
+			if err := auth.Authorize(ctx, id); err != nil {
+				batchErr.Add(i, id, err)
+				continue
+			}
+			allowed = append(allowed, id)
+			positions = append(positions, i)
+		}
+		return mergeBatchError(batchErr, tx.store.DeleteUsers(ctx, allowed), positions)
+	})
+}
+
+// runBatch runs apply in a transaction and reports the outcome for ids
+func (s *UserService) runBatch(ctx context.Context, ids []string, mode domain.BatchMode, apply func(ctx context.Context, tx *UserService, batchErr *domain.BatchError) error) (*domain.BatchResult, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid batch mode: %q", mode)
+	}
+	var batchErr domain.BatchError
+	err := s.store.WithinTx(ctx, func(ctx context.Context, store biginterface.DataStore) error {
+		batchErr = domain.BatchError{}
+		tx := *s
+		tx.store = store
+		if err := apply(ctx, &tx, &batchErr); err != nil {
+			return err
+		}
+		return abortBatch(mode, &batchErr)
+	})
+	return batchResult(ids, &batchErr, err)
+}
+
+// CreateTodos creates several Todos at once, checking each of them like CreateTodo
+// A subtask may be created in the same batch as its parent when it comes after it
+func (s *TodoService) CreateTodos(ctx context.Context, todos []*domain.Todo, mode domain.BatchMode) (*domain.BatchResult, error) {
+	ids := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	return s.runBatch(ctx, mode, func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error) {
+		valid := make([]*domain.Todo, 0, len(todos))
+		positions := make([]int, 0, len(todos))
+		users := make(map[string]error)
+		for i, todo := range todos {
+			if err := tx.checkNewTodo(ctx, todo, users); err != nil {
+				batchErr.Add(i, todo.ID, err)
+				continue
+			}
+			valid = append(valid, todo)
+			positions = append(positions, i)
+		}
+		return ids, mergeBatchError(batchErr, tx.store.CreateTodos(ctx, valid), positions)
+	})
+}
+
+// UpdateTodos saves changes to several Todos at once
+// A Todo cannot be handed to another user, and its project must belong to its user
+func (s *TodoService) UpdateTodos(ctx context.Context, todos []*domain.Todo, mode domain.BatchMode) (*domain.BatchResult, error) {
+	ids := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	return s.runBatch(ctx, mode, func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error) {
+		valid := make([]*domain.Todo, 0, len(todos))
+		positions := make([]int, 0, len(todos))
+		now := tx.now()
+		for i, todo := range todos {
+			if err := tx.checkUpdatedTodo(ctx, todo); err != nil {
+				batchErr.Add(i, todo.ID, err)
+				continue
+			}
+			updated := *todo
+			updated.UpdatedAt = now
+			valid = append(valid, &updated)
+			positions = append(positions, i)
+		}
+		return ids, mergeBatchError(batchErr, tx.store.UpdateTodos(ctx, valid), positions)
+	})
+}
+
+// DeleteTodos moves several Todos and all of their subtasks to the trash at once
+func (s *TodoService) DeleteTodos(ctx context.Context, ids []string, mode domain.BatchMode) (*domain.BatchResult, error) {
+	return s.runBatch(ctx, mode, func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error) {
+		return ids, tx.deleteTodos(ctx, ids, batchErr)
+	})
+}
+
+// DeleteMatchingTodos moves a user's Todos that match filter to the trash
+// The result lists the IDs of the Todos that matched
+func (s *TodoService) DeleteMatchingTodos(ctx context.Context, filter domain.TodoFilter, mode domain.BatchMode) (*domain.BatchResult, error) {
+	return s.runBatch(ctx, mode, func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error) {
+		ids, err := tx.matchingTodoIDs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return ids, tx.deleteTodos(ctx, ids, batchErr)
+	})
+}
+
+// CompleteTodos marks several Todos as complete at once, with the same follow-ups as CompleteTodo
+func (s *TodoService) CompleteTodos(ctx context.Context, ids []string, mode domain.BatchMode) (*domain.BatchResult, error) {
+	return s.runBatch(ctx, mode, func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error) {
+		return ids, tx.completeTodos(ctx, ids, batchErr)
+	})
+}
+
+// CompleteMatchingTodos marks a user's Todos that match filter as complete
+// The result lists the IDs of the Todos that matched
+func (s *TodoService) CompleteMatchingTodos(ctx context.Context, filter domain.TodoFilter, mode domain.BatchMode) (*domain.BatchResult, error) {
+	return s.runBatch(ctx, mode, func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error) {
+		ids, err := tx.matchingTodoIDs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return ids, tx.completeTodos(ctx, ids, batchErr)
+	})
+}
+
+// runBatch runs apply in a transaction and reports the outcome for the IDs it returns
+func (s *TodoService) runBatch(ctx context.Context, mode domain.BatchMode, apply func(ctx context.Context, tx *TodoService, batchErr *domain.BatchError) ([]string, error)) (*domain.BatchResult, error) {
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("invalid batch mode: %q", mode)
+	}
+	var ids []string
+	var batchErr domain.BatchError
+	err := s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		batchErr = domain.BatchError{}
+		var err error
+		ids, err = apply(ctx, tx, &batchErr)
+		if err != nil {
+			return err
+		}
+		return abortBatch(mode, &batchErr)
+	})
+	return batchResult(ids, &batchErr, err)
+}
+
+// checkNewTodo runs the checks of CreateTodo
+// users caches whether the owners exist, since a batch usually belongs to few users
+func (s *TodoService) checkNewTodo(ctx context.Context, todo *domain.Todo, users map[string]error) error {
+	if err := auth.Authorize(ctx, todo.UserID); err != nil {
+		return err
+	}
+	userErr, ok := users[todo.UserID]
+	if !ok {
+		if _, err := s.store.GetUser(ctx, todo.UserID); err != nil {
+			userErr = errors.New("cannot create todo for non-existent user")
+		}
+		users[todo.UserID] = userErr
+	}
+	if userErr != nil {
+		return userErr
+	}
+	if todo.ProjectID != "" {
+		if err := s.checkProjectOwner(ctx, todo.ProjectID, todo.UserID); err != nil {
+			return err
+		}
+	}
+	if todo.ParentID != "" {
+		if err := s.checkParent(ctx, todo); err != nil {
+			return err
+		}
+	}
+	if todo.Recurrence != nil {
+		return todo.Recurrence.Validate()
+	}
+	return nil
+}
+
+// checkUpdatedTodo checks the changes to a Todo before it is saved
+func (s *TodoService) checkUpdatedTodo(ctx context.Context, todo *domain.Todo) error {
+	current, err := s.store.GetTodo(ctx, todo.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeShared(ctx, current, domain.ShareEditor); err != nil {
+		return err
+	}
+	if todo.UserID != current.UserID {
+		return errors.New("todo cannot be moved to another user")
+	}
+	if !todo.Priority.IsValid() {
+		return fmt.Errorf("invalid priority: %d", todo.Priority)
+	}
+	if todo.ProjectID != "" && todo.ProjectID != current.ProjectID {
+		if err := s.checkProjectOwner(ctx, todo.ProjectID, todo.UserID); err != nil {
+			return err
+		}
+	}
+	if todo.Recurrence != nil {
+		return todo.Recurrence.Validate()
+	}
+	return nil
+}
+
+// deleteTodos deletes the trees of the given Todos in a single store batch
+// A subtask listed along with its parent is deleted as part of the parent's tree,
+// so only the roots of the trees are passed to the store
+func (s *TodoService) deleteTodos(ctx context.Context, ids []string, batchErr *domain.BatchError) error {
+	queued := make(map[string]struct{})
+	deleteIDs := make([]string, 0, len(ids))
+	positions := make([]int, 0, len(ids))
+	for i, id := range ids {
+		if _, ok := queued[id]; ok {
+			continue
+		}
+		todo, err := s.store.GetTodo(ctx, id)
+		if err != nil {
+			batchErr.Add(i, id, err)
+			continue
+		}
+		if err := s.authorizeShared(ctx, todo, domain.ShareEditor); err != nil {
+			batchErr.Add(i, id, err)
+			continue
+		}
+		tree, err := s.buildTree(ctx, todo)
+		if err != nil {
+			batchErr.Add(i, id, err)
+			continue
+		}
+		var walk func(node *domain.TodoNode)
+		walk = func(node *domain.TodoNode) {
+			queued[node.Todo.ID] = struct{}{}
+			for _, child := range node.Subtasks {
+				walk(child)
+			}
+		}
+		walk(tree)
+		deleteIDs = append(deleteIDs, id)
+		positions = append(positions, i)
+	}
+	return mergeBatchError(batchErr, s.store.DeleteTodos(ctx, deleteIDs), positions)
+}
+
+// completeTodos marks the Todos complete in a single store batch, then creates next occurrences
+// and completes parents like CompleteTodo does
+// Todos that are already complete are left alone
+func (s *TodoService) completeTodos(ctx context.Context, ids []string, batchErr *domain.BatchError) error {
+	todos := make([]*domain.Todo, 0, len(ids))
+	completeIDs := make([]string, 0, len(ids))
+	positions := make([]int, 0, len(ids))
+	for i, id := range ids {
+		todo, err := s.store.GetTodo(ctx, id)
+		if err != nil {
+			batchErr.Add(i, id, err)
+			continue
+		}
+		if err := s.authorizeShared(ctx, todo, domain.ShareEditor); err != nil {
+			batchErr.Add(i, id, err)
+			continue
+		}
+		if todo.Completed {
+			continue
+		}
+		todos = append(todos, todo)
+		completeIDs = append(completeIDs, id)
+		positions = append(positions, i)
+	}
+
+	var marked domain.BatchError
+	if err := mergeBatchError(&marked, s.store.MarkTodosComplete(ctx, completeIDs), nil); err != nil {
+		return err
+	}
+	failed := make(map[int]struct{}, len(marked.Items))
+	for _, item := range marked.Items {
+		failed[item.Index] = struct{}{}
+		batchErr.Add(positions[item.Index], item.ID, item.Err)
+	}
+	completed := make(map[string]struct{}, len(todos))
+	for j, todo := range todos {
+		if _, ok := failed[j]; !ok {
+			completed[todo.ID] = struct{}{}
+		}
+	}
+
+	for j, todo := range todos {
+		if _, ok := failed[j]; ok {
+			continue
+		}
+		if todo.Recurrence != nil {
+			if err := s.createNextOccurrence(ctx, todo); err != nil {
+				batchErr.Add(positions[j], todo.ID, err)
+				continue
+			}
+		}
+		// A parent completed in the same batch takes care of its own ancestors
+		if _, ok := completed[todo.ParentID]; ok {
+			continue
+		}
+		if err := s.completeParentIfDone(ctx, todo); err != nil {
+			batchErr.Add(positions[j], todo.ID, err)
+		}
+	}
+	return nil
+}
+
+// matchingTodoIDs lists the IDs of a user's Todos that match filter
+func (s *TodoService) matchingTodoIDs(ctx context.Context, filter domain.TodoFilter) ([]string, error) {
+	if filter.UserID == "" {
+		return nil, errors.New("filter must name a user")
+	}
+	if err := auth.Authorize(ctx, filter.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.GetUser(ctx, filter.UserID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	todos, err := s.store.ListUserTodos(ctx, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		if filter.Matches(todo) {
+			ids = append(ids, todo.ID)
+		}
+	}
+	return ids, nil
+}
+
+// mergeBatchError adds the items of a *domain.BatchError returned by a store batch to batchErr
+// positions maps the indices of the store batch to those of the caller's batch, and nil
+// means they are the same; an item is only reported once per position
+// Any other error is returned, failing the whole batch
+func mergeBatchError(batchErr *domain.BatchError, err error, positions []int) error {
+	if err == nil {
+		return nil
+	}
+	var storeErr *domain.BatchError
+	if !errors.As(err, &storeErr) {
+		return err
+	}
+	reported := make(map[int]struct{}, len(batchErr.Items))
+	for _, item := range batchErr.Items {
+		reported[item.Index] = struct{}{}
+	}
+	for _, item := range storeErr.Items {
+		index := item.Index
+		if positions != nil {
+			index = positions[index]
+		}
+		if _, ok := reported[index]; ok {
+			continue
+		}
+		reported[index] = struct{}{}
+		batchErr.Add(index, item.ID, item.Err)
+	}
+	return nil
+}
+
+// abortBatch returns the error that rolls back a batch with failed items in domain.BatchAllOrNothing mode
+func abortBatch(mode domain.BatchMode, batchErr *domain.BatchError) error {
+	if mode == domain.BatchAllOrNothing {
+		return batchErr.Err()
+	}
+	return nil
+}
+
+// batchResult reports the outcome of a batch for its IDs
+func batchResult(ids []string, batchErr *domain.BatchError, err error) (*domain.BatchResult, error) {
+	sort.SliceStable(batchErr.Items, func(i, j int) bool {
+		return batchErr.Items[i].Index < batchErr.Items[j].Index
+	})
+	result := &domain.BatchResult{
+		Succeeded: make([]string, 0, len(ids)),
+		Failed:    append([]domain.BatchItemError{}, batchErr.Items...),
+	}
+	if err != nil {
+		var rolledBack *domain.BatchError
+		if errors.As(err, &rolledBack) {
+			return result, err
+		}
+		return nil, err
+	}
+
+	failed := make(map[int]struct{}, len(batchErr.Items))
+	for _, item := range batchErr.Items {
+		failed[item.Index] = struct{}{}
+	}
+	for i, id := range ids {
+		if _, ok := failed[i]; !ok {
+			result.Succeeded = append(result.Succeeded, id)
+		}
+	}
+	return result, nil
+}
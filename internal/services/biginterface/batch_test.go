@@ -0,0 +1,335 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestTodoService_CreateTodos(t *testing.T) {
+	newTodos := func() []*domain.Todo {
+		return []*domain.Todo{
+			{ID: "todo1", UserID: "user1", Title: "First"},
+			{ID: "todo2", UserID: "ghost", Title: "Orphan"},
+			{ID: "todo3", UserID: "user1", Title: "Third"},
+		}
+	}
+
+	tests := map[string]struct {
+		mode            domain.BatchMode
+		storeErr        error
+		expectSucceeded []string
+		expectFailed    []int
+		expectErr       error
+	}{
+		"Best effort: Todo of unknown user is reported, the others are created": {
+			mode:            domain.BatchBestEffort,
+			expectSucceeded: []string{"todo1", "todo3"},
+			expectFailed:    []int{1},
+		},
+		"Best effort: Store failure is reported at the caller's index": {
+			mode: domain.BatchBestEffort,
+			// todo3 is the second todo handed to the store
+			storeErr:        &domain.BatchError{Items: []domain.BatchItemError{{Index: 1, ID: "todo3", Err: errors.New("todo already exists")}}},
+			expectSucceeded: []string{"todo1"},
+			expectFailed:    []int{1, 2},
+		},
+		"All or nothing: One failure fails the whole batch": {
+			mode:            domain.BatchAllOrNothing,
+			expectSucceeded: []string{},
+			expectFailed:    []int{1},
+			expectErr:       errors.New("1 items failed"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			allowTx(mockStore)
+			todos := newTodos()
+
+			// The owner is looked up once per user
+			mockStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			mockStore.EXPECT().GetUser(gomock.Any(), "ghost").Return(nil, errors.New("user not found"))
+			mockStore.EXPECT().
+				CreateTodos(gomock.Any(), []*domain.Todo{todos[0], todos[2]}).
+				Return(tt.storeErr)
+
+			service := NewTodoService(mockStore)
+
+			result, err := service.CreateTodos(auth.WithSystem(context.Background()), todos, tt.mode)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+				var batchErr *domain.BatchError
+				assert.True(t, errors.As(err, &batchErr))
+			} else {
+				require.NoError(t, err)
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expectSucceeded, result.Succeeded)
+			failed := make([]int, 0, len(result.Failed))
+			for _, item := range result.Failed {
+				failed = append(failed, item.Index)
+			}
+			assert.Equal(t, tt.expectFailed, failed)
+		})
+	}
+}
+
+func TestTodoService_CreateTodosUnderParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mocks.NewMockDataStore(ctrl)
+	allowTx(mockStore)
+	todos := []*domain.Todo{
+		{ID: "sub1", UserID: "user1", ParentID: "parent1"},
+		{ID: "sub2", UserID: "user1", ParentID: "other1"},
+		{ID: "sub3", UserID: "user1", ParentID: "level3"},
+		{ID: "sub4", UserID: "user1", ParentID: "trashed1"},
+	}
+
+	mockStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "parent1").Return(&domain.Todo{ID: "parent1", UserID: "user1"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "other1").Return(&domain.Todo{ID: "other1", UserID: "user2"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "level3").Return(&domain.Todo{ID: "level3", UserID: "user1", ParentID: "level2"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "level2").Return(&domain.Todo{ID: "level2", UserID: "user1", ParentID: "level1"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "level1").Return(&domain.Todo{ID: "level1", UserID: "user1", ParentID: "root"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "root").Return(&domain.Todo{ID: "root", UserID: "user1"}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "trashed1").Return(nil, errors.New("todo not found: trashed1"))
+	mockStore.EXPECT().CreateTodos(gomock.Any(), []*domain.Todo{todos[0]}).Return(nil)
+
+	service := NewTodoService(mockStore)
+
+	result, err := service.CreateTodos(auth.WithSystem(context.Background()), todos, domain.BatchBestEffort)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub1"}, result.Succeeded)
+	require.Len(t, result.Failed, 3)
+	assert.Contains(t, result.Failed[0].Err.Error(), "same user as its parent")
+	assert.Contains(t, result.Failed[1].Err.Error(), "cannot be nested deeper")
+	assert.Contains(t, result.Failed[2].Err.Error(), "parent todo not found")
+}
+
+func TestTodoService_CreateTodosInvalidMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mocks.NewMockDataStore(ctrl)
+	allowTx(mockStore)
+
+	service := NewTodoService(mockStore)
+
+	result, err := service.CreateTodos(auth.WithSystem(context.Background()), []*domain.Todo{{ID: "todo1", UserID: "user1"}}, "sometimes")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid batch mode")
+	assert.Nil(t, result)
+}
+
+func TestTodoService_DeleteTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mocks.NewMockDataStore(ctrl)
+	allowTx(mockStore)
+
+	parent := &domain.Todo{ID: "todo1", UserID: "user1"}
+	subtask := &domain.Todo{ID: "sub1", UserID: "user1", ParentID: "todo1"}
+	mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(parent, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "missing").Return(nil, errors.New("todo not found: missing"))
+	mockStore.EXPECT().ListSubtasks(gomock.Any(), "todo1").Return([]*domain.Todo{subtask}, nil)
+	mockStore.EXPECT().ListSubtasks(gomock.Any(), "sub1").Return(nil, nil)
+	// sub1 is trashed by the store along with its parent, so it is neither looked up again nor passed on
+	mockStore.EXPECT().DeleteTodos(gomock.Any(), []string{"todo1"}).Return(nil)
+
+	service := NewTodoService(mockStore)
+
+	result, err := service.DeleteTodos(auth.WithSystem(context.Background()), []string{"todo1", "missing", "sub1"}, domain.BatchBestEffort)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"todo1", "sub1"}, result.Succeeded)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, 1, result.Failed[0].Index)
+	assert.Equal(t, "missing", result.Failed[0].ID)
+}
+
+func TestTodoService_BatchSharedAccess(t *testing.T) {
+	editor := domain.Principal{UserID: "user2", Role: domain.RoleUser}
+	todo := &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project1"}
+
+	tests := map[string]struct {
+		call            func(ctx context.Context, service *TodoService) (*domain.BatchResult, error)
+		setupMocks      func(mockStore *mocks.MockDataStore)
+		expectSucceeded []string
+		expectErr       error
+	}{
+		"Success: Editor of the Todo completes it in a batch": {
+			call: func(ctx context.Context, service *TodoService) (*domain.BatchResult, error) {
+				return service.CompleteTodos(ctx, []string{"todo1"}, domain.BatchBestEffort)
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				mockStore.EXPECT().MarkTodosComplete(gomock.Any(), []string{"todo1"}).Return(nil)
+			},
+			expectSucceeded: []string{"todo1"},
+		},
+		"Success: Editor of the project updates its Todo in a batch": {
+			call: func(ctx context.Context, service *TodoService) (*domain.BatchResult, error) {
+				renamed := *todo
+				renamed.Title = "Renamed"
+				return service.UpdateTodos(ctx, []*domain.Todo{&renamed}, domain.BatchBestEffort)
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceProject, "project1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				mockStore.EXPECT().UpdateTodos(gomock.Any(), gomock.Len(1)).Return(nil)
+			},
+			expectSucceeded: []string{"todo1"},
+		},
+		"Success: Editor of the Todo deletes it in a batch": {
+			call: func(ctx context.Context, service *TodoService) (*domain.BatchResult, error) {
+				return service.DeleteTodos(ctx, []string{"todo1"}, domain.BatchBestEffort)
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				mockStore.EXPECT().ListSubtasks(gomock.Any(), "todo1").Return(nil, nil)
+				mockStore.EXPECT().DeleteTodos(gomock.Any(), []string{"todo1"}).Return(nil)
+			},
+			expectSucceeded: []string{"todo1"},
+		},
+		"Error: Viewer completes the Todo in a batch": {
+			call: func(ctx context.Context, service *TodoService) (*domain.BatchResult, error) {
+				return service.CompleteTodos(ctx, []string{"todo1"}, domain.BatchBestEffort)
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceProject, "project1", "user2").Return(nil, errors.New("share not found"))
+				mockStore.EXPECT().MarkTodosComplete(gomock.Any(), []string{}).Return(nil)
+			},
+			expectSucceeded: []string{},
+			expectErr:       domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			allowTx(mockStore)
+			tt.setupMocks(mockStore)
+
+			service := NewTodoService(mockStore)
+			result, err := tt.call(auth.WithPrincipal(context.Background(), editor), service)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectSucceeded, result.Succeeded)
+			if tt.expectErr != nil {
+				require.Len(t, result.Failed, 1)
+				assert.Equal(t, tt.expectErr, result.Failed[0].Err)
+			} else {
+				assert.Empty(t, result.Failed)
+			}
+		})
+	}
+}
+
+func TestTodoService_CompleteMatchingTodos(t *testing.T) {
+	high := domain.PriorityHigh
+	todos := []*domain.Todo{
+		{ID: "todo1", UserID: "user1", Priority: domain.PriorityHigh},
+		{ID: "todo2", UserID: "user1", Priority: domain.PriorityLow},
+		{ID: "todo3", UserID: "user1", Priority: domain.PriorityHigh, Completed: true},
+		{ID: "todo4", UserID: "user1", Priority: domain.PriorityHigh},
+	}
+
+	tests := map[string]struct {
+		filter          domain.TodoFilter
+		setupFunc       func(mock *mocks.MockDataStore)
+		expectSucceeded []string
+		expectErr       error
+	}{
+		"Success: Matching todos are completed, completed ones are left alone": {
+			filter: domain.TodoFilter{UserID: "user1", Priority: &high},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mock.EXPECT().ListUserTodos(gomock.Any(), "user1").Return(todos, nil)
+				mock.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todos[0], nil)
+				mock.EXPECT().GetTodo(gomock.Any(), "todo3").Return(todos[2], nil)
+				mock.EXPECT().GetTodo(gomock.Any(), "todo4").Return(todos[3], nil)
+				mock.EXPECT().MarkTodosComplete(gomock.Any(), []string{"todo1", "todo4"}).Return(nil)
+			},
+			expectSucceeded: []string{"todo1", "todo3", "todo4"},
+		},
+		"Error: Filter without a user": {
+			filter:    domain.TodoFilter{Priority: &high},
+			setupFunc: func(mock *mocks.MockDataStore) {},
+			expectErr: errors.New("filter must name a user"),
+		},
+		"Error: User not found": {
+			filter: domain.TodoFilter{UserID: "ghost"},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "ghost").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			allowTx(mockStore)
+			tt.setupFunc(mockStore)
+
+			service := NewTodoService(mockStore)
+
+			result, err := service.CompleteMatchingTodos(auth.WithSystem(context.Background()), tt.filter, domain.BatchAllOrNothing)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectSucceeded, result.Succeeded)
+				assert.Empty(t, result.Failed)
+			}
+		})
+	}
+}
+
+func TestUserService_CreateUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mocks.NewMockDataStore(ctrl)
+	allowTx(mockStore)
+	users := []*domain.User{{ID: "user1"}, {ID: "user2"}}
+	mockStore.EXPECT().
+		CreateUsers(gomock.Any(), users).
+		Return(&domain.BatchError{Items: []domain.BatchItemError{{Index: 0, ID: "user1", Err: errors.New("user already exists")}}})
+
+	service := NewUserService(mockStore)
+
+	result, err := service.CreateUsers(auth.WithSystem(context.Background()), users, domain.BatchBestEffort)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user2"}, result.Succeeded)
+	require.Len(t, result.Failed, 1)
+	assert.Contains(t, result.Failed[0].Error(), "user already exists")
+}
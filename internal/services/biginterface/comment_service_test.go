@@ -0,0 +1,248 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestCommentService_AddComment(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	todo := &domain.Todo{ID: "todo1", UserID: "user1"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		authorID   string
+		body       string
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+		expectMsg  string
+	}{
+		"Success: Owner comments on their Todo": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			authorID:  "user1",
+			body:      "Started on this",
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockStore.EXPECT().CreateComment(gomock.Any(), &domain.Comment{
+					ID:        "comment1",
+					TodoID:    "todo1",
+					AuthorID:  "user1",
+					Body:      "Started on this",
+					CreatedAt: now,
+					UpdatedAt: now,
+				}).Return(nil)
+			},
+		},
+		"Success: Viewer of the Todo comments on it": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			authorID:  "user2",
+			body:      "Need help?",
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+				mockStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockStore.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"Error: Commenting as another user": {
+			principal:  domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			authorID:   "user1",
+			body:       "Impersonated",
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+		"Error: Todo is not shared with the author": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			authorID:  "user2",
+			body:      "Hello",
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Author does not exist": {
+			principal: domain.Principal{UserID: "admin", Role: domain.RoleAdmin},
+			authorID:  "nonexistent",
+			body:      "Hello",
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetUser(gomock.Any(), "nonexistent").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+		"Error: Empty body": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			authorID:   "user1",
+			body:       "  ",
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectMsg:  "comment body cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupMocks(mockStore)
+
+			service := NewCommentService(mockStore)
+			service.now = func() time.Time { return now }
+			service.newID = func() string { return "comment1" }
+			comment, err := service.AddComment(auth.WithPrincipal(context.Background(), tt.principal), "todo1", tt.authorID, tt.body)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectMsg != "":
+				assert.EqualError(t, err, tt.expectMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "comment1", comment.ID)
+				assert.Equal(t, tt.authorID, comment.AuthorID)
+			}
+		})
+	}
+}
+
+func TestCommentService_GetTodoComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mocks.NewMockDataStore(ctrl)
+
+	comments := []*domain.Comment{
+		{ID: "comment1", TodoID: "todo1", AuthorID: "user1", Body: "First"},
+		{ID: "comment2", TodoID: "todo1", AuthorID: "user2", Body: "Second"},
+	}
+	mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+	mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+	mockStore.EXPECT().ListTodoComments(gomock.Any(), "todo1").Return(comments, nil)
+
+	service := NewCommentService(mockStore)
+	ctx := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "user2", Role: domain.RoleUser})
+	result, err := service.GetTodoComments(ctx, "todo1")
+
+	require.NoError(t, err)
+	assert.Equal(t, comments, result)
+}
+
+func TestCommentService_EditComment(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := now.Add(-time.Hour)
+	comment := &domain.Comment{ID: "comment1", TodoID: "todo1", AuthorID: "user2", Body: "Old", CreatedAt: created, UpdatedAt: created}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		body       string
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+		expectMsg  string
+	}{
+		"Success: Author edits their comment": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			body:      "New",
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+				mockStore.EXPECT().UpdateComment(gomock.Any(), &domain.Comment{
+					ID:        "comment1",
+					TodoID:    "todo1",
+					AuthorID:  "user2",
+					Body:      "New",
+					CreatedAt: created,
+					UpdatedAt: now,
+				}).Return(nil)
+			},
+		},
+		"Error: Owner of the Todo edits someone else's comment": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			body:      "New",
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Empty body": {
+			principal:  domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			body:       "",
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectMsg:  "comment body cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupMocks(mockStore)
+
+			service := NewCommentService(mockStore)
+			service.now = func() time.Time { return now }
+			err := service.EditComment(auth.WithPrincipal(context.Background(), tt.principal), "comment1", tt.body)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectMsg != "":
+				assert.EqualError(t, err, tt.expectMsg)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	comment := &domain.Comment{ID: "comment1", TodoID: "todo1", AuthorID: "user2", Body: "Hello"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+	}{
+		"Success: Author deletes their comment": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+				mockStore.EXPECT().DeleteComment(gomock.Any(), "comment1").Return(nil)
+			},
+		},
+		"Error: Owner of the Todo deletes someone else's comment": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupMocks(mockStore)
+
+			service := NewCommentService(mockStore)
+			err := service.DeleteComment(auth.WithPrincipal(context.Background(), tt.principal), "comment1")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestProjectService_DeleteProject(t *testing.T) {
+	mockProject := &domain.Project{ID: "project1", UserID: "user1", Name: "Work"}
+
+	// Each test case builds its own todo list because DeleteProject modifies Todos
+	newTodos := func() []*domain.Todo {
+		return []*domain.Todo{
+			{ID: "todo1", UserID: "user1", ProjectID: "project1"},
+			{ID: "todo2", UserID: "user1", ProjectID: "project2"},
+		}
+	}
+
+	tests := map[string]struct {
+		projectID string
+		mode      domain.ProjectDeleteMode
+		setupFunc func(mock *mocks.MockDataStore)
+		expectErr error
+	}{
+		"Success: Todos are detached": {
+			projectID: "project1",
+			mode:      domain.ProjectDeleteDetach,
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetProject(gomock.Any(), "project1").Return(mockProject, nil)
+				mock.EXPECT().ListUserTodos(gomock.Any(), "user1").Return(newTodos(), nil)
+				mock.EXPECT().ListArchivedTodos(gomock.Any(), "user1").Return(nil, nil)
+				mock.EXPECT().
+					UpdateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						assert.Equal(t, "todo1", todo.ID)
+						assert.Empty(t, todo.ProjectID)
+						return nil
+					})
+				mock.EXPECT().DeleteProject(gomock.Any(), "project1").Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Success: Todos are deleted with the project": {
+			projectID: "project1",
+			mode:      domain.ProjectDeleteCascade,
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetProject(gomock.Any(), "project1").Return(mockProject, nil)
+				mock.EXPECT().ListUserTodos(gomock.Any(), "user1").Return(newTodos(), nil)
+				mock.EXPECT().ListArchivedTodos(gomock.Any(), "user1").Return(nil, nil)
+				mock.EXPECT().DeleteTodo(gomock.Any(), "todo1").Return(nil)
+				mock.EXPECT().DeleteProject(gomock.Any(), "project1").Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Project not found": {
+			projectID: "nonexistent",
+			mode:      domain.ProjectDeleteDetach,
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetProject(gomock.Any(), "nonexistent").
+					Return(nil, errors.New("project not found"))
+			},
+			expectErr: errors.New("project not found"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupFunc(mockStore)
+
+			service := NewProjectService(mockStore)
+
+			err := service.DeleteProject(auth.WithSystem(context.Background()), tt.projectID, tt.mode)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodoInProject(t *testing.T) {
+	tests := map[string]struct {
+		todo      *domain.Todo
+		setupFunc func(mock *mocks.MockDataStore)
+		expectErr error
+	}{
+		"Success: Project belongs to the user": {
+			todo: &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project1"},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetProject(gomock.Any(), "project1").
+					Return(&domain.Project{ID: "project1", UserID: "user1"}, nil)
+				mock.EXPECT().CreateTodo(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Project belongs to another user": {
+			todo: &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project2"},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetProject(gomock.Any(), "project2").
+					Return(&domain.Project{ID: "project2", UserID: "user2"}, nil)
+			},
+			expectErr: errors.New("project does not belong to the todo's user"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().
+				GetUser(gomock.Any(), "user1").
+				Return(&domain.User{ID: "user1"}, nil)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			err := service.CreateTodo(auth.WithSystem(context.Background()), tt.todo)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package biginterface
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 )
@@ -22,28 +25,59 @@ func NewUserService(store biginterface.DataStore) *UserService {
 
 // GetUser retrieves a user
 func (s *UserService) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	if err := auth.Authorize(ctx, id); err != nil {
+		return nil, err
+	}
 	return s.store.GetUser(ctx, id)
 }
 
 // CreateUser creates a new user
 func (s *UserService) CreateUser(ctx context.Context, user *domain.User) error {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
 	return s.store.CreateUser(ctx, user)
 }
 
+// DeleteUser moves a user to the trash
+// Use TrashService to restore or purge it
+func (s *UserService) DeleteUser(ctx context.Context, id string) error {
+	if err := auth.Authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.store.DeleteUser(ctx, id)
+}
+
 // TodoService is a service that provides Todo-related operations
 type TodoService struct {
 	store biginterface.DataStore // Using the same big interface
+	now   func() time.Time
+	newID func() string
 }
 
 // NewTodoService creates a new TodoService
 func NewTodoService(store biginterface.DataStore) *TodoService {
 	return &TodoService{
 		store: store,
+		now:   time.Now,
+		newID: domain.NewID,
 	}
 }
 
+// inTx runs fn in a transaction with a copy of the service whose store belongs to it
+func (s *TodoService) inTx(ctx context.Context, fn func(ctx context.Context, tx *TodoService) error) error {
+	return s.store.WithinTx(ctx, func(ctx context.Context, store biginterface.DataStore) error {
+		tx := *s
+		tx.store = store
+		return fn(ctx, &tx)
+	})
+}
+
 // GetUserTodos retrieves a user's Todo list
 func (s *TodoService) GetUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
 	// When we need to check if a user exists,
 	// we can access user information through the big interface here as well
 	_, err := s.store.GetUser(ctx, userID)
@@ -56,16 +90,769 @@ func (s *TodoService) GetUserTodos(ctx context.Context, userID string) ([]*domai
 
 // CreateTodo creates a new Todo
 func (s *TodoService) CreateTodo(ctx context.Context, todo *domain.Todo) error {
-	// Check if user exists
-	_, err := s.store.GetUser(ctx, todo.UserID)
-	if err != nil {
-		return errors.New("cannot create todo for non-existent user")
+	if err := auth.Authorize(ctx, todo.UserID); err != nil {
+		return err
 	}
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		// Check if user exists
+		_, err := tx.store.GetUser(ctx, todo.UserID)
+		if err != nil {
+			return errors.New("cannot create todo for non-existent user")
+		}
+		if todo.ProjectID != "" {
+			if err := tx.checkProjectOwner(ctx, todo.ProjectID, todo.UserID); err != nil {
+				return err
+			}
+		}
+		if todo.ParentID != "" {
+			if err := tx.checkParent(ctx, todo); err != nil {
+				return err
+			}
+		}
+		if todo.Recurrence != nil {
+			if err := todo.Recurrence.Validate(); err != nil {
+				return err
+			}
+		}
 
-	return s.store.CreateTodo(ctx, todo)
+		return tx.store.CreateTodo(ctx, todo)
+	})
 }
 
 // CompleteTodo marks a Todo as complete
+// When it was the last incomplete subtask, its parent is completed as well,
+// and when it is recurring, its next occurrence is created
 func (s *TodoService) CompleteTodo(ctx context.Context, id string) error {
-	return s.store.MarkTodoComplete(ctx, id)
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		todo, err := tx.store.GetTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.authorizeShared(ctx, todo, domain.ShareEditor); err != nil {
+			return err
+		}
+		// Completing twice must not create a second next occurrence
+		if todo.Completed {
+			return nil
+		}
+		if err := tx.store.MarkTodoComplete(ctx, id); err != nil {
+			return err
+		}
+		if todo.Recurrence != nil {
+			if err := tx.createNextOccurrence(ctx, todo); err != nil {
+				return err
+			}
+		}
+		return tx.completeParentIfDone(ctx, todo)
+	})
+}
+
+// SetDueDate sets the due date of a Todo, or clears it when dueAt is nil
+func (s *TodoService) SetDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	if err := s.authorizeTodo(ctx, id, domain.ShareEditor); err != nil {
+		return err
+	}
+	return s.store.SetTodoDueDate(ctx, id, dueAt)
+}
+
+// SetPriority changes the priority of a Todo
+func (s *TodoService) SetPriority(ctx context.Context, id string, priority domain.Priority) error {
+	if !priority.IsValid() {
+		return fmt.Errorf("invalid priority: %d", priority)
+	}
+	if err := s.authorizeTodo(ctx, id, domain.ShareEditor); err != nil {
+		return err
+	}
+	return s.store.SetTodoPriority(ctx, id, priority)
+}
+
+// GetOverdueTodos retrieves a user's incomplete Todos whose due date has passed
+func (s *TodoService) GetOverdueTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.store.ListOverdueTodos(ctx, userID, s.now())
+}
+
+// GetTodosDueWithin retrieves a user's incomplete Todos due in the next days days
+func (s *TodoService) GetTodosDueWithin(ctx context.Context, userID string, days int) ([]*domain.Todo, error) {
+	if days < 0 {
+		return nil, fmt.Errorf("days must not be negative: %d", days)
+	}
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	now := s.now()
+	return s.store.ListTodosDueBetween(ctx, userID, now, now.AddDate(0, 0, days))
+}
+
+// GetUserTodosByPriority retrieves a user's Todos sorted by priority, then by due date
+func (s *TodoService) GetUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.store.ListUserTodosByPriority(ctx, userID)
+}
+
+// AddTag attaches a tag to a Todo
+func (s *TodoService) AddTag(ctx context.Context, todoID string, tag string) error {
+	name := domain.NormalizeTagName(tag)
+	if name == "" {
+		return errors.New("tag cannot be empty")
+	}
+	if err := s.authorizeTodo(ctx, todoID, domain.ShareEditor); err != nil {
+		return err
+	}
+	return s.store.AddTodoTag(ctx, todoID, name)
+}
+
+// RemoveTag detaches a tag from a Todo
+func (s *TodoService) RemoveTag(ctx context.Context, todoID string, tag string) error {
+	if err := s.authorizeTodo(ctx, todoID, domain.ShareEditor); err != nil {
+		return err
+	}
+	return s.store.RemoveTodoTag(ctx, todoID, domain.NormalizeTagName(tag))
+}
+
+// GetTodoTags retrieves the tags attached to a Todo
+func (s *TodoService) GetTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error) {
+	if err := s.authorizeTodo(ctx, todoID, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return s.store.ListTodoTags(ctx, todoID)
+}
+
+// GetUserTags retrieves every tag a user has attached to their Todos
+func (s *TodoService) GetUserTags(ctx context.Context, userID string) ([]*domain.Tag, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.store.ListUserTags(ctx, userID)
+}
+
+// GetTodosWithAllTags retrieves a user's Todos that carry every one of the given tags
+func (s *TodoService) GetTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.store.ListTodosWithAllTags(ctx, userID, normalizeTagNames(tags))
+}
+
+// GetTodosWithAnyTag retrieves a user's Todos that carry at least one of the given tags
+func (s *TodoService) GetTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.store.ListTodosWithAnyTag(ctx, userID, normalizeTagNames(tags))
+}
+
+// normalizeTagNames normalizes tag names and drops empty and duplicate ones
+func normalizeTagNames(tags []string) []string {
+	names := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		name := domain.NormalizeTagName(tag)
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// MoveTodoToProject moves a Todo into a project, or out of any project when projectID is empty
+// Its subtasks are moved along with it, so a subtask is always in the project of its parent
+func (s *TodoService) MoveTodoToProject(ctx context.Context, todoID string, projectID string) error {
+	todo, err := s.store.GetTodo(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, todo.UserID); err != nil {
+		return err
+	}
+	if todo.ParentID != "" {
+		return errors.New("cannot move subtask to another project, move its parent instead")
+	}
+	if projectID != "" {
+		if err := s.checkProjectOwner(ctx, projectID, todo.UserID); err != nil {
+			return err
+		}
+	}
+
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		return tx.moveTree(ctx, todo, projectID, s.now())
+	})
+}
+
+func (s *TodoService) moveTree(ctx context.Context, todo *domain.Todo, projectID string, now time.Time) error {
+	subtasks, err := s.store.ListSubtasks(ctx, todo.ID)
+	if err != nil {
+		return err
+	}
+	updated := *todo
+	updated.ProjectID = projectID
+	updated.UpdatedAt = now
+	if err := s.store.UpdateTodo(ctx, &updated); err != nil {
+		return err
+	}
+	for _, subtask := range subtasks {
+		if err := s.moveTree(ctx, subtask, projectID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorizeTodo checks that the principal of ctx may act on the Todo with the given ID with the access of required
+// Without a principal access is denied before the Todo is looked up
+func (s *TodoService) authorizeTodo(ctx context.Context, id string, required domain.ShareRole) error {
+	if _, ok := auth.PrincipalFromContext(ctx); !ok {
+		return domain.ErrPermissionDenied
+	}
+	todo, err := s.store.GetTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.authorizeShared(ctx, todo, required)
+}
+
+// authorizeShared checks that the principal of ctx may act on todo with the access of required
+func (s *TodoService) authorizeShared(ctx context.Context, todo *domain.Todo, required domain.ShareRole) error {
+	return authorizeSharedTodo(ctx, s.store, todo, required)
+}
+
+// checkProjectOwner verifies that a project exists and belongs to the given user
+func (s *TodoService) checkProjectOwner(ctx context.Context, projectID string, userID string) error {
+	project, err := s.store.GetProject(ctx, projectID)
+	if err != nil {
+		return errors.New("project not found")
+	}
+	if project.UserID != userID {
+		return errors.New("project does not belong to the todo's user")
+	}
+	return nil
+}
+
+// checkParent verifies that the parent of a new Todo is a live Todo of the same user
+// with room for one more level of subtasks
+func (s *TodoService) checkParent(ctx context.Context, todo *domain.Todo) error {
+	parent, err := s.store.GetTodo(ctx, todo.ParentID)
+	if err != nil {
+		return fmt.Errorf("parent todo not found: %s", todo.ParentID)
+	}
+	if parent.UserID != todo.UserID {
+		return errors.New("subtask must belong to the same user as its parent")
+	}
+	return s.checkSubtaskDepth(ctx, parent)
+}
+
+// checkSubtaskDepth verifies that a subtask of parent would not be nested deeper than domain.MaxSubtaskDepth
+func (s *TodoService) checkSubtaskDepth(ctx context.Context, parent *domain.Todo) error {
+	depth, err := s.depth(ctx, parent)
+	if err != nil {
+		return err
+	}
+	if depth+1 > domain.MaxSubtaskDepth {
+		return fmt.Errorf("subtasks cannot be nested deeper than %d levels", domain.MaxSubtaskDepth)
+	}
+	return nil
+}
+
+// AddSubtask creates a Todo as the last subtask of another Todo
+// The subtask inherits the parent's owner and project, and nesting is limited to domain.MaxSubtaskDepth
+func (s *TodoService) AddSubtask(ctx context.Context, parentID string, subtask *domain.Todo) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		parent, err := tx.store.GetTodo(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if err := tx.authorizeShared(ctx, parent, domain.ShareEditor); err != nil {
+			return err
+		}
+		if subtask.UserID != "" && subtask.UserID != parent.UserID {
+			return errors.New("subtask must belong to the same user as its parent")
+		}
+		if err := tx.checkSubtaskDepth(ctx, parent); err != nil {
+			return err
+		}
+		siblings, err := tx.store.ListSubtasks(ctx, parentID)
+		if err != nil {
+			return err
+		}
+
+		subtask.UserID = parent.UserID
+		subtask.ProjectID = parent.ProjectID
+		subtask.ParentID = parent.ID
+		subtask.Position = 1
+		if len(siblings) > 0 {
+			subtask.Position = siblings[len(siblings)-1].Position + 1
+		}
+		subtask.PositionSet = true
+		if err := tx.store.CreateTodo(ctx, subtask); err != nil {
+			return err
+		}
+
+		// An incomplete subtask means its ancestors are no longer done
+		if !subtask.Completed {
+			return tx.reopenAncestors(ctx, parent)
+		}
+		return nil
+	})
+}
+
+// ReorderSubtasks puts the subtasks of a Todo in the given order
+// orderedIDs must contain every subtask of the parent exactly once
+func (s *TodoService) ReorderSubtasks(ctx context.Context, parentID string, orderedIDs []string) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		if err := tx.authorizeTodo(ctx, parentID, domain.ShareEditor); err != nil {
+			return err
+		}
+		subtasks, err := tx.store.ListSubtasks(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if len(orderedIDs) != len(subtasks) {
+			return errors.New("ordered IDs must list every subtask exactly once")
+		}
+		byID := make(map[string]*domain.Todo, len(subtasks))
+		for _, subtask := range subtasks {
+			byID[subtask.ID] = subtask
+		}
+		seen := make(map[string]struct{}, len(orderedIDs))
+		for _, id := range orderedIDs {
+			_, known := byID[id]
+			_, dup := seen[id]
+			if !known || dup {
+				return errors.New("ordered IDs must list every subtask exactly once")
+			}
+			seen[id] = struct{}{}
+		}
+
+		for i, id := range orderedIDs {
+			subtask := byID[id]
+			position := float64(i + 1)
+			if subtask.Position == position {
+				continue
+			}
+			updated := *subtask
+			updated.Position = position
+			updated.UpdatedAt = tx.now()
+			if err := tx.store.UpdateTodo(ctx, &updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTodoTree retrieves a Todo together with all of its subtasks
+func (s *TodoService) GetTodoTree(ctx context.Context, id string) (*domain.TodoNode, error) {
+	todo, err := s.store.GetTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeShared(ctx, todo, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return s.buildTree(ctx, todo)
+}
+
+func (s *TodoService) buildTree(ctx context.Context, todo *domain.Todo) (*domain.TodoNode, error) {
+	subtasks, err := s.store.ListSubtasks(ctx, todo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &domain.TodoNode{Todo: todo, Subtasks: make([]*domain.TodoNode, 0, len(subtasks))}
+	for _, subtask := range subtasks {
+		child, err := s.buildTree(ctx, subtask)
+		if err != nil {
+			return nil, err
+		}
+		node.Subtasks = append(node.Subtasks, child)
+	}
+	return node, nil
+}
+
+// depth returns how many ancestors a Todo has
+func (s *TodoService) depth(ctx context.Context, todo *domain.Todo) (int, error) {
+	depth := 0
+	for todo.ParentID != "" {
+		parent, err := s.store.GetTodo(ctx, todo.ParentID)
+		if err != nil {
+			return 0, err
+		}
+		depth++
+		todo = parent
+	}
+	return depth, nil
+}
+
+// completeParentIfDone completes the parent of a Todo once all of its subtasks are complete,
+// and continues up the tree
+func (s *TodoService) completeParentIfDone(ctx context.Context, todo *domain.Todo) error {
+	if todo.ParentID == "" {
+		return nil
+	}
+	siblings, err := s.store.ListSubtasks(ctx, todo.ParentID)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		if !sibling.Completed {
+			return nil
+		}
+	}
+
+	if err := s.store.MarkTodoComplete(ctx, todo.ParentID); err != nil {
+		return err
+	}
+	parent, err := s.store.GetTodo(ctx, todo.ParentID)
+	if err != nil {
+		return err
+	}
+	return s.completeParentIfDone(ctx, parent)
+}
+
+// reopenAncestors marks a Todo and its completed ancestors as incomplete again
+func (s *TodoService) reopenAncestors(ctx context.Context, todo *domain.Todo) error {
+	for todo.Completed {
+		updated := *todo
+		updated.Completed = false
+		updated.CompletedAt = nil
+		updated.UpdatedAt = s.now()
+		if err := s.store.UpdateTodo(ctx, &updated); err != nil {
+			return err
+		}
+		if todo.ParentID == "" {
+			return nil
+		}
+		parent, err := s.store.GetTodo(ctx, todo.ParentID)
+		if err != nil {
+			return err
+		}
+		todo = parent
+	}
+	return nil
+}
+
+// SetRecurrence makes a Todo repeat according to recurrence, or stops it repeating when recurrence is nil
+func (s *TodoService) SetRecurrence(ctx context.Context, id string, recurrence *domain.Recurrence) error {
+	if recurrence != nil {
+		if err := recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+	todo, err := s.store.GetTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeShared(ctx, todo, domain.ShareEditor); err != nil {
+		return err
+	}
+
+	updated := *todo
+	updated.Recurrence = recurrence
+	if recurrence != nil {
+		if updated.SeriesID == "" {
+			updated.SeriesID = updated.ID
+		}
+		if updated.Occurrence == 0 {
+			updated.Occurrence = 1
+		}
+	}
+	updated.UpdatedAt = s.now()
+	return s.store.UpdateTodo(ctx, &updated)
+}
+
+// SetRecurrenceRule is SetRecurrence with the rule given as an RFC 5545 RRULE value
+func (s *TodoService) SetRecurrenceRule(ctx context.Context, id string, rule string) error {
+	recurrence, err := domain.ParseRRule(rule)
+	if err != nil {
+		return err
+	}
+	return s.SetRecurrence(ctx, id, recurrence)
+}
+
+// createNextOccurrence creates the Todo following a completed recurring Todo, with the same tags
+// Todos without a due date repeat relative to the time they were completed
+func (s *TodoService) createNextOccurrence(ctx context.Context, todo *domain.Todo) error {
+	now := s.now()
+	base := now
+	if todo.DueAt != nil {
+		base = *todo.DueAt
+	}
+	occurrence := todo.Occurrence
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	dueAt, ok := todo.Recurrence.NextOccurrence(base, occurrence)
+	if !ok {
+		return nil
+	}
+
+	next := *todo
+	next.ID = s.newID()
+	next.Completed = false
+	next.CompletedAt = nil
+	next.ArchivedAt = nil
+	next.DueAt = &dueAt
+	next.Occurrence = occurrence + 1
+	// Left to the store, which appends it after its siblings instead of sharing the completed todo's place
+	next.Position = 0
+	next.PositionSet = false
+	if next.SeriesID == "" {
+		next.SeriesID = todo.ID
+	}
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	if err := s.store.CreateTodo(ctx, &next); err != nil {
+		return err
+	}
+
+	tags, err := s.store.ListTodoTags(ctx, todo.ID)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := s.store.AddTodoTag(ctx, next.ID, tag.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveTodo moves a Todo to the top or bottom of its siblings, or before or after one of them
+// Siblings are a user's top-level Todos, or the subtasks of the same parent
+func (s *TodoService) MoveTodo(ctx context.Context, id string, move domain.TodoMove) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		todo, err := tx.store.GetTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := auth.Authorize(ctx, todo.UserID); err != nil {
+			return err
+		}
+		siblings, err := tx.siblings(ctx, todo)
+		if err != nil {
+			return err
+		}
+		positions, err := domain.PlanMove(siblings, id, move)
+		if err != nil {
+			return err
+		}
+
+		for _, sibling := range siblings {
+			position, ok := positions[sibling.ID]
+			if !ok {
+				continue
+			}
+			updated := *sibling
+			updated.Position = position
+			updated.UpdatedAt = tx.now()
+			if err := tx.store.UpdateTodo(ctx, &updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// siblings returns the Todos ordered together with todo, including todo itself
+func (s *TodoService) siblings(ctx context.Context, todo *domain.Todo) ([]*domain.Todo, error) {
+	if todo.ParentID != "" {
+		return s.store.ListSubtasks(ctx, todo.ParentID)
+	}
+
+	todos, err := s.store.ListUserTodos(ctx, todo.UserID)
+	if err != nil {
+		return nil, err
+	}
+	topLevel := make([]*domain.Todo, 0, len(todos))
+	for _, t := range todos {
+		if t.ParentID == "" {
+			topLevel = append(topLevel, t)
+		}
+	}
+	return topLevel, nil
+}
+
+// DeleteTodo moves a Todo and all of its subtasks to the trash
+// The store trashes the subtasks along with it
+// Use TrashService to restore or purge them
+func (s *TodoService) DeleteTodo(ctx context.Context, id string) error {
+	todo, err := s.store.GetTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, todo.UserID); err != nil {
+		return err
+	}
+	return s.store.DeleteTodo(ctx, id)
+}
+
+// GetArchivedTodos retrieves a user's archived Todos, which GetUserTodos leaves out
+func (s *TodoService) GetArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.store.ListArchivedTodos(ctx, userID)
+}
+
+// ArchiveTodo archives a Todo together with its subtasks
+func (s *TodoService) ArchiveTodo(ctx context.Context, id string) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		todo, err := tx.store.GetTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := auth.Authorize(ctx, todo.UserID); err != nil {
+			return err
+		}
+		now := tx.now()
+		return tx.setArchivedAt(ctx, todo, &now)
+	})
+}
+
+// UnarchiveTodo brings an archived Todo and its subtasks back into the user's list
+func (s *TodoService) UnarchiveTodo(ctx context.Context, id string) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		todo, err := tx.store.GetTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := auth.Authorize(ctx, todo.UserID); err != nil {
+			return err
+		}
+		if !todo.IsArchived() {
+			return errors.New("todo is not archived")
+		}
+		if todo.ParentID != "" {
+			parent, err := tx.store.GetTodo(ctx, todo.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.IsArchived() {
+				return errors.New("cannot unarchive subtask of an archived todo, unarchive the parent first")
+			}
+		}
+		return tx.setArchivedAt(ctx, todo, nil)
+	})
+}
+
+// ArchiveCompletedTodos archives a user's Todos that were completed more than olderThan ago
+// It returns how many Todos were archived, not counting subtasks archived along with them
+// When archiving one of them fails, none of them are archived
+func (s *TodoService) ArchiveCompletedTodos(ctx context.Context, userID string, olderThan time.Duration) (int, error) {
+	count := 0
+	err := s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		if err := auth.Authorize(ctx, userID); err != nil {
+			return err
+		}
+		_, err := tx.store.GetUser(ctx, userID)
+		if err != nil {
+			return errors.New("user not found")
+		}
+		todos, err := tx.store.ListUserTodos(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		now := tx.now()
+		cutoff := now.Add(-olderThan)
+		archived := make(map[string]struct{})
+		// Todos are listed parents first, so a subtask whose parent was just archived is skipped
+		for _, todo := range todos {
+			if _, ok := archived[todo.ParentID]; ok {
+				archived[todo.ID] = struct{}{}
+				continue
+			}
+			if !completedBefore(todo, cutoff) {
+				continue
+			}
+			if err := tx.setArchivedAt(ctx, todo, &now); err != nil {
+				return err
+			}
+			archived[todo.ID] = struct{}{}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// setArchivedAt archives or unarchives a Todo and all of its subtasks
+func (s *TodoService) setArchivedAt(ctx context.Context, todo *domain.Todo, archivedAt *time.Time) error {
+	tree, err := s.buildTree(ctx, todo)
+	if err != nil {
+		return err
+	}
+
+	var walk func(node *domain.TodoNode) error
+	walk = func(node *domain.TodoNode) error {
+		updated := *node.Todo
+		updated.ArchivedAt = archivedAt
+		updated.UpdatedAt = s.now()
+		if err := s.store.UpdateTodo(ctx, &updated); err != nil {
+			return err
+		}
+		for _, child := range node.Subtasks {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(tree)
+}
+
+// completedBefore reports whether a Todo was completed before cutoff
+// Todos completed before completion times were recorded fall back to their last update
+func completedBefore(todo *domain.Todo, cutoff time.Time) bool {
+	if !todo.Completed {
+		return false
+	}
+	if todo.CompletedAt != nil {
+		return todo.CompletedAt.Before(cutoff)
+	}
+	return todo.UpdatedAt.Before(cutoff)
 }
@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 )
@@ -44,6 +46,17 @@ func setupUserNotFound(mock *mocks.MockDataStore) {
 		Return(nil, errors.New("user not found"))
 }
 
+// allowTx lets the service start transactions, running them against the mock itself
+// so expectations inside a transaction are set as usual
+func allowTx(mock *mocks.MockDataStore) {
+	mock.EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context, biginterface.DataStore) error) error {
+			return fn(ctx, mock)
+		}).
+		AnyTimes()
+}
+
 func TestUserService_GetUser(t *testing.T) {
 	mockUser := &domain.User{
 		ID:        "user1",
@@ -89,7 +102,7 @@ func TestUserService_GetUser(t *testing.T) {
 
 			service := NewUserService(mockStore)
 
-			ctx := context.Background()
+			ctx := auth.WithSystem(context.Background())
 			user, err := service.GetUser(ctx, tt.userID)
 
 			if tt.expectErr != nil {
@@ -177,9 +190,10 @@ func TestTodoService_GetUserTodos(t *testing.T) {
 					Return(tt.expectReturnVal, nil)
 			}
 
+			allowTx(mockStore)
 			service := NewTodoService(mockStore)
 
-			ctx := context.Background()
+			ctx := auth.WithSystem(context.Background())
 			todos, err := service.GetUserTodos(ctx, tt.userID)
 
 			if tt.expectErr != nil {
@@ -198,12 +212,16 @@ func setupTodoMarkComplete(mock *mocks.MockDataStore) {
 	mock.EXPECT().
 		MarkTodoComplete(gomock.Any(), "todo1").
 		Return(nil)
+	// A top-level, non-recurring Todo has no parent to complete and no next occurrence
+	mock.EXPECT().
+		GetTodo(gomock.Any(), "todo1").
+		Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
 }
 
 func setupTodoNotFound(mock *mocks.MockDataStore) {
 	mock.EXPECT().
-		MarkTodoComplete(gomock.Any(), "nonexistent").
-		Return(errors.New("todo not found"))
+		GetTodo(gomock.Any(), "nonexistent").
+		Return(nil, errors.New("todo not found"))
 }
 
 func TestTodoService_CompleteTodo(t *testing.T) {
@@ -231,9 +249,10 @@ func TestTodoService_CompleteTodo(t *testing.T) {
 			mockStore := mocks.NewMockDataStore(ctrl)
 			tt.setupFunc(mockStore)
 
+			allowTx(mockStore)
 			service := NewTodoService(mockStore)
 
-			ctx := context.Background()
+			ctx := auth.WithSystem(context.Background())
 			err := service.CompleteTodo(ctx, tt.todoID)
 
 			if tt.expectErr != nil {
@@ -245,3 +264,664 @@ func TestTodoService_CompleteTodo(t *testing.T) {
 		})
 	}
 }
+
+func TestTodoService_SetPriority(t *testing.T) {
+	tests := map[string]struct {
+		priority  domain.Priority
+		setupFunc func(mock *mocks.MockDataStore)
+		expectErr error
+	}{
+		"Success: Priority changed": {
+			priority: domain.PriorityHigh,
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetTodo(gomock.Any(), "todo1").
+					Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mock.EXPECT().
+					SetTodoPriority(gomock.Any(), "todo1", domain.PriorityHigh).
+					Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Invalid priority": {
+			priority:  domain.Priority(42),
+			setupFunc: func(mock *mocks.MockDataStore) {},
+			expectErr: errors.New("invalid priority"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			err := service.SetPriority(auth.WithSystem(context.Background()), "todo1", tt.priority)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodosDueWithin(t *testing.T) {
+	now := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	dueAt := now.Add(48 * time.Hour)
+	mockTodos := []*domain.Todo{
+		{ID: "todo1", UserID: "user1", Title: "Due soon", DueAt: &dueAt},
+	}
+
+	tests := map[string]struct {
+		userID          string
+		days            int
+		setupFunc       func(mock *mocks.MockDataStore)
+		expectReturnVal []*domain.Todo
+		expectErr       error
+	}{
+		"Success: Todos due within a week": {
+			userID: "user1",
+			days:   7,
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetUser(gomock.Any(), "user1").
+					Return(&domain.User{ID: "user1"}, nil)
+				mock.EXPECT().
+					ListTodosDueBetween(gomock.Any(), "user1", now, now.AddDate(0, 0, 7)).
+					Return(mockTodos, nil)
+			},
+			expectReturnVal: mockTodos,
+			expectErr:       nil,
+		},
+		"Error: User not found": {
+			userID:          "nonexistent",
+			days:            7,
+			setupFunc:       setupUserNotFoundForTodos,
+			expectReturnVal: nil,
+			expectErr:       errors.New("user not found"),
+		},
+		"Error: Negative days": {
+			userID:          "user1",
+			days:            -1,
+			setupFunc:       func(mock *mocks.MockDataStore) {},
+			expectReturnVal: nil,
+			expectErr:       errors.New("days must not be negative"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+			service.now = func() time.Time { return now }
+
+			todos, err := service.GetTodosDueWithin(auth.WithSystem(context.Background()), tt.userID, tt.days)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+				assert.Nil(t, todos)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectReturnVal, todos)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodosWithAllTags(t *testing.T) {
+	mockTodos := []*domain.Todo{
+		{ID: "todo1", UserID: "user1", Title: "Tagged Todo"},
+	}
+
+	tests := map[string]struct {
+		userID          string
+		tags            []string
+		setupFunc       func(mock *mocks.MockDataStore)
+		expectReturnVal []*domain.Todo
+		expectErr       error
+	}{
+		"Success: Tags are normalized before lookup": {
+			userID: "user1",
+			tags:   []string{" Work", "urgent", "work"},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetUser(gomock.Any(), "user1").
+					Return(&domain.User{ID: "user1"}, nil)
+				mock.EXPECT().
+					ListTodosWithAllTags(gomock.Any(), "user1", []string{"work", "urgent"}).
+					Return(mockTodos, nil)
+			},
+			expectReturnVal: mockTodos,
+			expectErr:       nil,
+		},
+		"Error: User not found": {
+			userID:          "nonexistent",
+			tags:            []string{"work"},
+			setupFunc:       setupUserNotFoundForTodos,
+			expectReturnVal: nil,
+			expectErr:       errors.New("user not found"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			todos, err := service.GetTodosWithAllTags(auth.WithSystem(context.Background()), tt.userID, tt.tags)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+				assert.Nil(t, todos)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectReturnVal, todos)
+			}
+		})
+	}
+}
+
+func TestTodoService_CompleteSubtask(t *testing.T) {
+	tests := map[string]struct {
+		siblings  []*domain.Todo
+		setupFunc func(mock *mocks.MockDataStore)
+	}{
+		"Success: Parent is completed with its last subtask": {
+			siblings: []*domain.Todo{
+				{ID: "sub1", ParentID: "parent1", Completed: true},
+				{ID: "sub2", ParentID: "parent1", Completed: true},
+			},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().MarkTodoComplete(gomock.Any(), "parent1").Return(nil)
+				mock.EXPECT().
+					GetTodo(gomock.Any(), "parent1").
+					Return(&domain.Todo{ID: "parent1", Completed: true}, nil)
+			},
+		},
+		"Success: Parent stays open while subtasks remain": {
+			siblings: []*domain.Todo{
+				{ID: "sub1", ParentID: "parent1", Completed: true},
+				{ID: "sub2", ParentID: "parent1", Completed: false},
+			},
+			setupFunc: func(mock *mocks.MockDataStore) {},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().
+				GetTodo(gomock.Any(), "sub1").
+				Return(&domain.Todo{ID: "sub1", ParentID: "parent1"}, nil)
+			mockStore.EXPECT().MarkTodoComplete(gomock.Any(), "sub1").Return(nil)
+			mockStore.EXPECT().
+				ListSubtasks(gomock.Any(), "parent1").
+				Return(tt.siblings, nil)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			err := service.CompleteTodo(auth.WithSystem(context.Background()), "sub1")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoService_AddSubtask(t *testing.T) {
+	tests := map[string]struct {
+		parent    *domain.Todo
+		setupFunc func(mock *mocks.MockDataStore)
+		expectErr error
+	}{
+		"Success: Subtask appended after its siblings": {
+			parent: &domain.Todo{ID: "parent1", UserID: "user1", ProjectID: "project1"},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					ListSubtasks(gomock.Any(), "parent1").
+					Return([]*domain.Todo{{ID: "sub1", Position: 1}}, nil)
+				mock.EXPECT().
+					CreateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						assert.Equal(t, "user1", todo.UserID)
+						assert.Equal(t, "project1", todo.ProjectID)
+						assert.Equal(t, "parent1", todo.ParentID)
+						assert.Equal(t, float64(2), todo.Position)
+						return nil
+					})
+			},
+			expectErr: nil,
+		},
+		"Error: Nesting too deep": {
+			parent: &domain.Todo{ID: "parent1", UserID: "user1", ParentID: "level2"},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					GetTodo(gomock.Any(), "level2").
+					Return(&domain.Todo{ID: "level2", ParentID: "level1"}, nil)
+				mock.EXPECT().
+					GetTodo(gomock.Any(), "level1").
+					Return(&domain.Todo{ID: "level1", ParentID: "root"}, nil)
+				mock.EXPECT().
+					GetTodo(gomock.Any(), "root").
+					Return(&domain.Todo{ID: "root"}, nil)
+			},
+			expectErr: errors.New("subtasks cannot be nested deeper"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().GetTodo(gomock.Any(), "parent1").Return(tt.parent, nil)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			err := service.AddSubtask(auth.WithSystem(context.Background()), "parent1", &domain.Todo{ID: "sub2", Title: "Subtask"})
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodoUnderParent(t *testing.T) {
+	tests := map[string]struct {
+		setupFunc func(mock *mocks.MockDataStore)
+		expectErr error
+	}{
+		"Success: Parent belongs to the same user": {
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetTodo(gomock.Any(), "parent1").Return(&domain.Todo{ID: "parent1", UserID: "user1"}, nil)
+				mock.EXPECT().CreateTodo(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Parent is missing": {
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetTodo(gomock.Any(), "parent1").Return(nil, errors.New("todo not found: parent1"))
+			},
+			expectErr: errors.New("parent todo not found: parent1"),
+		},
+		"Error: Parent belongs to another user": {
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetTodo(gomock.Any(), "parent1").Return(&domain.Todo{ID: "parent1", UserID: "user2"}, nil)
+			},
+			expectErr: errors.New("subtask must belong to the same user as its parent"),
+		},
+		"Error: Nesting too deep": {
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetTodo(gomock.Any(), "parent1").Return(&domain.Todo{ID: "parent1", UserID: "user1", ParentID: "level2"}, nil)
+				mock.EXPECT().GetTodo(gomock.Any(), "level2").Return(&domain.Todo{ID: "level2", ParentID: "level1"}, nil)
+				mock.EXPECT().GetTodo(gomock.Any(), "level1").Return(&domain.Todo{ID: "level1", ParentID: "root"}, nil)
+				mock.EXPECT().GetTodo(gomock.Any(), "root").Return(&domain.Todo{ID: "root"}, nil)
+			},
+			expectErr: errors.New("subtasks cannot be nested deeper"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			err := service.CreateTodo(auth.WithSystem(context.Background()), &domain.Todo{ID: "sub1", UserID: "user1", ParentID: "parent1"})
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_CompleteRecurringTodo(t *testing.T) {
+	dueAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	nextDueAt := time.Date(2024, 4, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		recurrence *domain.Recurrence
+		setupFunc  func(mock *mocks.MockDataStore)
+	}{
+		"Success: Next occurrence is created with the same tags": {
+			recurrence: &domain.Recurrence{Frequency: domain.FrequencyWeekly},
+			setupFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().
+					CreateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						assert.Equal(t, "todo2", todo.ID)
+						assert.Equal(t, "todo1", todo.SeriesID)
+						assert.Equal(t, 2, todo.Occurrence)
+						assert.False(t, todo.Completed)
+						assert.Equal(t, nextDueAt, *todo.DueAt)
+						assert.Zero(t, todo.Position)
+						assert.False(t, todo.PositionSet)
+						return nil
+					})
+				mock.EXPECT().
+					ListTodoTags(gomock.Any(), "todo1").
+					Return([]*domain.Tag{{UserID: "user1", Name: "chores"}}, nil)
+				mock.EXPECT().AddTodoTag(gomock.Any(), "todo2", "chores").Return(nil)
+			},
+		},
+		"Success: Series has ended": {
+			recurrence: &domain.Recurrence{Frequency: domain.FrequencyWeekly, Count: 1},
+			setupFunc:  func(mock *mocks.MockDataStore) {},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().
+				GetTodo(gomock.Any(), "todo1").
+				Return(&domain.Todo{ID: "todo1", UserID: "user1", DueAt: &dueAt, Recurrence: tt.recurrence, Occurrence: 1, Position: 3}, nil)
+			mockStore.EXPECT().MarkTodoComplete(gomock.Any(), "todo1").Return(nil)
+			tt.setupFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+			service.newID = func() string { return "todo2" }
+
+			err := service.CompleteTodo(auth.WithSystem(context.Background()), "todo1")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoService_MoveTodo(t *testing.T) {
+	// Built per test case because MoveTodo works on the listed Todos
+	newTodos := func() []*domain.Todo {
+		return []*domain.Todo{
+			{ID: "todo1", UserID: "user1", Position: 1},
+			{ID: "sub1", UserID: "user1", ParentID: "todo1", Position: 1},
+			{ID: "todo2", UserID: "user1", Position: 2},
+			{ID: "todo3", UserID: "user1", Position: 3},
+		}
+	}
+
+	tests := map[string]struct {
+		move           domain.TodoMove
+		expectPosition float64
+		expectErr      error
+	}{
+		"Success: Move before another todo": {
+			move:           domain.TodoMove{Placement: domain.PlaceBefore, TargetID: "todo1"},
+			expectPosition: 0,
+			expectErr:      nil,
+		},
+		"Success: Move after another todo": {
+			move:           domain.TodoMove{Placement: domain.PlaceAfter, TargetID: "todo1"},
+			expectPosition: 1.5,
+			expectErr:      nil,
+		},
+		"Error: Target is a subtask, not a sibling": {
+			move:      domain.TodoMove{Placement: domain.PlaceAfter, TargetID: "sub1"},
+			expectErr: errors.New("target todo is not a sibling"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			todos := newTodos()
+			mockStore.EXPECT().GetTodo(gomock.Any(), "todo3").Return(todos[3], nil)
+			mockStore.EXPECT().ListUserTodos(gomock.Any(), "user1").Return(todos, nil)
+			if tt.expectErr == nil {
+				mockStore.EXPECT().
+					UpdateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						assert.Equal(t, "todo3", todo.ID)
+						assert.Equal(t, tt.expectPosition, todo.Position)
+						return nil
+					})
+			}
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+
+			err := service.MoveTodo(auth.WithSystem(context.Background()), "todo3", tt.move)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_ArchiveCompletedTodos(t *testing.T) {
+	now := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	longAgo := now.AddDate(0, 0, -40)
+	recently := now.AddDate(0, 0, -2)
+
+	tests := map[string]struct {
+		setupUserFunc func(mock *mocks.MockDataStore)
+		setupTodoFunc func(mock *mocks.MockDataStore)
+		expectCount   int
+		expectErr     error
+	}{
+		"Success: Archive todos completed before the cutoff with their subtasks": {
+			setupUserFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockDataStore) {
+				todo1 := &domain.Todo{ID: "todo1", UserID: "user1", Completed: true, CompletedAt: &longAgo}
+				sub1 := &domain.Todo{ID: "sub1", UserID: "user1", ParentID: "todo1", Completed: true, CompletedAt: &longAgo}
+				mock.EXPECT().
+					ListUserTodos(gomock.Any(), "user1").
+					Return([]*domain.Todo{
+						todo1,
+						sub1,
+						{ID: "todo2", UserID: "user1", Completed: true, CompletedAt: &recently},
+						{ID: "todo3", UserID: "user1", UpdatedAt: longAgo},
+					}, nil)
+				mock.EXPECT().ListSubtasks(gomock.Any(), "todo1").Return([]*domain.Todo{sub1}, nil)
+				mock.EXPECT().ListSubtasks(gomock.Any(), "sub1").Return(nil, nil)
+				mock.EXPECT().
+					UpdateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						require.NotNil(t, todo.ArchivedAt)
+						assert.Equal(t, now, *todo.ArchivedAt)
+						return nil
+					}).
+					Times(2)
+			},
+			expectCount: 1,
+			expectErr:   nil,
+		},
+		"Error: User not found": {
+			setupUserFunc: func(mock *mocks.MockDataStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "nonexistent").Return(nil, errors.New("user not found"))
+			},
+			setupTodoFunc: func(mock *mocks.MockDataStore) {},
+			expectErr:     errors.New("user not found"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			tt.setupUserFunc(mockStore)
+			tt.setupTodoFunc(mockStore)
+
+			allowTx(mockStore)
+			service := NewTodoService(mockStore)
+			service.now = func() time.Time { return now }
+
+			userID := "user1"
+			if tt.expectErr != nil {
+				userID = "nonexistent"
+			}
+			count, err := service.ArchiveCompletedTodos(auth.WithSystem(context.Background()), userID, 30*24*time.Hour)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectCount, count)
+			}
+		})
+	}
+}
+
+func TestTodoService_DeleteTodo(t *testing.T) {
+	tests := map[string]struct {
+		deleteErr error
+		expectErr error
+	}{
+		"Success: Only the parent is passed on, the store trashes its subtasks along with it": {
+			deleteErr: nil,
+			expectErr: nil,
+		},
+		"Error: Store failure": {
+			deleteErr: errors.New("disk full"),
+			expectErr: errors.New("disk full"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+
+			mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+			mockStore.EXPECT().DeleteTodo(gomock.Any(), "todo1").Return(tt.deleteErr)
+
+			service := NewTodoService(mockStore)
+
+			err := service.DeleteTodo(auth.WithSystem(context.Background()), "todo1")
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_MoveTodoToProject(t *testing.T) {
+	tests := map[string]struct {
+		todoID           string
+		updateSubtaskErr error
+		expectMoved      []string
+		expectErr        error
+	}{
+		"Success: Todo and subtask are moved in one transaction": {
+			todoID:      "todo1",
+			expectMoved: []string{"todo1", "sub1"},
+			expectErr:   nil,
+		},
+		"Error: Failing subtask fails the whole transaction": {
+			todoID:           "todo1",
+			updateSubtaskErr: errors.New("disk full"),
+			expectMoved:      []string{"todo1"},
+			expectErr:        errors.New("disk full"),
+		},
+		"Error: Subtask cannot be moved on its own": {
+			todoID:    "sub1",
+			expectErr: errors.New("cannot move subtask to another project"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+
+			todos := map[string]*domain.Todo{
+				"todo1": {ID: "todo1", UserID: "user1", ProjectID: "proj1"},
+				"sub1":  {ID: "sub1", UserID: "user1", ProjectID: "proj1", ParentID: "todo1"},
+			}
+			mockStore.EXPECT().GetTodo(gomock.Any(), tt.todoID).Return(todos[tt.todoID], nil)
+			// The store sees the error, which is what makes a real store roll back
+			var txErr error
+			var moved []string
+			if tt.todoID == "todo1" {
+				mockStore.EXPECT().
+					WithinTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(context.Context, biginterface.DataStore) error) error {
+						txErr = fn(ctx, mockStore)
+						return txErr
+					})
+				mockStore.EXPECT().ListSubtasks(gomock.Any(), "todo1").Return([]*domain.Todo{todos["sub1"]}, nil)
+				mockStore.EXPECT().ListSubtasks(gomock.Any(), "sub1").Return(nil, nil).MaxTimes(1)
+				mockStore.EXPECT().
+					UpdateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						assert.Empty(t, todo.ProjectID)
+						if todo.ID == "sub1" && tt.updateSubtaskErr != nil {
+							return tt.updateSubtaskErr
+						}
+						moved = append(moved, todo.ID)
+						return nil
+					}).
+					Times(2)
+			}
+
+			service := NewTodoService(mockStore)
+
+			err := service.MoveTodoToProject(auth.WithSystem(context.Background()), tt.todoID, "")
+
+			assert.Equal(t, tt.expectMoved, moved)
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+				if txErr != nil {
+					assert.Equal(t, err, txErr)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package biginterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface/mocks"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestTodoService_ShareTodo(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := now.Add(-time.Hour)
+	todo := &domain.Todo{ID: "todo1", UserID: "user1"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		userID     string
+		role       domain.ShareRole
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+		expectMsg  string
+	}{
+		"Success: Owner shares a Todo": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			userID:    "user2",
+			role:      domain.ShareViewer,
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+				mockStore.EXPECT().PutShare(gomock.Any(), &domain.Share{
+					ResourceType: domain.ShareResourceTodo,
+					ResourceID:   "todo1",
+					OwnerID:      "user1",
+					UserID:       "user2",
+					Role:         domain.ShareViewer,
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				}).Return(nil)
+			},
+		},
+		"Success: Sharing again changes the role and keeps the creation time": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			userID:    "user2",
+			role:      domain.ShareEditor,
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer, CreatedAt: created}, nil)
+				mockStore.EXPECT().PutShare(gomock.Any(), &domain.Share{
+					ResourceType: domain.ShareResourceTodo,
+					ResourceID:   "todo1",
+					OwnerID:      "user1",
+					UserID:       "user2",
+					Role:         domain.ShareEditor,
+					CreatedAt:    created,
+					UpdatedAt:    now,
+				}).Return(nil)
+			},
+		},
+		"Error: Editor shares the Todo further": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			userID:    "user3",
+			role:      domain.ShareViewer,
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: User does not exist": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			userID:    "nonexistent",
+			role:      domain.ShareViewer,
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetUser(gomock.Any(), "nonexistent").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+		"Error: Sharing with the owner": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			userID:    "user1",
+			role:      domain.ShareViewer,
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+			},
+			expectMsg: "cannot share with the owner",
+		},
+		"Error: Invalid role": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			userID:    "user2",
+			role:      domain.ShareRole("owner"),
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+			},
+			expectMsg: `invalid share role: "owner"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			allowTx(mockStore)
+			tt.setupMocks(mockStore)
+
+			service := NewTodoService(mockStore)
+			service.now = func() time.Time { return now }
+			err := service.ShareTodo(auth.WithPrincipal(context.Background(), tt.principal), "todo1", tt.userID, tt.role)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectMsg != "":
+				assert.EqualError(t, err, tt.expectMsg)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_SharedAccess(t *testing.T) {
+	editor := domain.Principal{UserID: "user2", Role: domain.RoleUser}
+	todo := &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project1"}
+
+	tests := map[string]struct {
+		call       func(ctx context.Context, service *TodoService) error
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+	}{
+		"Success: Editor of the Todo completes it": {
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CompleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				mockStore.EXPECT().MarkTodoComplete(gomock.Any(), "todo1").Return(nil)
+			},
+		},
+		"Success: Editor of the project sets the due date of its Todo": {
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.SetDueDate(ctx, "todo1", nil)
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceProject, "project1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				mockStore.EXPECT().SetTodoDueDate(gomock.Any(), "todo1", nil).Return(nil)
+			},
+		},
+		"Success: Viewer reads the Todo": {
+			call: func(ctx context.Context, service *TodoService) error {
+				_, err := service.GetTodoTree(ctx, "todo1")
+				return err
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+				mockStore.EXPECT().ListSubtasks(gomock.Any(), "todo1").Return([]*domain.Todo{}, nil)
+			},
+		},
+		"Error: Viewer completes the Todo": {
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CompleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+				mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceProject, "project1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Editor deletes the Todo": {
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.DeleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			allowTx(mockStore)
+			tt.setupMocks(mockStore)
+
+			service := NewTodoService(mockStore)
+			err := tt.call(auth.WithPrincipal(context.Background(), editor), service)
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
+
+func TestTodoService_GetSharedTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mocks.NewMockDataStore(ctrl)
+
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	shared := &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project1"}
+	inProject := &domain.Todo{ID: "todo2", UserID: "user1", ProjectID: "project1"}
+	elsewhere := &domain.Todo{ID: "todo3", UserID: "user1"}
+	archived := &domain.Todo{ID: "todo4", UserID: "user3", ArchivedAt: &archivedAt}
+
+	mockStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+	mockStore.EXPECT().ListUserShares(gomock.Any(), "user2").Return([]*domain.Share{
+		{ResourceType: domain.ShareResourceProject, ResourceID: "project1", OwnerID: "user1", UserID: "user2"},
+		{ResourceType: domain.ShareResourceTodo, ResourceID: "todo1", OwnerID: "user1", UserID: "user2"},
+		{ResourceType: domain.ShareResourceTodo, ResourceID: "todo4", OwnerID: "user3", UserID: "user2"},
+		{ResourceType: domain.ShareResourceTodo, ResourceID: "deleted", OwnerID: "user3", UserID: "user2"},
+	}, nil)
+	mockStore.EXPECT().ListUserTodos(gomock.Any(), "user1").Return([]*domain.Todo{shared, inProject, elsewhere}, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(shared, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "todo4").Return(archived, nil)
+	mockStore.EXPECT().GetTodo(gomock.Any(), "deleted").Return(nil, errors.New("todo not found"))
+
+	service := NewTodoService(mockStore)
+	todos, err := service.GetSharedTodos(auth.WithSystem(context.Background()), "user2")
+
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Todo{shared, inProject}, todos)
+}
+
+func TestTodoService_UnshareTodo(t *testing.T) {
+	tests := map[string]struct {
+		principal  domain.Principal
+		setupMocks func(mockStore *mocks.MockDataStore)
+		expectErr  error
+	}{
+		"Success: Owner unshares the Todo": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().DeleteShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil)
+			},
+		},
+		"Success: User gives up their access": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			setupMocks: func(mockStore *mocks.MockDataStore) {
+				mockStore.EXPECT().DeleteShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil)
+			},
+		},
+		"Error: Another user unshares the Todo": {
+			principal:  domain.Principal{UserID: "user3", Role: domain.RoleUser},
+			setupMocks: func(mockStore *mocks.MockDataStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+			tt.setupMocks(mockStore)
+
+			service := NewTodoService(mockStore)
+			err := service.UnshareTodo(auth.WithPrincipal(context.Background(), tt.principal), "todo1", "user2")
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
+
+func TestProjectService_SharedAccess(t *testing.T) {
+	project := &domain.Project{ID: "project1", UserID: "user1", Name: "Project"}
+
+	tests := map[string]struct {
+		role      domain.ShareRole
+		expectErr error
+	}{
+		"Success: Editor renames the project": {
+			role: domain.ShareEditor,
+		},
+		"Error: Viewer renames the project": {
+			role:      domain.ShareViewer,
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStore := mocks.NewMockDataStore(ctrl)
+			mockStore.EXPECT().GetProject(gomock.Any(), "project1").Return(project, nil)
+			mockStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceProject, "project1", "user2").Return(&domain.Share{Role: tt.role}, nil)
+			if tt.expectErr == nil {
+				mockStore.EXPECT().UpdateProject(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewProjectService(mockStore)
+			ctx := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "user2", Role: domain.RoleUser})
+			err := service.UpdateProject(ctx, &domain.Project{ID: "project1", UserID: "user1", Name: "Renamed"})
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
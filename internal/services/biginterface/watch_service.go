@@ -0,0 +1,39 @@
+package biginterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/biginterface"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// WatchService is a service that streams changes to a user's Todos as they happen
+type WatchService struct {
+	store biginterface.DataStore // Using the same big interface
+}
+
+// NewWatchService creates a new WatchService
+func NewWatchService(store biginterface.DataStore) *WatchService {
+	return &WatchService{
+		store: store,
+	}
+}
+
+// WatchUserTodos streams changes to a user's Todos until ctx is done
+// A non-zero afterRevision resumes a previous watch after the last change it received
+func (s *WatchService) WatchUserTodos(ctx context.Context, userID string, afterRevision int64) (<-chan domain.Change, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return s.store.Watch(ctx, domain.ChangeFilter{
+		Entity:        domain.ChangeEntityTodo,
+		UserID:        userID,
+		AfterRevision: afterRevision,
+	})
+}
@@ -0,0 +1,173 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestTodoService_AssignTodo(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	todo := &domain.Todo{ID: "todo1", UserID: "user1", Title: "Test Todo"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		assigneeID string
+		setupMocks func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore)
+		expectErr  error
+	}{
+		"Success: Owner assigns the Todo to another user": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			assigneeID: "user2",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockTodoStore.EXPECT().UpdateTodo(gomock.Any(), &domain.Todo{
+					ID:         "todo1",
+					UserID:     "user1",
+					AssigneeID: "user2",
+					Title:      "Test Todo",
+					UpdatedAt:  now,
+				}).Return(nil)
+			},
+		},
+		"Success: Editor of the Todo assigns it": {
+			principal:  domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			assigneeID: "user2",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareEditor}, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockTodoStore.EXPECT().UpdateTodo(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"Success: Assigning the current assignee again changes nothing": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			assigneeID: "user2",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1", AssigneeID: "user2"}, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+			},
+		},
+		"Error: Viewer assigns the Todo": {
+			principal:  domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			assigneeID: "user2",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Assignee does not exist": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			assigneeID: "nonexistent",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "nonexistent").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockSharingStore := mocks.NewMockSharingStore(ctrl)
+			tt.setupMocks(mockTodoStore, mockUserStore, mockSharingStore)
+
+			service := NewTodoService(mockTodoStore, mockUserStore, mocks.NewMockTagStore(ctrl), mocks.NewMockProjectStore(ctrl), mockSharingStore, newTxRunner(ctrl, mockUserStore, mockTodoStore))
+			service.now = func() time.Time { return now }
+			err := service.AssignTodo(auth.WithPrincipal(context.Background(), tt.principal), "todo1", tt.assigneeID)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_UnassignTodo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTodoStore := mocks.NewMockTodoStore(ctrl)
+	mockUserStore := mocks.NewMockUserStore(ctrl)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1", AssigneeID: "user2"}, nil)
+	mockTodoStore.EXPECT().UpdateTodo(gomock.Any(), &domain.Todo{ID: "todo1", UserID: "user1", UpdatedAt: now}).Return(nil)
+
+	service := NewTodoService(mockTodoStore, mockUserStore, mocks.NewMockTagStore(ctrl), mocks.NewMockProjectStore(ctrl), mocks.NewMockSharingStore(ctrl), newTxRunner(ctrl, mockUserStore, mockTodoStore))
+	service.now = func() time.Time { return now }
+	err := service.UnassignTodo(auth.WithSystem(context.Background()), "todo1")
+
+	assert.NoError(t, err)
+}
+
+func TestTodoService_GetAssignedTodos(t *testing.T) {
+	assigned := []*domain.Todo{
+		{ID: "todo1", UserID: "user1", AssigneeID: "user2"},
+		{ID: "todo2", UserID: "user2", AssigneeID: "user2"},
+	}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		setupMocks func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore)
+		expected   []*domain.Todo
+		expectErr  error
+	}{
+		"Success: Assignee lists their Todos": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockTodoStore.EXPECT().ListAssignedTodos(gomock.Any(), "user2").Return(assigned, nil)
+			},
+			expected: assigned,
+		},
+		"Error: Another user lists them": {
+			principal:  domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+		"Error: User does not exist": {
+			principal: domain.Principal{UserID: "admin", Role: domain.RoleAdmin},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user2").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			tt.setupMocks(mockTodoStore, mockUserStore)
+
+			service := NewTodoService(mockTodoStore, mockUserStore, mocks.NewMockTagStore(ctrl), mocks.NewMockProjectStore(ctrl), mocks.NewMockSharingStore(ctrl), newTxRunner(ctrl, mockUserStore, mockTodoStore))
+			todos, err := service.GetAssignedTodos(auth.WithPrincipal(context.Background(), tt.principal), "user2")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, todos)
+		})
+	}
+}
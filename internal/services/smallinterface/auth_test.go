@@ -0,0 +1,160 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestTodoService_Authorization(t *testing.T) {
+	owner := domain.Principal{UserID: "user1", Role: domain.RoleUser}
+	other := domain.Principal{UserID: "user2", Role: domain.RoleUser}
+	admin := domain.Principal{UserID: "root", Role: domain.RoleAdmin}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		call       func(ctx context.Context, service *TodoService) error
+		setupMocks func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore)
+		expectErr  error
+	}{
+		"Success: Owner lists their Todos": {
+			principal: owner,
+			call: func(ctx context.Context, service *TodoService) error {
+				_, err := service.GetUserTodos(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockTodoStore.EXPECT().ListUserTodos(gomock.Any(), "user1").Return([]*domain.Todo{}, nil)
+			},
+		},
+		"Error: Another user lists the Todos": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				_, err := service.GetUserTodos(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Success: Admin completes another user's Todo": {
+			principal: admin,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CompleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mockTodoStore.EXPECT().MarkTodoComplete(gomock.Any(), "todo1").Return(nil)
+			},
+		},
+		"Error: Another user completes the Todo": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CompleteTodo(ctx, "todo1")
+			},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Another user sets the priority": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.SetPriority(ctx, "todo1", domain.PriorityHigh)
+			},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+				mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Another user creates a Todo for the owner": {
+			principal: other,
+			call: func(ctx context.Context, service *TodoService) error {
+				return service.CreateTodo(ctx, &domain.Todo{UserID: "user1", Title: "Todo"})
+			},
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTagStore := mocks.NewMockTagStore(ctrl)
+			mockProjectStore := mocks.NewMockProjectStore(ctrl)
+			mockSharingStore := mocks.NewMockSharingStore(ctrl)
+			tt.setupMocks(mockTodoStore, mockUserStore, mockSharingStore)
+
+			service := NewTodoService(mockTodoStore, mockUserStore, mockTagStore, mockProjectStore, mockSharingStore, newTxRunner(ctrl, mockUserStore, mockTodoStore))
+			err := tt.call(auth.WithPrincipal(context.Background(), tt.principal), service)
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
+
+func TestUserService_Authorization(t *testing.T) {
+	tests := map[string]struct {
+		principal  domain.Principal
+		call       func(ctx context.Context, service *UserService) error
+		setupMocks func(mockUserStore *mocks.MockUserStore)
+		expectErr  error
+	}{
+		"Success: User reads themselves": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			call: func(ctx context.Context, service *UserService) error {
+				_, err := service.GetUser(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+		},
+		"Error: User reads another user": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			call: func(ctx context.Context, service *UserService) error {
+				_, err := service.GetUser(ctx, "user1")
+				return err
+			},
+			setupMocks: func(mockUserStore *mocks.MockUserStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+		"Error: User creates a user": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			call: func(ctx context.Context, service *UserService) error {
+				return service.CreateUser(ctx, &domain.User{ID: "user3", Name: "User", Email: "user3@example.com"})
+			},
+			setupMocks: func(mockUserStore *mocks.MockUserStore) {},
+			expectErr:  domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			tt.setupMocks(mockUserStore)
+
+			service := NewUserService(mockUserStore, newTxRunner(ctrl, mockUserStore, mockTodoStore))
+			err := tt.call(auth.WithPrincipal(context.Background(), tt.principal), service)
+
+			assert.Equal(t, tt.expectErr, err)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package smallinterface
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// ImportChecklist creates a user's Todos from a Markdown checklist and returns them in document order
+// Nested items become subtasks and checked boxes completed Todos, imported as they are written
+// Top-level items are added after the user's existing top-level Todos
+func (s *TodoService) ImportChecklist(ctx context.Context, userID string, markdown string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	items, err := domain.ParseChecklist(markdown)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkChecklistDepth(items, 0); err != nil {
+		return nil, err
+	}
+
+	var created []*domain.Todo
+	err = s.inTx(ctx, func(ctx context.Context, tx *TodoService) error {
+		if _, err := tx.userStore.GetUser(ctx, userID); err != nil {
+			return domain.ErrUserNotFound
+		}
+		existing, err := tx.todoStore.ListUserTodos(ctx, userID)
+		if err != nil {
+			return err
+		}
+		var last float64
+		for _, todo := range existing {
+			if todo.ParentID == "" && todo.Position > last {
+				last = todo.Position
+			}
+		}
+
+		created = tx.checklistTodos(userID, "", last, items, nil)
+		return tx.todoStore.CreateTodos(ctx, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// checklistTodos appends the Todos of items and their nested items to todos, parents first
+// Siblings are positioned after last
+func (s *TodoService) checklistTodos(userID, parentID string, last float64, items []*domain.ChecklistItem, todos []*domain.Todo) []*domain.Todo {
+	now := s.now()
+	for i, item := range items {
+		todo := &domain.Todo{
+			ID:          s.newID(),
+			UserID:      userID,
+			ParentID:    parentID,
+			Position:    last + float64(i+1),
+			PositionSet: true,
+			Title:       item.Title,
+			Completed:   item.Completed,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if item.Completed {
+			todo.CompletedAt = &now
+		}
+		todos = append(todos, todo)
+		todos = s.checklistTodos(userID, todo.ID, 0, item.Items, todos)
+	}
+	return todos
+}
+
+func checkChecklistDepth(items []*domain.ChecklistItem, depth int) error {
+	for _, item := range items {
+		if len(item.Items) == 0 {
+			continue
+		}
+		if depth+1 > domain.MaxSubtaskDepth {
+			return fmt.Errorf("subtasks cannot be nested deeper than %d levels", domain.MaxSubtaskDepth)
+		}
+		if err := checkChecklistDepth(item.Items, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportChecklist renders a user's Todos as a Markdown checklist, with subtasks nested below their parents
+// Archived Todos are left out, and subtasks of an archived Todo are listed at the top level
+func (s *TodoService) ExportChecklist(ctx context.Context, userID string) (string, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return "", err
+	}
+	if _, err := s.userStore.GetUser(ctx, userID); err != nil {
+		return "", domain.ErrUserNotFound
+	}
+	todos, err := s.todoStore.ListUserTodos(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	live := make(map[string]struct{}, len(todos))
+	for _, todo := range todos {
+		live[todo.ID] = struct{}{}
+	}
+	children := make(map[string][]*domain.Todo)
+	for _, todo := range todos {
+		parentID := todo.ParentID
+		if _, ok := live[parentID]; !ok {
+			parentID = ""
+		}
+		children[parentID] = append(children[parentID], todo)
+	}
+	return domain.RenderChecklist(checklistItems(children, "")), nil
+}
+
+// checklistItems returns the checklist items of the Todos below parentID in manual order
+func checklistItems(children map[string][]*domain.Todo, parentID string) []*domain.ChecklistItem {
+	todos := children[parentID]
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].Position < todos[j].Position
+	})
+	items := make([]*domain.ChecklistItem, 0, len(todos))
+	for _, todo := range todos {
+		items = append(items, &domain.ChecklistItem{
+			Title:     todo.Title,
+			Completed: todo.Completed,
+			Items:     checklistItems(children, todo.ID),
+		})
+	}
+	return items
+}
@@ -0,0 +1,148 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestTodoService_ImportChecklist(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		markdown     string
+		setupMocks   func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore)
+		expectTodos  []*domain.Todo
+		expectErr    error
+		expectErrMsg string
+	}{
+		"Success: Items are created after the existing Todos, subtasks below their parents": {
+			markdown: "- [ ] Groceries\n  - [x] Milk\n- [x] Laundry\n",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockTodoStore.EXPECT().ListUserTodos(gomock.Any(), "user1").Return([]*domain.Todo{
+					{ID: "old1", UserID: "user1", Position: 2},
+					{ID: "old2", UserID: "user1", ParentID: "old1", Position: 5},
+				}, nil)
+				mockTodoStore.EXPECT().CreateTodos(gomock.Any(), gomock.Len(3)).Return(nil)
+			},
+			expectTodos: []*domain.Todo{
+				{ID: "id1", UserID: "user1", Position: 3, PositionSet: true, Title: "Groceries", CreatedAt: now, UpdatedAt: now},
+				{ID: "id2", UserID: "user1", ParentID: "id1", Position: 1, PositionSet: true, Title: "Milk", Completed: true, CompletedAt: &now, CreatedAt: now, UpdatedAt: now},
+				{ID: "id3", UserID: "user1", Position: 4, PositionSet: true, Title: "Laundry", Completed: true, CompletedAt: &now, CreatedAt: now, UpdatedAt: now},
+			},
+		},
+		"Error: User does not exist": {
+			markdown: "- [ ] Groceries\n",
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+		"Error: Items are nested too deeply": {
+			markdown:     "- [ ] 1\n  - [ ] 2\n    - [ ] 3\n      - [ ] 4\n        - [ ] 5\n",
+			setupMocks:   func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {},
+			expectErrMsg: "cannot be nested deeper than 3 levels",
+		},
+		"Error: Markdown is not a checklist": {
+			markdown:     "# Groceries\n",
+			setupMocks:   func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {},
+			expectErrMsg: "line 1: not a checklist item",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTagStore := mocks.NewMockTagStore(ctrl)
+			mockProjectStore := mocks.NewMockProjectStore(ctrl)
+			tt.setupMocks(mockTodoStore, mockUserStore)
+
+			service := NewTodoService(mockTodoStore, mockUserStore, mockTagStore, mockProjectStore, mocks.NewMockSharingStore(ctrl), newTxRunner(ctrl, mockUserStore, mockTodoStore))
+			service.now = func() time.Time { return now }
+			ids := 0
+			service.newID = func() string {
+				ids++
+				return fmt.Sprintf("id%d", ids)
+			}
+
+			todos, err := service.ImportChecklist(auth.WithSystem(context.Background()), "user1", tt.markdown)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectErrMsg != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErrMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectTodos, todos)
+			}
+		})
+	}
+}
+
+func TestTodoService_ExportChecklist(t *testing.T) {
+	tests := map[string]struct {
+		setupMocks     func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore)
+		expectMarkdown string
+		expectErr      error
+	}{
+		"Success: Subtasks are nested below their parents in manual order": {
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockTodoStore.EXPECT().ListUserTodos(gomock.Any(), "user1").Return([]*domain.Todo{
+					{ID: "todo2", UserID: "user1", Position: 2, Title: "Laundry", Completed: true},
+					{ID: "todo4", UserID: "user1", ParentID: "todo1", Position: 2, Title: "Eggs"},
+					{ID: "todo1", UserID: "user1", Position: 1, Title: "Groceries"},
+					{ID: "todo3", UserID: "user1", ParentID: "todo1", Position: 1, Title: "Milk", Completed: true},
+					// The parent of todo5 is archived
+					{ID: "todo5", UserID: "user1", ParentID: "archived", Position: 3, Title: "Orphan"},
+				}, nil)
+			},
+			expectMarkdown: "- [ ] Groceries\n  - [x] Milk\n  - [ ] Eggs\n- [x] Laundry\n- [ ] Orphan\n",
+		},
+		"Error: User does not exist": {
+			setupMocks: func(mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore) {
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTagStore := mocks.NewMockTagStore(ctrl)
+			mockProjectStore := mocks.NewMockProjectStore(ctrl)
+			tt.setupMocks(mockTodoStore, mockUserStore)
+
+			service := NewTodoService(mockTodoStore, mockUserStore, mockTagStore, mockProjectStore, mocks.NewMockSharingStore(ctrl), newTxRunner(ctrl, mockUserStore, mockTodoStore))
+
+			markdown, err := service.ExportChecklist(auth.WithSystem(context.Background()), "user1")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectMarkdown, markdown)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// CommentService is a service that provides comment-related operations
+type CommentService struct {
+	commentStore smallinterface.CommentStore // Using the small comment interface
+	todoStore    smallinterface.TodoStore    // Needed to check that the Todo exists and who may see it
+	userStore    smallinterface.UserStore    // Needed to check that the author exists
+	sharingStore smallinterface.SharingStore // Lets users a Todo is shared with comment on it
+	now          func() time.Time
+	newID        func() string
+}
+
+// NewCommentService creates a new CommentService
+func NewCommentService(
+	commentStore smallinterface.CommentStore,
+	todoStore smallinterface.TodoStore,
+	userStore smallinterface.UserStore,
+	sharingStore smallinterface.SharingStore,
+) *CommentService {
+	return &CommentService{
+		commentStore: commentStore,
+		todoStore:    todoStore,
+		userStore:    userStore,
+		sharingStore: sharingStore,
+		now:          time.Now,
+		newID:        domain.NewID,
+	}
+}
+
+// AddComment adds a comment by authorID to a Todo
+// Anyone who may read the Todo, including viewers it is shared with, may comment on it
+func (s *CommentService) AddComment(ctx context.Context, todoID string, authorID string, body string) (*domain.Comment, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, errors.New("comment body cannot be empty")
+	}
+	if err := auth.Authorize(ctx, authorID); err != nil {
+		return nil, err
+	}
+	todo, err := s.todoStore.GetTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeSharedTodo(ctx, s.sharingStore, todo, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	if _, err := s.userStore.GetUser(ctx, authorID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	now := s.now()
+	comment := &domain.Comment{
+		ID:        s.newID(),
+		TodoID:    todo.ID,
+		AuthorID:  authorID,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.commentStore.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetTodoComments retrieves the comments of a Todo from the oldest to the newest
+func (s *CommentService) GetTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error) {
+	todo, err := s.todoStore.GetTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeSharedTodo(ctx, s.sharingStore, todo, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return s.commentStore.ListTodoComments(ctx, todo.ID)
+}
+
+// EditComment replaces the body of a comment
+// Only its author may edit it
+func (s *CommentService) EditComment(ctx context.Context, id string, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("comment body cannot be empty")
+	}
+	comment, err := s.commentStore.GetComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, comment.AuthorID); err != nil {
+		return err
+	}
+
+	updated := *comment
+	updated.Body = body
+	updated.UpdatedAt = s.now()
+	return s.commentStore.UpdateComment(ctx, &updated)
+}
+
+// DeleteComment deletes a comment
+// Only its author may delete it
+func (s *CommentService) DeleteComment(ctx context.Context, id string) error {
+	comment, err := s.commentStore.GetComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, comment.AuthorID); err != nil {
+		return err
+	}
+	return s.commentStore.DeleteComment(ctx, id)
+}
@@ -0,0 +1,255 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestCommentService_AddComment(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	todo := &domain.Todo{ID: "todo1", UserID: "user1"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		authorID   string
+		body       string
+		setupMocks func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore)
+		expectErr  error
+		expectMsg  string
+	}{
+		"Success: Owner comments on their Todo": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			authorID:  "user1",
+			body:      "Started on this",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+				mockCommentStore.EXPECT().CreateComment(gomock.Any(), &domain.Comment{
+					ID:        "comment1",
+					TodoID:    "todo1",
+					AuthorID:  "user1",
+					Body:      "Started on this",
+					CreatedAt: now,
+					UpdatedAt: now,
+				}).Return(nil)
+			},
+		},
+		"Success: Viewer of the Todo comments on it": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			authorID:  "user2",
+			body:      "Need help?",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "user2").Return(&domain.User{ID: "user2"}, nil)
+				mockCommentStore.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"Error: Commenting as another user": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			authorID:  "user1",
+			body:      "Impersonated",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Todo is not shared with the author": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			authorID:  "user2",
+			body:      "Hello",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(nil, errors.New("share not found"))
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Author does not exist": {
+			principal: domain.Principal{UserID: "admin", Role: domain.RoleAdmin},
+			authorID:  "nonexistent",
+			body:      "Hello",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+				mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(todo, nil)
+				mockUserStore.EXPECT().GetUser(gomock.Any(), "nonexistent").Return(nil, errors.New("user not found"))
+			},
+			expectErr: domain.ErrUserNotFound,
+		},
+		"Error: Empty body": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			authorID:  "user1",
+			body:      "  ",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore, mockTodoStore *mocks.MockTodoStore, mockUserStore *mocks.MockUserStore, mockSharingStore *mocks.MockSharingStore) {
+			},
+			expectMsg: "comment body cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCommentStore := mocks.NewMockCommentStore(ctrl)
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockSharingStore := mocks.NewMockSharingStore(ctrl)
+			tt.setupMocks(mockCommentStore, mockTodoStore, mockUserStore, mockSharingStore)
+
+			service := NewCommentService(mockCommentStore, mockTodoStore, mockUserStore, mockSharingStore)
+			service.now = func() time.Time { return now }
+			service.newID = func() string { return "comment1" }
+			comment, err := service.AddComment(auth.WithPrincipal(context.Background(), tt.principal), "todo1", tt.authorID, tt.body)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectMsg != "":
+				assert.EqualError(t, err, tt.expectMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "comment1", comment.ID)
+				assert.Equal(t, tt.authorID, comment.AuthorID)
+			}
+		})
+	}
+}
+
+func TestCommentService_GetTodoComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCommentStore := mocks.NewMockCommentStore(ctrl)
+	mockTodoStore := mocks.NewMockTodoStore(ctrl)
+	mockSharingStore := mocks.NewMockSharingStore(ctrl)
+
+	comments := []*domain.Comment{
+		{ID: "comment1", TodoID: "todo1", AuthorID: "user1", Body: "First"},
+		{ID: "comment2", TodoID: "todo1", AuthorID: "user2", Body: "Second"},
+	}
+	mockTodoStore.EXPECT().GetTodo(gomock.Any(), "todo1").Return(&domain.Todo{ID: "todo1", UserID: "user1"}, nil)
+	mockSharingStore.EXPECT().GetShare(gomock.Any(), domain.ShareResourceTodo, "todo1", "user2").Return(&domain.Share{Role: domain.ShareViewer}, nil)
+	mockCommentStore.EXPECT().ListTodoComments(gomock.Any(), "todo1").Return(comments, nil)
+
+	service := NewCommentService(mockCommentStore, mockTodoStore, mocks.NewMockUserStore(ctrl), mockSharingStore)
+	ctx := auth.WithPrincipal(context.Background(), domain.Principal{UserID: "user2", Role: domain.RoleUser})
+	result, err := service.GetTodoComments(ctx, "todo1")
+
+	require.NoError(t, err)
+	assert.Equal(t, comments, result)
+}
+
+func TestCommentService_EditComment(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := now.Add(-time.Hour)
+	comment := &domain.Comment{ID: "comment1", TodoID: "todo1", AuthorID: "user2", Body: "Old", CreatedAt: created, UpdatedAt: created}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		body       string
+		setupMocks func(mockCommentStore *mocks.MockCommentStore)
+		expectErr  error
+		expectMsg  string
+	}{
+		"Success: Author edits their comment": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			body:      "New",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore) {
+				mockCommentStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+				mockCommentStore.EXPECT().UpdateComment(gomock.Any(), &domain.Comment{
+					ID:        "comment1",
+					TodoID:    "todo1",
+					AuthorID:  "user2",
+					Body:      "New",
+					CreatedAt: created,
+					UpdatedAt: now,
+				}).Return(nil)
+			},
+		},
+		"Error: Owner of the Todo edits someone else's comment": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			body:      "New",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore) {
+				mockCommentStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+		"Error: Empty body": {
+			principal:  domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			body:       "",
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore) {},
+			expectMsg:  "comment body cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCommentStore := mocks.NewMockCommentStore(ctrl)
+			tt.setupMocks(mockCommentStore)
+
+			service := NewCommentService(mockCommentStore, mocks.NewMockTodoStore(ctrl), mocks.NewMockUserStore(ctrl), mocks.NewMockSharingStore(ctrl))
+			service.now = func() time.Time { return now }
+			err := service.EditComment(auth.WithPrincipal(context.Background(), tt.principal), "comment1", tt.body)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorIs(t, err, tt.expectErr)
+			case tt.expectMsg != "":
+				assert.EqualError(t, err, tt.expectMsg)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	comment := &domain.Comment{ID: "comment1", TodoID: "todo1", AuthorID: "user2", Body: "Hello"}
+
+	tests := map[string]struct {
+		principal  domain.Principal
+		setupMocks func(mockCommentStore *mocks.MockCommentStore)
+		expectErr  error
+	}{
+		"Success: Author deletes their comment": {
+			principal: domain.Principal{UserID: "user2", Role: domain.RoleUser},
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore) {
+				mockCommentStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+				mockCommentStore.EXPECT().DeleteComment(gomock.Any(), "comment1").Return(nil)
+			},
+		},
+		"Error: Owner of the Todo deletes someone else's comment": {
+			principal: domain.Principal{UserID: "user1", Role: domain.RoleUser},
+			setupMocks: func(mockCommentStore *mocks.MockCommentStore) {
+				mockCommentStore.EXPECT().GetComment(gomock.Any(), "comment1").Return(comment, nil)
+			},
+			expectErr: domain.ErrPermissionDenied,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCommentStore := mocks.NewMockCommentStore(ctrl)
+			tt.setupMocks(mockCommentStore)
+
+			service := NewCommentService(mockCommentStore, mocks.NewMockTodoStore(ctrl), mocks.NewMockUserStore(ctrl), mocks.NewMockSharingStore(ctrl))
+			err := service.DeleteComment(auth.WithPrincipal(context.Background(), tt.principal), "comment1")
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
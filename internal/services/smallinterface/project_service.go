@@ -0,0 +1,174 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// ProjectService is a service that provides project-related operations
+type ProjectService struct {
+	projectStore smallinterface.ProjectStore // Using the small project interface
+	todoStore    smallinterface.TodoStore    // Needed to detach or delete a project's Todos
+	userStore    smallinterface.UserStore    // Needed to check that the owner exists
+	sharingStore smallinterface.SharingStore // Grants other users access to projects
+	now          func() time.Time
+}
+
+// NewProjectService creates a new ProjectService
+func NewProjectService(
+	projectStore smallinterface.ProjectStore,
+	todoStore smallinterface.TodoStore,
+	userStore smallinterface.UserStore,
+	sharingStore smallinterface.SharingStore,
+) *ProjectService {
+	return &ProjectService{
+		projectStore: projectStore,
+		todoStore:    todoStore,
+		userStore:    userStore,
+		sharingStore: sharingStore,
+		now:          time.Now,
+	}
+}
+
+// GetProject retrieves a project
+func (s *ProjectService) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	project, err := s.projectStore.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeShared(ctx, project, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// GetUserProjects retrieves a user's projects
+func (s *ProjectService) GetUserProjects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.userStore.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.projectStore.ListUserProjects(ctx, userID)
+}
+
+// GetProjectTodos retrieves the Todos that belong to a project
+func (s *ProjectService) GetProjectTodos(ctx context.Context, projectID string) ([]*domain.Todo, error) {
+	project, err := s.projectStore.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeShared(ctx, project, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+
+	return s.listProjectTodos(ctx, project)
+}
+
+// CreateProject creates a new project
+func (s *ProjectService) CreateProject(ctx context.Context, project *domain.Project) error {
+	if project.Name == "" {
+		return errors.New("project name cannot be empty")
+	}
+	if err := auth.Authorize(ctx, project.UserID); err != nil {
+		return err
+	}
+	_, err := s.userStore.GetUser(ctx, project.UserID)
+	if err != nil {
+		return errors.New("cannot create project for non-existent user")
+	}
+
+	return s.projectStore.CreateProject(ctx, project)
+}
+
+// UpdateProject updates a project's name and description
+// The owner of a project cannot be changed
+func (s *ProjectService) UpdateProject(ctx context.Context, project *domain.Project) error {
+	if project.Name == "" {
+		return errors.New("project name cannot be empty")
+	}
+	existing, err := s.projectStore.GetProject(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeShared(ctx, existing, domain.ShareEditor); err != nil {
+		return err
+	}
+	if existing.UserID != project.UserID {
+		return errors.New("project owner cannot be changed")
+	}
+
+	updated := *project
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = s.now()
+	return s.projectStore.UpdateProject(ctx, &updated)
+}
+
+// DeleteProject deletes a project
+// Depending on mode, the project's Todos are either kept and detached, or deleted with it
+func (s *ProjectService) DeleteProject(ctx context.Context, id string, mode domain.ProjectDeleteMode) error {
+	project, err := s.projectStore.GetProject(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, project.UserID); err != nil {
+		return err
+	}
+	todos, err := s.listProjectTodos(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	if mode == domain.ProjectDeleteCascade {
+		// Subtasks are deleted along with their parent
+		todos = domain.RootTodos(todos)
+	}
+	for _, todo := range todos {
+		switch mode {
+		case domain.ProjectDeleteDetach:
+			updated := *todo
+			updated.ProjectID = ""
+			updated.UpdatedAt = s.now()
+			err = s.todoStore.UpdateTodo(ctx, &updated)
+		case domain.ProjectDeleteCascade:
+			err = s.todoStore.DeleteTodo(ctx, todo.ID)
+		default:
+			return fmt.Errorf("unknown project delete mode: %d", mode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.projectStore.DeleteProject(ctx, id)
+}
+
+// listProjectTodos returns the Todos of a project, archived ones included
+func (s *ProjectService) listProjectTodos(ctx context.Context, project *domain.Project) ([]*domain.Todo, error) {
+	todos, err := s.todoStore.ListUserTodos(ctx, project.UserID)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.todoStore.ListArchivedTodos(ctx, project.UserID)
+	if err != nil {
+		return nil, err
+	}
+	todos = append(todos, archived...)
+
+	projectTodos := make([]*domain.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if todo.ProjectID == project.ID {
+			projectTodos = append(projectTodos, todo)
+		}
+	}
+	return projectTodos, nil
+}
@@ -0,0 +1,162 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestProjectService_DeleteProject(t *testing.T) {
+	mockProject := &domain.Project{ID: "project1", UserID: "user1", Name: "Work"}
+
+	// Each test case builds its own todo list because DeleteProject modifies Todos
+	newTodos := func() []*domain.Todo {
+		return []*domain.Todo{
+			{ID: "todo1", UserID: "user1", ProjectID: "project1"},
+			{ID: "todo2", UserID: "user1", ProjectID: "project2"},
+		}
+	}
+
+	tests := map[string]struct {
+		projectID        string
+		mode             domain.ProjectDeleteMode
+		setupProjectFunc func(mock *mocks.MockProjectStore)
+		setupTodoFunc    func(mock *mocks.MockTodoStore)
+		expectErr        error
+	}{
+		"Success: Todos are detached": {
+			projectID: "project1",
+			mode:      domain.ProjectDeleteDetach,
+			setupProjectFunc: func(mock *mocks.MockProjectStore) {
+				mock.EXPECT().GetProject(gomock.Any(), "project1").Return(mockProject, nil)
+				mock.EXPECT().DeleteProject(gomock.Any(), "project1").Return(nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {
+				mock.EXPECT().ListUserTodos(gomock.Any(), "user1").Return(newTodos(), nil)
+				mock.EXPECT().ListArchivedTodos(gomock.Any(), "user1").Return(nil, nil)
+				mock.EXPECT().
+					UpdateTodo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *domain.Todo) error {
+						assert.Equal(t, "todo1", todo.ID)
+						assert.Empty(t, todo.ProjectID)
+						return nil
+					})
+			},
+			expectErr: nil,
+		},
+		"Success: Todos are deleted with the project": {
+			projectID: "project1",
+			mode:      domain.ProjectDeleteCascade,
+			setupProjectFunc: func(mock *mocks.MockProjectStore) {
+				mock.EXPECT().GetProject(gomock.Any(), "project1").Return(mockProject, nil)
+				mock.EXPECT().DeleteProject(gomock.Any(), "project1").Return(nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {
+				mock.EXPECT().ListUserTodos(gomock.Any(), "user1").Return(newTodos(), nil)
+				mock.EXPECT().ListArchivedTodos(gomock.Any(), "user1").Return(nil, nil)
+				mock.EXPECT().DeleteTodo(gomock.Any(), "todo1").Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Project not found": {
+			projectID: "nonexistent",
+			mode:      domain.ProjectDeleteDetach,
+			setupProjectFunc: func(mock *mocks.MockProjectStore) {
+				mock.EXPECT().
+					GetProject(gomock.Any(), "nonexistent").
+					Return(nil, errors.New("project not found"))
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {},
+			expectErr:     errors.New("project not found"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			// UserStore is not used when deleting, but ProjectService still depends on it
+			mockProjectStore := mocks.NewMockProjectStore(ctrl)
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			tt.setupProjectFunc(mockProjectStore)
+			tt.setupTodoFunc(mockTodoStore)
+
+			service := NewProjectService(mockProjectStore, mockTodoStore, mockUserStore, mocks.NewMockSharingStore(ctrl))
+
+			err := service.DeleteProject(auth.WithSystem(context.Background()), tt.projectID, tt.mode)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodoInProject(t *testing.T) {
+	tests := map[string]struct {
+		todo             *domain.Todo
+		setupProjectFunc func(mock *mocks.MockProjectStore)
+		setupTodoFunc    func(mock *mocks.MockTodoStore)
+		expectErr        error
+	}{
+		"Success: Project belongs to the user": {
+			todo: &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project1"},
+			setupProjectFunc: func(mock *mocks.MockProjectStore) {
+				mock.EXPECT().
+					GetProject(gomock.Any(), "project1").
+					Return(&domain.Project{ID: "project1", UserID: "user1"}, nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {
+				mock.EXPECT().CreateTodo(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Project belongs to another user": {
+			todo: &domain.Todo{ID: "todo1", UserID: "user1", ProjectID: "project2"},
+			setupProjectFunc: func(mock *mocks.MockProjectStore) {
+				mock.EXPECT().
+					GetProject(gomock.Any(), "project2").
+					Return(&domain.Project{ID: "project2", UserID: "user2"}, nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {},
+			expectErr:     errors.New("project does not belong to the todo's user"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTagStore := mocks.NewMockTagStore(ctrl)
+			mockProjectStore := mocks.NewMockProjectStore(ctrl)
+			setupUserExistsForTodos(mockUserStore)
+			tt.setupProjectFunc(mockProjectStore)
+			tt.setupTodoFunc(mockTodoStore)
+
+			service := NewTodoService(mockTodoStore, mockUserStore, mockTagStore, mockProjectStore, mocks.NewMockSharingStore(ctrl), newTxRunner(ctrl, mockUserStore, mockTodoStore))
+
+			err := service.CreateTodo(auth.WithSystem(context.Background()), tt.todo)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// ShareTodo grants another user access to a Todo, or changes the role of the existing share
+// Only the owner of the Todo may share it
+func (s *TodoService) ShareTodo(ctx context.Context, todoID string, userID string, role domain.ShareRole) error {
+	todo, err := s.todoStore.GetTodo(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, todo.UserID); err != nil {
+		return err
+	}
+	return putShare(ctx, s.sharingStore, s.userStore, &domain.Share{
+		ResourceType: domain.ShareResourceTodo,
+		ResourceID:   todo.ID,
+		OwnerID:      todo.UserID,
+		UserID:       userID,
+		Role:         role,
+	}, s.now())
+}
+
+// UnshareTodo revokes the access a user was granted to a Todo
+// Besides the owner, the user it is shared with may give up their access
+func (s *TodoService) UnshareTodo(ctx context.Context, todoID string, userID string) error {
+	todo, err := s.todoStore.GetTodo(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, todo.UserID); err != nil && auth.Authorize(ctx, userID) != nil {
+		return err
+	}
+	return s.sharingStore.DeleteShare(ctx, domain.ShareResourceTodo, todo.ID, userID)
+}
+
+// GetTodoShares retrieves the users a Todo is shared with
+func (s *TodoService) GetTodoShares(ctx context.Context, todoID string) ([]*domain.Share, error) {
+	todo, err := s.todoStore.GetTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeShared(ctx, todo, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return s.sharingStore.ListResourceShares(ctx, domain.ShareResourceTodo, todo.ID)
+}
+
+// GetSharedTodos retrieves the Todos other users have shared with a user,
+// either directly or through one of their projects
+// Shared Todos that were deleted or archived by their owner are left out
+func (s *TodoService) GetSharedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.userStore.GetUser(ctx, userID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	shares, err := s.sharingStore.ListUserShares(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]*domain.Todo, 0)
+	seen := make(map[string]struct{})
+	add := func(todo *domain.Todo) {
+		if _, ok := seen[todo.ID]; ok || todo.IsArchived() {
+			return
+		}
+		seen[todo.ID] = struct{}{}
+		todos = append(todos, todo)
+	}
+	for _, share := range shares {
+		switch share.ResourceType {
+		case domain.ShareResourceTodo:
+			todo, err := s.todoStore.GetTodo(ctx, share.ResourceID)
+			if err != nil {
+				// The owner deleted the Todo, which takes the share with it once purged
+				continue
+			}
+			add(todo)
+		case domain.ShareResourceProject:
+			ownerTodos, err := s.todoStore.ListUserTodos(ctx, share.OwnerID)
+			if err != nil {
+				return nil, err
+			}
+			for _, todo := range ownerTodos {
+				if todo.ProjectID == share.ResourceID {
+					add(todo)
+				}
+			}
+		}
+	}
+	return todos, nil
+}
+
+// ShareProject grants another user access to a project and every Todo in it,
+// or changes the role of the existing share
+// Only the owner of the project may share it
+func (s *ProjectService) ShareProject(ctx context.Context, projectID string, userID string, role domain.ShareRole) error {
+	project, err := s.projectStore.GetProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, project.UserID); err != nil {
+		return err
+	}
+	return putShare(ctx, s.sharingStore, s.userStore, &domain.Share{
+		ResourceType: domain.ShareResourceProject,
+		ResourceID:   project.ID,
+		OwnerID:      project.UserID,
+		UserID:       userID,
+		Role:         role,
+	}, s.now())
+}
+
+// UnshareProject revokes the access a user was granted to a project
+// Besides the owner, the user it is shared with may give up their access
+func (s *ProjectService) UnshareProject(ctx context.Context, projectID string, userID string) error {
+	project, err := s.projectStore.GetProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, project.UserID); err != nil && auth.Authorize(ctx, userID) != nil {
+		return err
+	}
+	return s.sharingStore.DeleteShare(ctx, domain.ShareResourceProject, project.ID, userID)
+}
+
+// GetProjectShares retrieves the users a project is shared with
+func (s *ProjectService) GetProjectShares(ctx context.Context, projectID string) ([]*domain.Share, error) {
+	project, err := s.projectStore.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeShared(ctx, project, domain.ShareViewer); err != nil {
+		return nil, err
+	}
+	return s.sharingStore.ListResourceShares(ctx, domain.ShareResourceProject, project.ID)
+}
+
+// authorizeShared checks that the principal of ctx may act on project with the access of required
+// Besides its owner and admins, users the project is shared with may, when their share allows it
+func (s *ProjectService) authorizeShared(ctx context.Context, project *domain.Project, required domain.ShareRole) error {
+	err := auth.Authorize(ctx, project.UserID)
+	if errors.Is(err, domain.ErrPermissionDenied) && sharedWith(ctx, s.sharingStore, domain.ShareResourceProject, project.ID, required) {
+		return nil
+	}
+	return err
+}
+
+// authorizeSharedTodo checks that the principal of ctx may act on todo with the access of required
+// Besides its owner and admins, users the Todo or its project is shared with may, when their share allows it
+func authorizeSharedTodo(ctx context.Context, sharingStore smallinterface.SharingStore, todo *domain.Todo, required domain.ShareRole) error {
+	err := auth.Authorize(ctx, todo.UserID)
+	if !errors.Is(err, domain.ErrPermissionDenied) {
+		return err
+	}
+	if sharedWith(ctx, sharingStore, domain.ShareResourceTodo, todo.ID, required) {
+		return nil
+	}
+	if todo.ProjectID != "" && sharedWith(ctx, sharingStore, domain.ShareResourceProject, todo.ProjectID, required) {
+		return nil
+	}
+	return err
+}
+
+// sharedWith reports whether the resource is shared with the principal of ctx with at least the access of required
+func sharedWith(ctx context.Context, sharingStore smallinterface.SharingStore, resourceType domain.ShareResourceType, resourceID string, required domain.ShareRole) bool {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return false
+	}
+	share, err := sharingStore.GetShare(ctx, resourceType, resourceID, principal.UserID)
+	return err == nil && share.Role.Allows(required)
+}
+
+// putShare validates a share and stores it, keeping the creation time of the share it replaces
+func putShare(ctx context.Context, sharingStore smallinterface.SharingStore, userStore smallinterface.UserStore, share *domain.Share, now time.Time) error {
+	if !share.Role.IsValid() {
+		return fmt.Errorf("invalid share role: %q", share.Role)
+	}
+	if share.UserID == share.OwnerID {
+		return errors.New("cannot share with the owner")
+	}
+	if _, err := userStore.GetUser(ctx, share.UserID); err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	share.CreatedAt = now
+	share.UpdatedAt = now
+	if existing, err := sharingStore.GetShare(ctx, share.ResourceType, share.ResourceID, share.UserID); err == nil {
+		share.CreatedAt = existing.CreatedAt
+	}
+	return sharingStore.PutShare(ctx, share)
+}
@@ -0,0 +1,233 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// TrashService is a service that restores and purges deleted users and Todos
+// Entities left in the trash longer than the retention period are purged by PurgeExpired
+type TrashService struct {
+	userStore smallinterface.UserStore // Using the small user interface
+	todoStore smallinterface.TodoStore // Using the small Todo interface
+	txRunner  smallinterface.TxRunner  // Runs restores and purges in a transaction
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewTrashService creates a new TrashService
+// A retention of zero or less keeps trashed entities until they are purged by hand
+func NewTrashService(userStore smallinterface.UserStore, todoStore smallinterface.TodoStore, txRunner smallinterface.TxRunner, retention time.Duration) *TrashService {
+	return &TrashService{
+		userStore: userStore,
+		todoStore: todoStore,
+		txRunner:  txRunner,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// inTx runs fn in a transaction with a copy of the service whose stores belong to it
+func (s *TrashService) inTx(ctx context.Context, fn func(ctx context.Context, tx *TrashService) error) error {
+	return s.txRunner.RunInTx(ctx, func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error {
+		tx := *s
+		tx.userStore = users
+		tx.todoStore = todos
+		return fn(ctx, &tx)
+	})
+}
+
+// GetDeletedUsers retrieves the users in the trash
+func (s *TrashService) GetDeletedUsers(ctx context.Context) ([]*domain.User, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.userStore.ListDeletedUsers(ctx)
+}
+
+// GetDeletedTodos retrieves a user's Todos in the trash
+func (s *TrashService) GetDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.todoStore.ListDeletedTodos(ctx, userID)
+}
+
+// RestoreUser takes a user out of the trash
+func (s *TrashService) RestoreUser(ctx context.Context, id string) error {
+	if err := auth.Authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.userStore.RestoreUser(ctx, id)
+}
+
+// PurgeUser permanently removes a user in the trash together with all of their Todos
+func (s *TrashService) PurgeUser(ctx context.Context, id string) error {
+	if err := auth.Authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(ctx context.Context, tx *TrashService) error {
+		if _, err := tx.userStore.GetDeletedUser(ctx, id); err != nil {
+			return err
+		}
+
+		live, err := tx.todoStore.ListUserTodos(ctx, id)
+		if err != nil {
+			return err
+		}
+		archived, err := tx.todoStore.ListArchivedTodos(ctx, id)
+		if err != nil {
+			return err
+		}
+		live = append(live, archived...)
+		for _, todo := range domain.RootTodos(live) {
+			if err := tx.todoStore.DeleteTodo(ctx, todo.ID); err != nil {
+				return err
+			}
+		}
+		trashed, err := tx.todoStore.ListDeletedTodos(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, todo := range domain.RootTodos(trashed) {
+			if err := tx.todoStore.PurgeTodo(ctx, todo.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.userStore.PurgeUser(ctx, id)
+	})
+}
+
+// RestoreTodo takes a Todo out of the trash together with the subtasks deleted with it
+// Subtasks deleted on their own before it stay in the trash
+func (s *TrashService) RestoreTodo(ctx context.Context, id string) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *TrashService) error {
+		todo, err := tx.todoStore.GetDeletedTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := auth.Authorize(ctx, todo.UserID); err != nil {
+			return err
+		}
+		if _, err := tx.userStore.GetUser(ctx, todo.UserID); err != nil {
+			return errors.New("cannot restore todo of a deleted user, restore the user first")
+		}
+		if todo.ParentID != "" {
+			if _, err := tx.todoStore.GetTodo(ctx, todo.ParentID); err != nil {
+				return errors.New("cannot restore subtask of a deleted todo, restore the parent first")
+			}
+		}
+		descendants, err := tx.trashedDescendants(ctx, todo)
+		if err != nil {
+			return err
+		}
+
+		deletedAt := *todo.DeletedAt
+		if err := tx.todoStore.RestoreTodo(ctx, id); err != nil {
+			return err
+		}
+		for _, descendant := range descendants {
+			if descendant.DeletedAt.Before(deletedAt) {
+				continue
+			}
+			if err := tx.todoStore.RestoreTodo(ctx, descendant.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PurgeTodo permanently removes a Todo in the trash together with its trashed subtasks
+// The store purges the subtasks along with it
+func (s *TrashService) PurgeTodo(ctx context.Context, id string) error {
+	todo, err := s.todoStore.GetDeletedTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(ctx, todo.UserID); err != nil {
+		return err
+	}
+	return s.todoStore.PurgeTodo(ctx, id)
+}
+
+// PurgeExpired permanently removes users and Todos that have been in the trash longer than the retention period
+// It returns how many users and Todos were purged, not counting the Todos purged along with their user
+func (s *TrashService) PurgeExpired(ctx context.Context) (int, int, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return 0, 0, err
+	}
+	if s.retention <= 0 {
+		return 0, 0, nil
+	}
+	cutoff := s.now().Add(-s.retention)
+
+	users, err := s.userStore.ListDeletedUsers(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	purgedUsers := 0
+	for _, user := range users {
+		if !user.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if err := s.PurgeUser(ctx, user.ID); err != nil {
+			return purgedUsers, 0, err
+		}
+		purgedUsers++
+	}
+
+	purgedTodos, err := s.todoStore.PurgeTodosDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return purgedUsers, purgedTodos, err
+	}
+	return purgedUsers, purgedTodos, nil
+}
+
+// Run calls PurgeExpired every interval until ctx is canceled or purging fails
+// As a background job it usually acts on behalf of the process, with a ctx from auth.WithSystem
+func (s *TrashService) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, _, err := s.PurgeExpired(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// trashedDescendants returns the trashed Todos below todo, parents before their subtasks
+func (s *TrashService) trashedDescendants(ctx context.Context, todo *domain.Todo) ([]*domain.Todo, error) {
+	trashed, err := s.todoStore.ListDeletedTodos(ctx, todo.UserID)
+	if err != nil {
+		return nil, err
+	}
+	byParent := make(map[string][]*domain.Todo)
+	for _, t := range trashed {
+		byParent[t.ParentID] = append(byParent[t.ParentID], t)
+	}
+
+	descendants := make([]*domain.Todo, 0)
+	queue := []string{todo.ID}
+	for len(queue) > 0 {
+		parentID := queue[0]
+		queue = queue[1:]
+		for _, child := range byParent[parentID] {
+			descendants = append(descendants, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return descendants, nil
+}
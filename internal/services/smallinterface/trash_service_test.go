@@ -0,0 +1,164 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestTrashService_RestoreTodo(t *testing.T) {
+	deletedAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	earlier := deletedAt.Add(-time.Hour)
+	later := deletedAt.Add(time.Millisecond)
+
+	tests := map[string]struct {
+		todo          *domain.Todo
+		setupUserFunc func(mock *mocks.MockUserStore)
+		setupTodoFunc func(mock *mocks.MockTodoStore)
+		expectErr     error
+	}{
+		"Success: Subtasks deleted with the todo are restored": {
+			todo: &domain.Todo{ID: "todo1", UserID: "user1", DeletedAt: &deletedAt},
+			setupUserFunc: func(mock *mocks.MockUserStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {
+				mock.EXPECT().
+					ListDeletedTodos(gomock.Any(), "user1").
+					Return([]*domain.Todo{
+						{ID: "sub1", ParentID: "todo1", DeletedAt: &earlier},
+						{ID: "todo1", UserID: "user1", DeletedAt: &deletedAt},
+						{ID: "sub2", ParentID: "todo1", DeletedAt: &later},
+						{ID: "sub2a", ParentID: "sub2", DeletedAt: &later},
+					}, nil)
+				mock.EXPECT().RestoreTodo(gomock.Any(), "todo1").Return(nil)
+				mock.EXPECT().RestoreTodo(gomock.Any(), "sub2").Return(nil)
+				mock.EXPECT().RestoreTodo(gomock.Any(), "sub2a").Return(nil)
+			},
+			expectErr: nil,
+		},
+		"Error: Parent todo is still in the trash": {
+			todo: &domain.Todo{ID: "sub1", UserID: "user1", ParentID: "todo1", DeletedAt: &deletedAt},
+			setupUserFunc: func(mock *mocks.MockUserStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {
+				mock.EXPECT().
+					GetTodo(gomock.Any(), "todo1").
+					Return(nil, errors.New("todo not found"))
+			},
+			expectErr: errors.New("restore the parent first"),
+		},
+		"Error: Owner is in the trash": {
+			todo: &domain.Todo{ID: "todo1", UserID: "user1", DeletedAt: &deletedAt},
+			setupUserFunc: func(mock *mocks.MockUserStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "user1").Return(nil, errors.New("user not found"))
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {},
+			expectErr:     errors.New("restore the user first"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			mockTodoStore.EXPECT().GetDeletedTodo(gomock.Any(), tt.todo.ID).Return(tt.todo, nil)
+			tt.setupUserFunc(mockUserStore)
+			tt.setupTodoFunc(mockTodoStore)
+
+			service := NewTrashService(mockUserStore, mockTodoStore, newTxRunner(ctrl, mockUserStore, mockTodoStore), 0)
+
+			err := service.RestoreTodo(auth.WithSystem(context.Background()), tt.todo.ID)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	now := time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC)
+	expired := now.AddDate(0, 0, -31)
+	recent := now.AddDate(0, 0, -1)
+
+	tests := map[string]struct {
+		retention         time.Duration
+		setupUserFunc     func(mock *mocks.MockUserStore)
+		setupTodoFunc     func(mock *mocks.MockTodoStore)
+		expectPurgedUsers int
+		expectPurgedTodos int
+	}{
+		"Success: Only entities older than the retention are purged": {
+			retention: 30 * 24 * time.Hour,
+			setupUserFunc: func(mock *mocks.MockUserStore) {
+				mock.EXPECT().
+					ListDeletedUsers(gomock.Any()).
+					Return([]*domain.User{
+						{ID: "user1", DeletedAt: &expired},
+						{ID: "user2", DeletedAt: &recent},
+					}, nil)
+				mock.EXPECT().
+					GetDeletedUser(gomock.Any(), "user1").
+					Return(&domain.User{ID: "user1", DeletedAt: &expired}, nil)
+				mock.EXPECT().PurgeUser(gomock.Any(), "user1").Return(nil)
+			},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {
+				mock.EXPECT().
+					ListUserTodos(gomock.Any(), "user1").
+					Return([]*domain.Todo{{ID: "todo1", UserID: "user1"}}, nil)
+				mock.EXPECT().ListArchivedTodos(gomock.Any(), "user1").Return(nil, nil)
+				mock.EXPECT().DeleteTodo(gomock.Any(), "todo1").Return(nil)
+				mock.EXPECT().
+					ListDeletedTodos(gomock.Any(), "user1").
+					Return([]*domain.Todo{{ID: "todo1", UserID: "user1", DeletedAt: &now}}, nil)
+				mock.EXPECT().PurgeTodo(gomock.Any(), "todo1").Return(nil)
+				mock.EXPECT().
+					PurgeTodosDeletedBefore(gomock.Any(), now.Add(-30*24*time.Hour)).
+					Return(2, nil)
+			},
+			expectPurgedUsers: 1,
+			expectPurgedTodos: 2,
+		},
+		"Success: No retention keeps everything": {
+			retention:     0,
+			setupUserFunc: func(mock *mocks.MockUserStore) {},
+			setupTodoFunc: func(mock *mocks.MockTodoStore) {},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			mockTodoStore := mocks.NewMockTodoStore(ctrl)
+			tt.setupUserFunc(mockUserStore)
+			tt.setupTodoFunc(mockTodoStore)
+
+			service := NewTrashService(mockUserStore, mockTodoStore, newTxRunner(ctrl, mockUserStore, mockTodoStore), tt.retention)
+			service.now = func() time.Time { return now }
+
+			users, todos, err := service.PurgeExpired(auth.WithSystem(context.Background()))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectPurgedUsers, users)
+			assert.Equal(t, tt.expectPurgedTodos, todos)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// WatchService is a service that streams changes to a user's Todos as they happen
+type WatchService struct {
+	watcher   smallinterface.ChangeWatcher // Using the small change notification interface
+	userStore smallinterface.UserStore     // Needed to check that the user exists
+}
+
+// NewWatchService creates a new WatchService
+func NewWatchService(watcher smallinterface.ChangeWatcher, userStore smallinterface.UserStore) *WatchService {
+	return &WatchService{
+		watcher:   watcher,
+		userStore: userStore,
+	}
+}
+
+// WatchUserTodos streams changes to a user's Todos until ctx is done
+// A non-zero afterRevision resumes a previous watch after the last change it received
+func (s *WatchService) WatchUserTodos(ctx context.Context, userID string, afterRevision int64) (<-chan domain.Change, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.userStore.GetUser(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return s.watcher.Watch(ctx, domain.ChangeFilter{
+		Entity:        domain.ChangeEntityTodo,
+		UserID:        userID,
+		AfterRevision: afterRevision,
+	})
+}
@@ -0,0 +1,70 @@
+package smallinterface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/auth"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface/mocks"
+)
+
+func TestWatchService_WatchUserTodos(t *testing.T) {
+	tests := map[string]struct {
+		userID           string
+		afterRevision    int64
+		setupUserFunc    func(mock *mocks.MockUserStore)
+		setupWatcherFunc func(mock *mocks.MockChangeWatcher)
+		expectErr        error
+	}{
+		"Success: Watch a user's todos from a revision": {
+			userID:        "user1",
+			afterRevision: 42,
+			setupUserFunc: func(mock *mocks.MockUserStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "user1").Return(&domain.User{ID: "user1"}, nil)
+			},
+			setupWatcherFunc: func(mock *mocks.MockChangeWatcher) {
+				mock.EXPECT().
+					Watch(gomock.Any(), domain.ChangeFilter{Entity: domain.ChangeEntityTodo, UserID: "user1", AfterRevision: 42}).
+					Return(make(chan domain.Change), nil)
+			},
+			expectErr: nil,
+		},
+		"Error: User not found": {
+			userID: "nonexistent",
+			setupUserFunc: func(mock *mocks.MockUserStore) {
+				mock.EXPECT().GetUser(gomock.Any(), "nonexistent").Return(nil, errors.New("user not found: nonexistent"))
+			},
+			setupWatcherFunc: func(mock *mocks.MockChangeWatcher) {},
+			expectErr:        domain.ErrUserNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockWatcher := mocks.NewMockChangeWatcher(ctrl)
+			mockUserStore := mocks.NewMockUserStore(ctrl)
+			tt.setupUserFunc(mockUserStore)
+			tt.setupWatcherFunc(mockWatcher)
+
+			service := NewWatchService(mockWatcher, mockUserStore)
+
+			changes, err := service.WatchUserTodos(auth.WithSystem(context.Background()), tt.userID, tt.afterRevision)
+
+			if tt.expectErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_attachmentstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface AttachmentStore
+
+// AttachmentStore is a small interface that defines only attachment operations
+// This is an example of a high cohesion approach
+type AttachmentStore interface {
+	GetAttachment(ctx context.Context, id string) (*domain.Attachment, error)
+	ListTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error)
+	ListBlobAttachments(ctx context.Context, digest string) ([]*domain.Attachment, error)
+	CreateAttachment(ctx context.Context, attachment *domain.Attachment) error
+	DeleteAttachment(ctx context.Context, id string) error
+}
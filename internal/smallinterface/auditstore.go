@@ -0,0 +1,17 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_auditstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface AuditStore
+
+// AuditStore is a small interface that defines only audit log operations
+// This is an example of a high cohesion approach
+type AuditStore interface {
+	AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error
+	ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error)
+	ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error)
+}
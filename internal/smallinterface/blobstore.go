@@ -0,0 +1,21 @@
+package smallinterface
+
+import (
+	"context"
+	"io"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_blobstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface BlobStore
+
+// BlobStore is a small interface that defines only operations on content-addressed blobs
+// Blobs are kept outside the other stores, so even the big interface approach depends on it separately
+// Like the other stores it acts on the tenant of ctx, so one tenant never sees nor deletes the blobs of another
+type BlobStore interface {
+	// PutBlob stores content and returns its blob; storing the same content again returns the same digest
+	PutBlob(ctx context.Context, content io.Reader) (*domain.Blob, error)
+	OpenBlob(ctx context.Context, digest string) (io.ReadCloser, error)
+	ListBlobs(ctx context.Context) ([]*domain.Blob, error)
+	DeleteBlob(ctx context.Context, digest string) error
+}
@@ -0,0 +1,15 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_changewatcher.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface ChangeWatcher
+
+// ChangeWatcher is a small interface that defines only change notification operations
+// This is an example of a high cohesion approach
+type ChangeWatcher interface {
+	Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error)
+}
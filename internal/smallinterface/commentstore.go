@@ -0,0 +1,19 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_commentstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface CommentStore
+
+// CommentStore is a small interface that defines only comment operations
+// This is an example of a high cohesion approach
+type CommentStore interface {
+	GetComment(ctx context.Context, id string) (*domain.Comment, error)
+	ListTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error)
+	CreateComment(ctx context.Context, comment *domain.Comment) error
+	UpdateComment(ctx context.Context, comment *domain.Comment) error
+	DeleteComment(ctx context.Context, id string) error
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: AttachmentStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_attachmentstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface AttachmentStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttachmentStore is a mock of AttachmentStore interface.
+type MockAttachmentStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAttachmentStoreMockRecorder is the mock recorder for MockAttachmentStore.
+type MockAttachmentStoreMockRecorder struct {
+	mock *MockAttachmentStore
+}
+
+// NewMockAttachmentStore creates a new mock instance.
+func NewMockAttachmentStore(ctrl *gomock.Controller) *MockAttachmentStore {
+	mock := &MockAttachmentStore{ctrl: ctrl}
+	mock.recorder = &MockAttachmentStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentStore) EXPECT() *MockAttachmentStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateAttachment mocks base method.
+func (m *MockAttachmentStore) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachment", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAttachment indicates an expected call of CreateAttachment.
+func (mr *MockAttachmentStoreMockRecorder) CreateAttachment(ctx, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachment", reflect.TypeOf((*MockAttachmentStore)(nil).CreateAttachment), ctx, attachment)
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockAttachmentStore) DeleteAttachment(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockAttachmentStoreMockRecorder) DeleteAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockAttachmentStore)(nil).DeleteAttachment), ctx, id)
+}
+
+// GetAttachment mocks base method.
+func (m *MockAttachmentStore) GetAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, id)
+	ret0, _ := ret[0].(*domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockAttachmentStoreMockRecorder) GetAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockAttachmentStore)(nil).GetAttachment), ctx, id)
+}
+
+// ListBlobAttachments mocks base method.
+func (m *MockAttachmentStore) ListBlobAttachments(ctx context.Context, digest string) ([]*domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlobAttachments", ctx, digest)
+	ret0, _ := ret[0].([]*domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlobAttachments indicates an expected call of ListBlobAttachments.
+func (mr *MockAttachmentStoreMockRecorder) ListBlobAttachments(ctx, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlobAttachments", reflect.TypeOf((*MockAttachmentStore)(nil).ListBlobAttachments), ctx, digest)
+}
+
+// ListTodoAttachments mocks base method.
+func (m *MockAttachmentStore) ListTodoAttachments(ctx context.Context, todoID string) ([]*domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoAttachments", ctx, todoID)
+	ret0, _ := ret[0].([]*domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoAttachments indicates an expected call of ListTodoAttachments.
+func (mr *MockAttachmentStoreMockRecorder) ListTodoAttachments(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoAttachments", reflect.TypeOf((*MockAttachmentStore)(nil).ListTodoAttachments), ctx, todoID)
+}
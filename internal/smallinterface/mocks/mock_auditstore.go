@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: AuditStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_auditstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface AuditStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// AppendAuditEntry mocks base method.
+func (m *MockAuditStore) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAuditEntry indicates an expected call of AppendAuditEntry.
+func (mr *MockAuditStoreMockRecorder) AppendAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAuditEntry", reflect.TypeOf((*MockAuditStore)(nil).AppendAuditEntry), ctx, entry)
+}
+
+// ListActorAuditEntries mocks base method.
+func (m *MockAuditStore) ListActorAuditEntries(ctx context.Context, actor string) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActorAuditEntries", ctx, actor)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActorAuditEntries indicates an expected call of ListActorAuditEntries.
+func (mr *MockAuditStoreMockRecorder) ListActorAuditEntries(ctx, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActorAuditEntries", reflect.TypeOf((*MockAuditStore)(nil).ListActorAuditEntries), ctx, actor)
+}
+
+// ListEntityAuditEntries mocks base method.
+func (m *MockAuditStore) ListEntityAuditEntries(ctx context.Context, entityType domain.AuditEntityType, entityID string) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntityAuditEntries", ctx, entityType, entityID)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntityAuditEntries indicates an expected call of ListEntityAuditEntries.
+func (mr *MockAuditStoreMockRecorder) ListEntityAuditEntries(ctx, entityType, entityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntityAuditEntries", reflect.TypeOf((*MockAuditStore)(nil).ListEntityAuditEntries), ctx, entityType, entityID)
+}
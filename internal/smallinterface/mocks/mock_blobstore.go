@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: BlobStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_blobstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface BlobStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlobStore is a mock of BlobStore interface.
+type MockBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockBlobStoreMockRecorder is the mock recorder for MockBlobStore.
+type MockBlobStoreMockRecorder struct {
+	mock *MockBlobStore
+}
+
+// NewMockBlobStore creates a new mock instance.
+func NewMockBlobStore(ctrl *gomock.Controller) *MockBlobStore {
+	mock := &MockBlobStore{ctrl: ctrl}
+	mock.recorder = &MockBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStore) EXPECT() *MockBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteBlob mocks base method.
+func (m *MockBlobStore) DeleteBlob(ctx context.Context, digest string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlob", ctx, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlob indicates an expected call of DeleteBlob.
+func (mr *MockBlobStoreMockRecorder) DeleteBlob(ctx, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlob", reflect.TypeOf((*MockBlobStore)(nil).DeleteBlob), ctx, digest)
+}
+
+// ListBlobs mocks base method.
+func (m *MockBlobStore) ListBlobs(ctx context.Context) ([]*domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlobs", ctx)
+	ret0, _ := ret[0].([]*domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlobs indicates an expected call of ListBlobs.
+func (mr *MockBlobStoreMockRecorder) ListBlobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlobs", reflect.TypeOf((*MockBlobStore)(nil).ListBlobs), ctx)
+}
+
+// OpenBlob mocks base method.
+func (m *MockBlobStore) OpenBlob(ctx context.Context, digest string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenBlob", ctx, digest)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenBlob indicates an expected call of OpenBlob.
+func (mr *MockBlobStoreMockRecorder) OpenBlob(ctx, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlob", reflect.TypeOf((*MockBlobStore)(nil).OpenBlob), ctx, digest)
+}
+
+// PutBlob mocks base method.
+func (m *MockBlobStore) PutBlob(ctx context.Context, content io.Reader) (*domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutBlob", ctx, content)
+	ret0, _ := ret[0].(*domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutBlob indicates an expected call of PutBlob.
+func (mr *MockBlobStoreMockRecorder) PutBlob(ctx, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutBlob", reflect.TypeOf((*MockBlobStore)(nil).PutBlob), ctx, content)
+}
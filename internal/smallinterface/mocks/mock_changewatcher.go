@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: ChangeWatcher)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_changewatcher.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface ChangeWatcher
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChangeWatcher is a mock of ChangeWatcher interface.
+type MockChangeWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeWatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockChangeWatcherMockRecorder is the mock recorder for MockChangeWatcher.
+type MockChangeWatcherMockRecorder struct {
+	mock *MockChangeWatcher
+}
+
+// NewMockChangeWatcher creates a new mock instance.
+func NewMockChangeWatcher(ctrl *gomock.Controller) *MockChangeWatcher {
+	mock := &MockChangeWatcher{ctrl: ctrl}
+	mock.recorder = &MockChangeWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeWatcher) EXPECT() *MockChangeWatcherMockRecorder {
+	return m.recorder
+}
+
+// Watch mocks base method.
+func (m *MockChangeWatcher) Watch(ctx context.Context, filter domain.ChangeFilter) (<-chan domain.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, filter)
+	ret0, _ := ret[0].(<-chan domain.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockChangeWatcherMockRecorder) Watch(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockChangeWatcher)(nil).Watch), ctx, filter)
+}
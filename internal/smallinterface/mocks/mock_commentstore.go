@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: CommentStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_commentstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface CommentStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentStore is a mock of CommentStore interface.
+type MockCommentStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockCommentStoreMockRecorder is the mock recorder for MockCommentStore.
+type MockCommentStoreMockRecorder struct {
+	mock *MockCommentStore
+}
+
+// NewMockCommentStore creates a new mock instance.
+func NewMockCommentStore(ctrl *gomock.Controller) *MockCommentStore {
+	mock := &MockCommentStore{ctrl: ctrl}
+	mock.recorder = &MockCommentStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentStore) EXPECT() *MockCommentStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockCommentStore) CreateComment(ctx context.Context, comment *domain.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCommentStoreMockRecorder) CreateComment(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockCommentStore)(nil).CreateComment), ctx, comment)
+}
+
+// DeleteComment mocks base method.
+func (m *MockCommentStore) DeleteComment(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentStoreMockRecorder) DeleteComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockCommentStore)(nil).DeleteComment), ctx, id)
+}
+
+// GetComment mocks base method.
+func (m *MockCommentStore) GetComment(ctx context.Context, id string) (*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", ctx, id)
+	ret0, _ := ret[0].(*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockCommentStoreMockRecorder) GetComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockCommentStore)(nil).GetComment), ctx, id)
+}
+
+// ListTodoComments mocks base method.
+func (m *MockCommentStore) ListTodoComments(ctx context.Context, todoID string) ([]*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoComments", ctx, todoID)
+	ret0, _ := ret[0].([]*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoComments indicates an expected call of ListTodoComments.
+func (mr *MockCommentStoreMockRecorder) ListTodoComments(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoComments", reflect.TypeOf((*MockCommentStore)(nil).ListTodoComments), ctx, todoID)
+}
+
+// UpdateComment mocks base method.
+func (m *MockCommentStore) UpdateComment(ctx context.Context, comment *domain.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockCommentStoreMockRecorder) UpdateComment(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockCommentStore)(nil).UpdateComment), ctx, comment)
+}
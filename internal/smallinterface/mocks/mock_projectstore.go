@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: ProjectStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_projectstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface ProjectStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProjectStore is a mock of ProjectStore interface.
+type MockProjectStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockProjectStoreMockRecorder is the mock recorder for MockProjectStore.
+type MockProjectStoreMockRecorder struct {
+	mock *MockProjectStore
+}
+
+// NewMockProjectStore creates a new mock instance.
+func NewMockProjectStore(ctrl *gomock.Controller) *MockProjectStore {
+	mock := &MockProjectStore{ctrl: ctrl}
+	mock.recorder = &MockProjectStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectStore) EXPECT() *MockProjectStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateProject mocks base method.
+func (m *MockProjectStore) CreateProject(ctx context.Context, project *domain.Project) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", ctx, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockProjectStoreMockRecorder) CreateProject(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockProjectStore)(nil).CreateProject), ctx, project)
+}
+
+// DeleteProject mocks base method.
+func (m *MockProjectStore) DeleteProject(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockProjectStoreMockRecorder) DeleteProject(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockProjectStore)(nil).DeleteProject), ctx, id)
+}
+
+// GetProject mocks base method.
+func (m *MockProjectStore) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", ctx, id)
+	ret0, _ := ret[0].(*domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockProjectStoreMockRecorder) GetProject(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockProjectStore)(nil).GetProject), ctx, id)
+}
+
+// ListUserProjects mocks base method.
+func (m *MockProjectStore) ListUserProjects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserProjects", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserProjects indicates an expected call of ListUserProjects.
+func (mr *MockProjectStoreMockRecorder) ListUserProjects(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserProjects", reflect.TypeOf((*MockProjectStore)(nil).ListUserProjects), ctx, userID)
+}
+
+// UpdateProject mocks base method.
+func (m *MockProjectStore) UpdateProject(ctx context.Context, project *domain.Project) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", ctx, project)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockProjectStoreMockRecorder) UpdateProject(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockProjectStore)(nil).UpdateProject), ctx, project)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: SharingStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_sharingstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface SharingStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSharingStore is a mock of SharingStore interface.
+type MockSharingStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSharingStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockSharingStoreMockRecorder is the mock recorder for MockSharingStore.
+type MockSharingStoreMockRecorder struct {
+	mock *MockSharingStore
+}
+
+// NewMockSharingStore creates a new mock instance.
+func NewMockSharingStore(ctrl *gomock.Controller) *MockSharingStore {
+	mock := &MockSharingStore{ctrl: ctrl}
+	mock.recorder = &MockSharingStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSharingStore) EXPECT() *MockSharingStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteShare mocks base method.
+func (m *MockSharingStore) DeleteShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShare", ctx, resourceType, resourceID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockSharingStoreMockRecorder) DeleteShare(ctx, resourceType, resourceID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockSharingStore)(nil).DeleteShare), ctx, resourceType, resourceID, userID)
+}
+
+// GetShare mocks base method.
+func (m *MockSharingStore) GetShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID, userID string) (*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShare", ctx, resourceType, resourceID, userID)
+	ret0, _ := ret[0].(*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShare indicates an expected call of GetShare.
+func (mr *MockSharingStoreMockRecorder) GetShare(ctx, resourceType, resourceID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShare", reflect.TypeOf((*MockSharingStore)(nil).GetShare), ctx, resourceType, resourceID, userID)
+}
+
+// ListResourceShares mocks base method.
+func (m *MockSharingStore) ListResourceShares(ctx context.Context, resourceType domain.ShareResourceType, resourceID string) ([]*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceShares", ctx, resourceType, resourceID)
+	ret0, _ := ret[0].([]*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceShares indicates an expected call of ListResourceShares.
+func (mr *MockSharingStoreMockRecorder) ListResourceShares(ctx, resourceType, resourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceShares", reflect.TypeOf((*MockSharingStore)(nil).ListResourceShares), ctx, resourceType, resourceID)
+}
+
+// ListUserShares mocks base method.
+func (m *MockSharingStore) ListUserShares(ctx context.Context, userID string) ([]*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserShares", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserShares indicates an expected call of ListUserShares.
+func (mr *MockSharingStoreMockRecorder) ListUserShares(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserShares", reflect.TypeOf((*MockSharingStore)(nil).ListUserShares), ctx, userID)
+}
+
+// PutShare mocks base method.
+func (m *MockSharingStore) PutShare(ctx context.Context, share *domain.Share) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutShare", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutShare indicates an expected call of PutShare.
+func (mr *MockSharingStoreMockRecorder) PutShare(ctx, share any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutShare", reflect.TypeOf((*MockSharingStore)(nil).PutShare), ctx, share)
+}
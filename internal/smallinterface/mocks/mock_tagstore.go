@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: TagStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_tagstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface TagStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagStore is a mock of TagStore interface.
+type MockTagStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTagStoreMockRecorder is the mock recorder for MockTagStore.
+type MockTagStoreMockRecorder struct {
+	mock *MockTagStore
+}
+
+// NewMockTagStore creates a new mock instance.
+func NewMockTagStore(ctrl *gomock.Controller) *MockTagStore {
+	mock := &MockTagStore{ctrl: ctrl}
+	mock.recorder = &MockTagStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagStore) EXPECT() *MockTagStoreMockRecorder {
+	return m.recorder
+}
+
+// AddTodoTag mocks base method.
+func (m *MockTagStore) AddTodoTag(ctx context.Context, todoID, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoTag", ctx, todoID, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTodoTag indicates an expected call of AddTodoTag.
+func (mr *MockTagStoreMockRecorder) AddTodoTag(ctx, todoID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoTag", reflect.TypeOf((*MockTagStore)(nil).AddTodoTag), ctx, todoID, tag)
+}
+
+// ListTodoTags mocks base method.
+func (m *MockTagStore) ListTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoTags", ctx, todoID)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoTags indicates an expected call of ListTodoTags.
+func (mr *MockTagStoreMockRecorder) ListTodoTags(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoTags", reflect.TypeOf((*MockTagStore)(nil).ListTodoTags), ctx, todoID)
+}
+
+// ListTodosWithAllTags mocks base method.
+func (m *MockTagStore) ListTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosWithAllTags", ctx, userID, tags)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosWithAllTags indicates an expected call of ListTodosWithAllTags.
+func (mr *MockTagStoreMockRecorder) ListTodosWithAllTags(ctx, userID, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosWithAllTags", reflect.TypeOf((*MockTagStore)(nil).ListTodosWithAllTags), ctx, userID, tags)
+}
+
+// ListTodosWithAnyTag mocks base method.
+func (m *MockTagStore) ListTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosWithAnyTag", ctx, userID, tags)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosWithAnyTag indicates an expected call of ListTodosWithAnyTag.
+func (mr *MockTagStoreMockRecorder) ListTodosWithAnyTag(ctx, userID, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosWithAnyTag", reflect.TypeOf((*MockTagStore)(nil).ListTodosWithAnyTag), ctx, userID, tags)
+}
+
+// ListUserTags mocks base method.
+func (m *MockTagStore) ListUserTags(ctx context.Context, userID string) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTags", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTags indicates an expected call of ListUserTags.
+func (mr *MockTagStoreMockRecorder) ListUserTags(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTags", reflect.TypeOf((*MockTagStore)(nil).ListUserTags), ctx, userID)
+}
+
+// RemoveTodoTag mocks base method.
+func (m *MockTagStore) RemoveTodoTag(ctx context.Context, todoID, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoTag", ctx, todoID, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTodoTag indicates an expected call of RemoveTodoTag.
+func (mr *MockTagStoreMockRecorder) RemoveTodoTag(ctx, todoID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoTag", reflect.TypeOf((*MockTagStore)(nil).RemoveTodoTag), ctx, todoID, tag)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -55,6 +56,20 @@ func (mr *MockTodoStoreMockRecorder) CreateTodo(ctx, todo any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodo", reflect.TypeOf((*MockTodoStore)(nil).CreateTodo), ctx, todo)
 }
 
+// CreateTodos mocks base method.
+func (m *MockTodoStore) CreateTodos(ctx context.Context, todos []*domain.Todo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTodos", ctx, todos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTodos indicates an expected call of CreateTodos.
+func (mr *MockTodoStoreMockRecorder) CreateTodos(ctx, todos any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodos", reflect.TypeOf((*MockTodoStore)(nil).CreateTodos), ctx, todos)
+}
+
 // DeleteTodo mocks base method.
 func (m *MockTodoStore) DeleteTodo(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -69,6 +84,35 @@ func (mr *MockTodoStoreMockRecorder) DeleteTodo(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockTodoStore)(nil).DeleteTodo), ctx, id)
 }
 
+// DeleteTodos mocks base method.
+func (m *MockTodoStore) DeleteTodos(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTodos", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTodos indicates an expected call of DeleteTodos.
+func (mr *MockTodoStoreMockRecorder) DeleteTodos(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodos", reflect.TypeOf((*MockTodoStore)(nil).DeleteTodos), ctx, ids)
+}
+
+// GetDeletedTodo mocks base method.
+func (m *MockTodoStore) GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedTodo", ctx, id)
+	ret0, _ := ret[0].(*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedTodo indicates an expected call of GetDeletedTodo.
+func (mr *MockTodoStoreMockRecorder) GetDeletedTodo(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedTodo", reflect.TypeOf((*MockTodoStore)(nil).GetDeletedTodo), ctx, id)
+}
+
 // GetTodo mocks base method.
 func (m *MockTodoStore) GetTodo(ctx context.Context, id string) (*domain.Todo, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +128,81 @@ func (mr *MockTodoStoreMockRecorder) GetTodo(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodo", reflect.TypeOf((*MockTodoStore)(nil).GetTodo), ctx, id)
 }
 
+// ListArchivedTodos mocks base method.
+func (m *MockTodoStore) ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArchivedTodos", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchivedTodos indicates an expected call of ListArchivedTodos.
+func (mr *MockTodoStoreMockRecorder) ListArchivedTodos(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedTodos", reflect.TypeOf((*MockTodoStore)(nil).ListArchivedTodos), ctx, userID)
+}
+
+// ListAssignedTodos mocks base method.
+func (m *MockTodoStore) ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssignedTodos", ctx, assigneeID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssignedTodos indicates an expected call of ListAssignedTodos.
+func (mr *MockTodoStoreMockRecorder) ListAssignedTodos(ctx, assigneeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssignedTodos", reflect.TypeOf((*MockTodoStore)(nil).ListAssignedTodos), ctx, assigneeID)
+}
+
+// ListDeletedTodos mocks base method.
+func (m *MockTodoStore) ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedTodos", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedTodos indicates an expected call of ListDeletedTodos.
+func (mr *MockTodoStoreMockRecorder) ListDeletedTodos(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedTodos", reflect.TypeOf((*MockTodoStore)(nil).ListDeletedTodos), ctx, userID)
+}
+
+// ListOverdueTodos mocks base method.
+func (m *MockTodoStore) ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueTodos", ctx, userID, now)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueTodos indicates an expected call of ListOverdueTodos.
+func (mr *MockTodoStoreMockRecorder) ListOverdueTodos(ctx, userID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueTodos", reflect.TypeOf((*MockTodoStore)(nil).ListOverdueTodos), ctx, userID, now)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockTodoStore) ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", ctx, parentID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockTodoStoreMockRecorder) ListSubtasks(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockTodoStore)(nil).ListSubtasks), ctx, parentID)
+}
+
 // ListTodos mocks base method.
 func (m *MockTodoStore) ListTodos(ctx context.Context) ([]*domain.Todo, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +218,21 @@ func (mr *MockTodoStoreMockRecorder) ListTodos(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockTodoStore)(nil).ListTodos), ctx)
 }
 
+// ListTodosDueBetween mocks base method.
+func (m *MockTodoStore) ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosDueBetween", ctx, userID, from, to)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosDueBetween indicates an expected call of ListTodosDueBetween.
+func (mr *MockTodoStoreMockRecorder) ListTodosDueBetween(ctx, userID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosDueBetween", reflect.TypeOf((*MockTodoStore)(nil).ListTodosDueBetween), ctx, userID, from, to)
+}
+
 // ListUserTodos mocks base method.
 func (m *MockTodoStore) ListUserTodos(ctx context.Context, userID string) ([]*domain.Todo, error) {
 	m.ctrl.T.Helper()
@@ -114,6 +248,21 @@ func (mr *MockTodoStoreMockRecorder) ListUserTodos(ctx, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTodos", reflect.TypeOf((*MockTodoStore)(nil).ListUserTodos), ctx, userID)
 }
 
+// ListUserTodosByPriority mocks base method.
+func (m *MockTodoStore) ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTodosByPriority", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTodosByPriority indicates an expected call of ListUserTodosByPriority.
+func (mr *MockTodoStoreMockRecorder) ListUserTodosByPriority(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTodosByPriority", reflect.TypeOf((*MockTodoStore)(nil).ListUserTodosByPriority), ctx, userID)
+}
+
 // MarkTodoComplete mocks base method.
 func (m *MockTodoStore) MarkTodoComplete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -128,6 +277,91 @@ func (mr *MockTodoStoreMockRecorder) MarkTodoComplete(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTodoComplete", reflect.TypeOf((*MockTodoStore)(nil).MarkTodoComplete), ctx, id)
 }
 
+// MarkTodosComplete mocks base method.
+func (m *MockTodoStore) MarkTodosComplete(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTodosComplete", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkTodosComplete indicates an expected call of MarkTodosComplete.
+func (mr *MockTodoStoreMockRecorder) MarkTodosComplete(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTodosComplete", reflect.TypeOf((*MockTodoStore)(nil).MarkTodosComplete), ctx, ids)
+}
+
+// PurgeTodo mocks base method.
+func (m *MockTodoStore) PurgeTodo(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTodo", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTodo indicates an expected call of PurgeTodo.
+func (mr *MockTodoStoreMockRecorder) PurgeTodo(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTodo", reflect.TypeOf((*MockTodoStore)(nil).PurgeTodo), ctx, id)
+}
+
+// PurgeTodosDeletedBefore mocks base method.
+func (m *MockTodoStore) PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTodosDeletedBefore", ctx, cutoff)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTodosDeletedBefore indicates an expected call of PurgeTodosDeletedBefore.
+func (mr *MockTodoStoreMockRecorder) PurgeTodosDeletedBefore(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTodosDeletedBefore", reflect.TypeOf((*MockTodoStore)(nil).PurgeTodosDeletedBefore), ctx, cutoff)
+}
+
+// RestoreTodo mocks base method.
+func (m *MockTodoStore) RestoreTodo(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTodo", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTodo indicates an expected call of RestoreTodo.
+func (mr *MockTodoStoreMockRecorder) RestoreTodo(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTodo", reflect.TypeOf((*MockTodoStore)(nil).RestoreTodo), ctx, id)
+}
+
+// SetTodoDueDate mocks base method.
+func (m *MockTodoStore) SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTodoDueDate", ctx, id, dueAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTodoDueDate indicates an expected call of SetTodoDueDate.
+func (mr *MockTodoStoreMockRecorder) SetTodoDueDate(ctx, id, dueAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTodoDueDate", reflect.TypeOf((*MockTodoStore)(nil).SetTodoDueDate), ctx, id, dueAt)
+}
+
+// SetTodoPriority mocks base method.
+func (m *MockTodoStore) SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTodoPriority", ctx, id, priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTodoPriority indicates an expected call of SetTodoPriority.
+func (mr *MockTodoStoreMockRecorder) SetTodoPriority(ctx, id, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTodoPriority", reflect.TypeOf((*MockTodoStore)(nil).SetTodoPriority), ctx, id, priority)
+}
+
 // UpdateTodo mocks base method.
 func (m *MockTodoStore) UpdateTodo(ctx context.Context, todo *domain.Todo) error {
 	m.ctrl.T.Helper()
@@ -141,3 +375,17 @@ func (mr *MockTodoStoreMockRecorder) UpdateTodo(ctx, todo any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockTodoStore)(nil).UpdateTodo), ctx, todo)
 }
+
+// UpdateTodos mocks base method.
+func (m *MockTodoStore) UpdateTodos(ctx context.Context, todos []*domain.Todo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodos", ctx, todos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTodos indicates an expected call of UpdateTodos.
+func (mr *MockTodoStoreMockRecorder) UpdateTodos(ctx, todos any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodos", reflect.TypeOf((*MockTodoStore)(nil).UpdateTodos), ctx, todos)
+}
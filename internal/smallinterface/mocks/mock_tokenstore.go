@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: TokenStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_tokenstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface TokenStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenStore is a mock of TokenStore interface.
+type MockTokenStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenStoreMockRecorder is the mock recorder for MockTokenStore.
+type MockTokenStoreMockRecorder struct {
+	mock *MockTokenStore
+}
+
+// NewMockTokenStore creates a new mock instance.
+func NewMockTokenStore(ctrl *gomock.Controller) *MockTokenStore {
+	mock := &MockTokenStore{ctrl: ctrl}
+	mock.recorder = &MockTokenStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenStore) EXPECT() *MockTokenStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *MockTokenStore) CreateToken(ctx context.Context, token *domain.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockTokenStoreMockRecorder) CreateToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockTokenStore)(nil).CreateToken), ctx, token)
+}
+
+// GetToken mocks base method.
+func (m *MockTokenStore) GetToken(ctx context.Context, id string) (*domain.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToken", ctx, id)
+	ret0, _ := ret[0].(*domain.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToken indicates an expected call of GetToken.
+func (mr *MockTokenStoreMockRecorder) GetToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToken", reflect.TypeOf((*MockTokenStore)(nil).GetToken), ctx, id)
+}
+
+// ListUserTokens mocks base method.
+func (m *MockTokenStore) ListUserTokens(ctx context.Context, userID string) ([]*domain.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserTokens", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserTokens indicates an expected call of ListUserTokens.
+func (mr *MockTokenStoreMockRecorder) ListUserTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserTokens", reflect.TypeOf((*MockTokenStore)(nil).ListUserTokens), ctx, userID)
+}
+
+// RevokeToken mocks base method.
+func (m *MockTokenStore) RevokeToken(ctx context.Context, id string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, id, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockTokenStoreMockRecorder) RevokeToken(ctx, id, revokedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockTokenStore)(nil).RevokeToken), ctx, id, revokedAt)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface (interfaces: TxRunner)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_txrunner.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface TxRunner
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	smallinterface "github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTxRunner is a mock of TxRunner interface.
+type MockTxRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxRunnerMockRecorder
+	isgomock struct{}
+}
+
+// MockTxRunnerMockRecorder is the mock recorder for MockTxRunner.
+type MockTxRunnerMockRecorder struct {
+	mock *MockTxRunner
+}
+
+// NewMockTxRunner creates a new mock instance.
+func NewMockTxRunner(ctrl *gomock.Controller) *MockTxRunner {
+	mock := &MockTxRunner{ctrl: ctrl}
+	mock.recorder = &MockTxRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTxRunner) EXPECT() *MockTxRunnerMockRecorder {
+	return m.recorder
+}
+
+// RunInTx mocks base method.
+func (m *MockTxRunner) RunInTx(ctx context.Context, fn func(context.Context, smallinterface.UserStore, smallinterface.TodoStore) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunInTx indicates an expected call of RunInTx.
+func (mr *MockTxRunnerMockRecorder) RunInTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInTx", reflect.TypeOf((*MockTxRunner)(nil).RunInTx), ctx, fn)
+}
@@ -55,6 +55,20 @@ func (mr *MockUserStoreMockRecorder) CreateUser(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserStore)(nil).CreateUser), ctx, user)
 }
 
+// CreateUsers mocks base method.
+func (m *MockUserStore) CreateUsers(ctx context.Context, users []*domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUsers", ctx, users)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUsers indicates an expected call of CreateUsers.
+func (mr *MockUserStoreMockRecorder) CreateUsers(ctx, users any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUsers", reflect.TypeOf((*MockUserStore)(nil).CreateUsers), ctx, users)
+}
+
 // DeleteUser mocks base method.
 func (m *MockUserStore) DeleteUser(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -69,6 +83,35 @@ func (mr *MockUserStoreMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserStore)(nil).DeleteUser), ctx, id)
 }
 
+// DeleteUsers mocks base method.
+func (m *MockUserStore) DeleteUsers(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUsers", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUsers indicates an expected call of DeleteUsers.
+func (mr *MockUserStoreMockRecorder) DeleteUsers(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUsers", reflect.TypeOf((*MockUserStore)(nil).DeleteUsers), ctx, ids)
+}
+
+// GetDeletedUser mocks base method.
+func (m *MockUserStore) GetDeletedUser(ctx context.Context, id string) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedUser", ctx, id)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedUser indicates an expected call of GetDeletedUser.
+func (mr *MockUserStoreMockRecorder) GetDeletedUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedUser", reflect.TypeOf((*MockUserStore)(nil).GetDeletedUser), ctx, id)
+}
+
 // GetUser mocks base method.
 func (m *MockUserStore) GetUser(ctx context.Context, id string) (*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +127,21 @@ func (mr *MockUserStoreMockRecorder) GetUser(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserStore)(nil).GetUser), ctx, id)
 }
 
+// ListDeletedUsers mocks base method.
+func (m *MockUserStore) ListDeletedUsers(ctx context.Context) ([]*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedUsers", ctx)
+	ret0, _ := ret[0].([]*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedUsers indicates an expected call of ListDeletedUsers.
+func (mr *MockUserStoreMockRecorder) ListDeletedUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedUsers", reflect.TypeOf((*MockUserStore)(nil).ListDeletedUsers), ctx)
+}
+
 // ListUsers mocks base method.
 func (m *MockUserStore) ListUsers(ctx context.Context) ([]*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +157,34 @@ func (mr *MockUserStoreMockRecorder) ListUsers(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserStore)(nil).ListUsers), ctx)
 }
 
+// PurgeUser mocks base method.
+func (m *MockUserStore) PurgeUser(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeUser indicates an expected call of PurgeUser.
+func (mr *MockUserStoreMockRecorder) PurgeUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUser", reflect.TypeOf((*MockUserStore)(nil).PurgeUser), ctx, id)
+}
+
+// RestoreUser mocks base method.
+func (m *MockUserStore) RestoreUser(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockUserStoreMockRecorder) RestoreUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockUserStore)(nil).RestoreUser), ctx, id)
+}
+
 // UpdateUser mocks base method.
 func (m *MockUserStore) UpdateUser(ctx context.Context, user *domain.User) error {
 	m.ctrl.T.Helper()
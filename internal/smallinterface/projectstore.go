@@ -0,0 +1,19 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_projectstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface ProjectStore
+
+// ProjectStore is a small interface that defines only project-related operations
+// This is an example of a high cohesion approach
+type ProjectStore interface {
+	GetProject(ctx context.Context, id string) (*domain.Project, error)
+	ListUserProjects(ctx context.Context, userID string) ([]*domain.Project, error)
+	CreateProject(ctx context.Context, project *domain.Project) error
+	UpdateProject(ctx context.Context, project *domain.Project) error
+	DeleteProject(ctx context.Context, id string) error
+}
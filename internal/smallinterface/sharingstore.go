@@ -0,0 +1,19 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_sharingstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface SharingStore
+
+// SharingStore is a small interface that defines only sharing operations
+// This is an example of a high cohesion approach
+type SharingStore interface {
+	GetShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) (*domain.Share, error)
+	ListResourceShares(ctx context.Context, resourceType domain.ShareResourceType, resourceID string) ([]*domain.Share, error)
+	ListUserShares(ctx context.Context, userID string) ([]*domain.Share, error)
+	PutShare(ctx context.Context, share *domain.Share) error
+	DeleteShare(ctx context.Context, resourceType domain.ShareResourceType, resourceID string, userID string) error
+}
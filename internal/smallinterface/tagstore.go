@@ -0,0 +1,21 @@
+package smallinterface
+
+import (
+	"context"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_tagstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface TagStore
+
+// TagStore is a small interface that defines only tag-related operations
+// Tagging is kept out of TodoStore so that services which never touch tags
+// do not depend on these methods
+type TagStore interface {
+	AddTodoTag(ctx context.Context, todoID string, tag string) error
+	RemoveTodoTag(ctx context.Context, todoID string, tag string) error
+	ListTodoTags(ctx context.Context, todoID string) ([]*domain.Tag, error)
+	ListUserTags(ctx context.Context, userID string) ([]*domain.Tag, error)
+	ListTodosWithAllTags(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error)
+	ListTodosWithAnyTag(ctx context.Context, userID string, tags []string) ([]*domain.Todo, error)
+}
@@ -2,6 +2,7 @@ package smallinterface
 
 import (
 	"context"
+	"time"
 
 	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
 )
@@ -18,4 +19,28 @@ type TodoStore interface {
 	UpdateTodo(ctx context.Context, todo *domain.Todo) error
 	DeleteTodo(ctx context.Context, id string) error
 	MarkTodoComplete(ctx context.Context, id string) error
+	ListSubtasks(ctx context.Context, parentID string) ([]*domain.Todo, error)
+	ListArchivedTodos(ctx context.Context, userID string) ([]*domain.Todo, error)
+	ListAssignedTodos(ctx context.Context, assigneeID string) ([]*domain.Todo, error)
+
+	// Batch operations
+	// Each applies every item it can and reports the others in a *domain.BatchError
+	CreateTodos(ctx context.Context, todos []*domain.Todo) error
+	UpdateTodos(ctx context.Context, todos []*domain.Todo) error
+	DeleteTodos(ctx context.Context, ids []string) error
+	MarkTodosComplete(ctx context.Context, ids []string) error
+
+	// Scheduling operations
+	SetTodoDueDate(ctx context.Context, id string, dueAt *time.Time) error
+	SetTodoPriority(ctx context.Context, id string, priority domain.Priority) error
+	ListOverdueTodos(ctx context.Context, userID string, now time.Time) ([]*domain.Todo, error)
+	ListTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.Todo, error)
+	ListUserTodosByPriority(ctx context.Context, userID string) ([]*domain.Todo, error)
+
+	// Trash operations
+	GetDeletedTodo(ctx context.Context, id string) (*domain.Todo, error)
+	ListDeletedTodos(ctx context.Context, userID string) ([]*domain.Todo, error)
+	RestoreTodo(ctx context.Context, id string) error
+	PurgeTodo(ctx context.Context, id string) error
+	PurgeTodosDeletedBefore(ctx context.Context, cutoff time.Time) (int, error)
 }
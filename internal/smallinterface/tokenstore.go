@@ -0,0 +1,19 @@
+package smallinterface
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+//go:generate mockgen -destination=./mocks/mock_tokenstore.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface TokenStore
+
+// TokenStore is a small interface that defines only API token operations
+// This is an example of a high cohesion approach
+type TokenStore interface {
+	GetToken(ctx context.Context, id string) (*domain.Token, error)
+	ListUserTokens(ctx context.Context, userID string) ([]*domain.Token, error)
+	CreateToken(ctx context.Context, token *domain.Token) error
+	RevokeToken(ctx context.Context, id string, revokedAt time.Time) error
+}
@@ -0,0 +1,15 @@
+package smallinterface
+
+import (
+	"context"
+)
+
+//go:generate mockgen -destination=./mocks/mock_txrunner.go -package=mocks github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface TxRunner
+
+// TxRunner is a small interface that defines only how to run a unit of work
+// fn receives a UserStore and TodoStore scoped to the transaction and must use them, and the
+// context it is given, for every read and write that belongs to the unit of work
+// The transaction commits when fn returns nil and rolls back when it returns an error
+type TxRunner interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context, users UserStore, todos TodoStore) error) error
+}
@@ -16,4 +16,15 @@ type UserStore interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	UpdateUser(ctx context.Context, user *domain.User) error
 	DeleteUser(ctx context.Context, id string) error
+
+	// Batch operations
+	// Each applies every item it can and reports the others in a *domain.BatchError
+	CreateUsers(ctx context.Context, users []*domain.User) error
+	DeleteUsers(ctx context.Context, ids []string) error
+
+	// Trash operations
+	GetDeletedUser(ctx context.Context, id string) (*domain.User, error)
+	ListDeletedUsers(ctx context.Context) ([]*domain.User, error)
+	RestoreUser(ctx context.Context, id string) error
+	PurgeUser(ctx context.Context, id string) error
 }
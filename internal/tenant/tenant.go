@@ -0,0 +1,43 @@
+// Package tenant carries the tenant, or workspace, whose data a request acts on
+// Stores keep the data of every tenant apart, so that one tenant never sees nor collides with the IDs of another
+package tenant
+
+import (
+	"context"
+	"fmt"
+)
+
+// Default is the tenant of contexts that do not name one, so that a deployment hosting a single team needs no setup
+const Default = "default"
+
+// maxIDLength is the length limit of a tenant ID
+const maxIDLength = 64
+
+type tenantKey struct{}
+
+// WithID returns a context acting on the data of the tenant with the given ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant ID of the context, or Default when it has none
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Validate checks that id is a tenant ID: 1 to 64 lowercase letters, digits, hyphens and underscores
+// IDs from outside the process, such as request headers, are validated before they are put in a context
+func Validate(id string) error {
+	if id == "" || len(id) > maxIDLength {
+		return fmt.Errorf("invalid tenant ID %q: must be 1 to %d characters", id, maxIDLength)
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return fmt.Errorf("invalid tenant ID %q: only lowercase letters, digits, '-' and '_' are allowed", id)
+		}
+	}
+	return nil
+}
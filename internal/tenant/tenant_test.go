@@ -0,0 +1,41 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "acme", FromContext(WithID(context.Background(), "acme")))
+	assert.Equal(t, Default, FromContext(WithID(context.Background(), "")))
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		id        string
+		expectErr bool
+	}{
+		"Lowercase letters and digits": {id: "acme42"},
+		"Hyphens and underscores":      {id: "team-a_1"},
+		"Longest ID":                   {id: strings.Repeat("a", 64)},
+		"Empty":                        {id: "", expectErr: true},
+		"Too long":                     {id: strings.Repeat("a", 65), expectErr: true},
+		"Uppercase letters":            {id: "Acme", expectErr: true},
+		"Path":                         {id: "../acme", expectErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(tt.id)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// csvColumn is a JSON field of users or Todos
+// String fields are written as they are; other fields are written as JSON, except that
+// JSON strings such as timestamps lose their quotes
+type csvColumn struct {
+	name     string
+	isString bool
+}
+
+// csvColumns are the columns after the leading "type" column: the JSON fields of users and then
+// those of Todos in the order they are declared, with fields they share listed once
+// Cells of fields a record does not have are left empty
+var csvColumns = mergeColumns(jsonColumns(reflect.TypeOf(domain.User{})), jsonColumns(reflect.TypeOf(domain.Todo{})))
+
+func jsonColumns(t reflect.Type) []csvColumn {
+	columns := make([]csvColumn, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		columns = append(columns, csvColumn{name: name, isString: field.Type.Kind() == reflect.String})
+	}
+	return columns
+}
+
+func mergeColumns(lists ...[]csvColumn) []csvColumn {
+	seen := make(map[string]struct{})
+	merged := make([]csvColumn, 0)
+	for _, columns := range lists {
+		for _, column := range columns {
+			if _, ok := seen[column.name]; ok {
+				continue
+			}
+			seen[column.name] = struct{}{}
+			merged = append(merged, column)
+		}
+	}
+	return merged
+}
+
+func encodeCSV(w io.Writer, data *Dataset) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, 0, len(csvColumns)+1)
+	header = append(header, "type")
+	for _, column := range csvColumns {
+		header = append(header, column.name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, user := range data.Users {
+		if err := writeCSVRecord(cw, recordUser, user); err != nil {
+			return err
+		}
+	}
+	for _, todo := range data.Todos {
+		if err := writeCSVRecord(cw, recordTodo, todo); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeCSVRecord(cw *csv.Writer, recordType string, entity interface{}) error {
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return err
+	}
+
+	row := make([]string, len(csvColumns)+1)
+	row[0] = recordType
+	for i, column := range csvColumns {
+		raw, ok := fields[column.name]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			row[i+1] = text
+		} else {
+			row[i+1] = string(raw)
+		}
+	}
+	return cw.Write(row)
+}
+
+func decodeCSV(r io.Reader) (*Dataset, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return &Dataset{}, nil
+		}
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	byName := make(map[string]csvColumn, len(csvColumns))
+	for _, column := range csvColumns {
+		byName[column.name] = column
+	}
+	columns := make([]csvColumn, len(header))
+	typeIndex := -1
+	for i, name := range header {
+		if name == "type" {
+			typeIndex = i
+			continue
+		}
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("csv header: unknown column %q", name)
+		}
+		columns[i] = column
+	}
+	if typeIndex < 0 {
+		return nil, errors.New("csv header: missing column \"type\"")
+	}
+
+	data := &Dataset{}
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		fields := make(map[string]json.RawMessage)
+		for i, cell := range row {
+			if i == typeIndex || cell == "" {
+				continue
+			}
+			fields[columns[i].name] = cellJSON(columns[i], cell)
+		}
+		encoded, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		switch row[typeIndex] {
+		case recordUser:
+			user := &domain.User{}
+			if err := unmarshalStrict(encoded, user); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			data.Users = append(data.Users, user)
+		case recordTodo:
+			todo := &domain.Todo{}
+			if err := unmarshalStrict(encoded, todo); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			data.Todos = append(data.Todos, todo)
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, row[typeIndex])
+		}
+	}
+	return data, nil
+}
+
+// cellJSON turns a cell back into the JSON value it was written from
+// A cell of a non-string field that is not valid JSON was a JSON string, such as a timestamp
+func cellJSON(column csvColumn, cell string) json.RawMessage {
+	if !column.isString && json.Valid([]byte(cell)) {
+		return json.RawMessage(cell)
+	}
+	quoted, _ := json.Marshal(cell)
+	return quoted
+}
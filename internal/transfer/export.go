@@ -0,0 +1,62 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// Exporter reads users and their Todos out of the stores
+// Archived Todos are exported, while users and Todos in the trash are left out
+type Exporter struct {
+	userStore smallinterface.UserStore
+	todoStore smallinterface.TodoStore
+}
+
+// NewExporter creates a new Exporter
+func NewExporter(userStore smallinterface.UserStore, todoStore smallinterface.TodoStore) *Exporter {
+	return &Exporter{
+		userStore: userStore,
+		todoStore: todoStore,
+	}
+}
+
+// Export returns the data of every user, or only of the given user when userID is not empty
+// Users are ordered by ID, and each user's Todos are in their manual order followed by the archived ones
+func (e *Exporter) Export(ctx context.Context, userID string) (*Dataset, error) {
+	users := make([]*domain.User, 0)
+	if userID != "" {
+		user, err := e.userStore.GetUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUserNotFound, userID)
+		}
+		users = append(users, user)
+	} else {
+		all, err := e.userStore.ListUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, all...)
+		sort.Slice(users, func(i, j int) bool {
+			return users[i].ID < users[j].ID
+		})
+	}
+
+	data := &Dataset{Users: users, Todos: make([]*domain.Todo, 0)}
+	for _, user := range users {
+		todos, err := e.todoStore.ListUserTodos(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		archived, err := e.todoStore.ListArchivedTodos(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		data.Todos = append(data.Todos, todos...)
+		data.Todos = append(data.Todos, archived...)
+	}
+	return data, nil
+}
@@ -0,0 +1,90 @@
+// Package transfer moves users and their Todos in and out of the stores as JSON, CSV or NDJSON
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// Dataset is the data an export produces and an import consumes
+// Todos are listed after their owners, and subtasks after their parents
+type Dataset struct {
+	Users []*domain.User `json:"users"`
+	Todos []*domain.Todo `json:"todos"`
+}
+
+// Format is an encoding of a Dataset
+type Format string
+
+const (
+	// FormatJSON is a single JSON document holding a Dataset
+	FormatJSON Format = "json"
+	// FormatCSV is a CSV table with one row per record, see csv.go for its columns
+	FormatCSV Format = "csv"
+	// FormatNDJSON is one JSON record per line, so that large exports can be streamed
+	FormatNDJSON Format = "ndjson"
+)
+
+// Record types name the entity of a CSV row or an NDJSON line
+const (
+	recordUser = "user"
+	recordTodo = "todo"
+)
+
+// ParseFormat returns the Format with the given name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatJSON, FormatCSV, FormatNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, want json, csv or ndjson", name)
+	}
+}
+
+// Encode writes a Dataset in the given format
+func Encode(w io.Writer, format Format, data *Dataset) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case FormatCSV:
+		return encodeCSV(w, data)
+	case FormatNDJSON:
+		return encodeNDJSON(w, data)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Decode reads a Dataset in the given format
+// Unknown fields are rejected, so that a typo does not silently drop data
+func Decode(r io.Reader, format Format) (*Dataset, error) {
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		data := &Dataset{}
+		if err := dec.Decode(data); err != nil {
+			return nil, fmt.Errorf("decode json: %w", err)
+		}
+		return data, nil
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatNDJSON:
+		return decodeNDJSON(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// unmarshalStrict decodes JSON into v, rejecting unknown fields
+func unmarshalStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
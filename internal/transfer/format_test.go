@@ -0,0 +1,102 @@
+package transfer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func sampleDataset() *Dataset {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := created.Add(48 * time.Hour)
+	return &Dataset{
+		Users: []*domain.User{
+			{ID: "user1", Name: "John, \"JD\" Doe", Email: "john@example.com", CreatedAt: created, UpdatedAt: created},
+		},
+		Todos: []*domain.Todo{
+			{ID: "todo1", UserID: "user1", Position: 1, Title: "Write report", Description: "line one\nline two", Priority: domain.PriorityHigh, DueAt: &due, CreatedAt: created, UpdatedAt: created},
+			{ID: "todo2", UserID: "user1", ParentID: "todo1", Position: 1.5, Title: "123", Completed: true, CompletedAt: &created, CreatedAt: created, UpdatedAt: created},
+			{ID: "todo3", UserID: "user1", Title: "Water plants", Recurrence: &domain.Recurrence{Frequency: domain.FrequencyDaily, Interval: 2}, SeriesID: "todo3", Occurrence: 1, CreatedAt: created, UpdatedAt: created},
+		},
+	}
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatCSV, FormatNDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, format, sampleDataset()))
+
+			data, err := Decode(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, sampleDataset(), data)
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := map[string]struct {
+		format  Format
+		input   string
+		wantErr string
+	}{
+		"json with an unknown field": {
+			format:  FormatJSON,
+			input:   `{"users":[{"id":"user1","nickname":"jd"}]}`,
+			wantErr: "nickname",
+		},
+		"csv with an unknown column": {
+			format:  FormatCSV,
+			input:   "type,id,nickname\nuser,user1,jd\n",
+			wantErr: `unknown column "nickname"`,
+		},
+		"csv without a type column": {
+			format:  FormatCSV,
+			input:   "id,name\nuser1,John\n",
+			wantErr: `missing column "type"`,
+		},
+		"csv with an unknown record type": {
+			format:  FormatCSV,
+			input:   "type,id\nuser,user1\nproject,project1\n",
+			wantErr: `line 3: unknown record type "project"`,
+		},
+		"csv with a malformed value": {
+			format:  FormatCSV,
+			input:   "type,id,priority\ntodo,todo1,high\n",
+			wantErr: "line 2:",
+		},
+		"ndjson with a malformed line": {
+			format:  FormatNDJSON,
+			input:   "{\"type\":\"user\",\"user\":{\"id\":\"user1\"}}\n\n{\"type\":\"todo\"\n",
+			wantErr: "line 3:",
+		},
+		"ndjson with a mismatched record": {
+			format:  FormatNDJSON,
+			input:   `{"type":"todo","user":{"id":"user1"}}`,
+			wantErr: "line 1:",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.input), tt.format)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("ndjson")
+	require.NoError(t, err)
+	assert.Equal(t, FormatNDJSON, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
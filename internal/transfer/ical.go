@@ -0,0 +1,298 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// iCalendar (RFC 5545) date and date-time value formats
+const (
+	icalDate        = "20060102"
+	icalDateTime    = "20060102T150405"
+	icalDateTimeUTC = "20060102T150405Z"
+)
+
+// maxICalLine is the longest content line EncodeICal writes, in octets without the line break
+const maxICalLine = 75
+
+const icalProductID = "-//big-interface-vs-small-interface//transfer//EN"
+
+// EncodeICal writes Todos as the VTODO components of an iCalendar (RFC 5545) object
+// The UID of each component is the ID of its Todo, so that calendar apps update it on the next sync
+func EncodeICal(w io.Writer, todos []*domain.Todo) error {
+	bw := bufio.NewWriter(w)
+	write := func(name, value string) {
+		writeICalLine(bw, name+":"+value)
+	}
+
+	write("BEGIN", "VCALENDAR")
+	write("VERSION", "2.0")
+	write("PRODID", icalProductID)
+	for _, todo := range todos {
+		write("BEGIN", "VTODO")
+		write("UID", escapeICalText(todo.ID))
+		write("DTSTAMP", formatICalTime(todo.UpdatedAt))
+		write("CREATED", formatICalTime(todo.CreatedAt))
+		write("LAST-MODIFIED", formatICalTime(todo.UpdatedAt))
+		write("SUMMARY", escapeICalText(todo.Title))
+		if todo.Description != "" {
+			write("DESCRIPTION", escapeICalText(todo.Description))
+		}
+		if todo.DueAt != nil {
+			write("DUE", formatICalTime(*todo.DueAt))
+		}
+		if todo.Completed {
+			write("STATUS", "COMPLETED")
+			if todo.CompletedAt != nil {
+				write("COMPLETED", formatICalTime(*todo.CompletedAt))
+			}
+		} else {
+			write("STATUS", "NEEDS-ACTION")
+		}
+		write("END", "VTODO")
+	}
+	write("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// writeICalLine writes a content line, folding it into lines of at most maxICalLine octets
+// Continuation lines start with a space, and UTF-8 sequences are never split
+func writeICalLine(w *bufio.Writer, line string) {
+	limit := maxICalLine
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the length of continuation lines
+		limit = maxICalLine - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format(icalDateTimeUTC)
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(text string) string {
+	return icalTextEscaper.Replace(text)
+}
+
+func unescapeICalText(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
+
+// icalProperty is an unfolded content line
+type icalProperty struct {
+	line   int
+	name   string
+	params map[string]string
+	value  string
+}
+
+// DecodeICal reads the VTODO components of an iCalendar object as Todos
+// The ID of each Todo is the UID of its component and UserID is left empty
+// Other components, such as VEVENT or the VALARM of a VTODO, and unknown properties are ignored
+func DecodeICal(r io.Reader) ([]*domain.Todo, error) {
+	properties, err := readICalProperties(r)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]*domain.Todo, 0)
+	var components []string
+	var todo *domain.Todo
+	var begin int
+	for _, prop := range properties {
+		switch prop.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(prop.value))
+			if components[len(components)-1] == "VTODO" {
+				if todo != nil {
+					return nil, fmt.Errorf("line %d: nested VTODO", prop.line)
+				}
+				todo = &domain.Todo{}
+				begin = prop.line
+			}
+			continue
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(prop.value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", prop.line, prop.value)
+			}
+			components = components[:len(components)-1]
+			if strings.ToUpper(prop.value) == "VTODO" {
+				if todo.ID == "" {
+					return nil, fmt.Errorf("line %d: VTODO without UID", begin)
+				}
+				// STATUS has the last word on completion
+				if !todo.Completed {
+					todo.CompletedAt = nil
+				}
+				todos = append(todos, todo)
+				todo = nil
+			}
+			continue
+		}
+		if todo == nil || components[len(components)-1] != "VTODO" {
+			continue
+		}
+		if err := applyICalProperty(todo, prop); err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", prop.line, prop.name, err)
+		}
+	}
+	if len(components) > 0 {
+		return nil, fmt.Errorf("missing END:%s", components[len(components)-1])
+	}
+	return todos, nil
+}
+
+func applyICalProperty(todo *domain.Todo, prop icalProperty) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		todo.ID = unescapeICalText(prop.value)
+	case "SUMMARY":
+		todo.Title = unescapeICalText(prop.value)
+	case "DESCRIPTION":
+		todo.Description = unescapeICalText(prop.value)
+	case "STATUS":
+		todo.Completed = strings.ToUpper(prop.value) == "COMPLETED"
+	case "COMPLETED":
+		var completed time.Time
+		if completed, err = parseICalTime(prop); err == nil {
+			todo.Completed = true
+			todo.CompletedAt = &completed
+		}
+	case "DUE":
+		var due time.Time
+		if due, err = parseICalTime(prop); err == nil {
+			todo.DueAt = &due
+		}
+	case "CREATED":
+		todo.CreatedAt, err = parseICalTime(prop)
+	case "LAST-MODIFIED":
+		todo.UpdatedAt, err = parseICalTime(prop)
+	}
+	return err
+}
+
+// parseICalTime parses a DATE or DATE-TIME value
+// Dates and floating date-times are taken as UTC, and TZID names an IANA time zone
+func parseICalTime(prop icalProperty) (time.Time, error) {
+	if len(prop.value) == len(icalDate) {
+		return time.Parse(icalDate, prop.value)
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		return time.Parse(icalDateTimeUTC, prop.value)
+	}
+	loc := time.UTC
+	if tzid, ok := prop.params["TZID"]; ok {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	return time.ParseInLocation(icalDateTime, prop.value, loc)
+}
+
+// readICalProperties unfolds the content lines of an iCalendar object and splits them into properties
+func readICalProperties(r io.Reader) ([]icalProperty, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+
+	var lines []string
+	var starts []int
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if len(lines) == 0 {
+				return nil, fmt.Errorf("line %d: continuation without a content line", number)
+			}
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		starts = append(starts, number)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ical: %w", err)
+	}
+
+	properties := make([]icalProperty, 0, len(lines))
+	for i, line := range lines {
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", starts[i], err)
+		}
+		prop.line = starts[i]
+		properties = append(properties, prop)
+	}
+	return properties, nil
+}
+
+// parseICalLine splits a content line into its name, parameters and value
+// The value starts at the first colon outside a quoted parameter value
+func parseICalLine(line string) (icalProperty, error) {
+	colon := -1
+	quoted := false
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return icalProperty{}, fmt.Errorf("missing colon in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  line[colon+1:],
+	}
+	if prop.name == "" {
+		return icalProperty{}, fmt.Errorf("missing property name in %q", line)
+	}
+	for _, param := range parts[1:] {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return icalProperty{}, fmt.Errorf("malformed parameter %q", param)
+		}
+		prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
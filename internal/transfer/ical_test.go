@@ -0,0 +1,161 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+func TestEncodeDecodeICal_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := created.Add(48 * time.Hour)
+	todos := []*domain.Todo{
+		{ID: "todo1", Title: "Write the report, then; send it", Description: "line one\nline two with a \\ backslash", DueAt: &due, CreatedAt: created, UpdatedAt: created},
+		{ID: "todo2", Title: strings.Repeat("長い件名", 20), Completed: true, CompletedAt: &due, CreatedAt: created, UpdatedAt: due},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeICal(&buf, todos))
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxICalLine)
+	}
+
+	decoded, err := DecodeICal(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, todos, decoded)
+}
+
+func TestDecodeICal(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	completed := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:event1",
+		"SUMMARY:Not a todo",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:todo1@example.com",
+		"summary:Buy milk",
+		"DUE;VALUE=DATE:20240305",
+		"BEGIN:VALARM",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:todo2@example.com",
+		"SUMMARY:Call\\, then",
+		"  write",
+		"DUE;TZID=\"America/New_York\":20240305T170000",
+		"COMPLETED:20240301T090000Z",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:todo3@example.com",
+		"COMPLETED:20240301T090000Z",
+		"STATUS:NEEDS-ACTION",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\n")
+
+	todos, err := DecodeICal(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, todos, 3)
+
+	dueDate := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, &domain.Todo{ID: "todo1@example.com", Title: "Buy milk", DueAt: &dueDate}, todos[0])
+
+	dueTime := time.Date(2024, 3, 5, 17, 0, 0, 0, newYork)
+	assert.Equal(t, "Call, then write", todos[1].Title)
+	require.NotNil(t, todos[1].DueAt)
+	assert.True(t, dueTime.Equal(*todos[1].DueAt))
+	assert.True(t, todos[1].Completed)
+	assert.Equal(t, &completed, todos[1].CompletedAt)
+
+	assert.False(t, todos[2].Completed)
+	assert.Nil(t, todos[2].CompletedAt)
+}
+
+func TestDecodeICal_Errors(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"missing uid": {
+			input:   "BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:x\nEND:VTODO\nEND:VCALENDAR\n",
+			wantErr: "line 2: VTODO without UID",
+		},
+		"mismatched end": {
+			input:   "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:x\nEND:VEVENT\n",
+			wantErr: "line 4: unexpected END:VEVENT",
+		},
+		"unterminated component": {
+			input:   "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:x\nEND:VTODO\n",
+			wantErr: "missing END:VCALENDAR",
+		},
+		"line without a colon": {
+			input:   "BEGIN:VCALENDAR\nSUMMARY\n",
+			wantErr: "line 2: missing colon",
+		},
+		"malformed date": {
+			input:   "BEGIN:VTODO\nUID:x\nDUE:tomorrow\nEND:VTODO\n",
+			wantErr: "line 3: DUE:",
+		},
+		"unknown time zone": {
+			input:   "BEGIN:VTODO\nUID:x\nDUE;TZID=Mars/Olympus:20240305T170000\nEND:VTODO\n",
+			wantErr: `unknown time zone "Mars/Olympus"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodeICal(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestImporter_ImportICal(t *testing.T) {
+	ctx := context.Background()
+	store := seededStore(t)
+	due := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	existing, err := store.GetTodo(ctx, "todo1")
+	require.NoError(t, err)
+	existing.Priority = domain.PriorityHigh
+	require.NoError(t, store.UpdateTodo(ctx, existing))
+
+	report, err := NewImporter(store, store).ImportICal(ctx, "user1", []*domain.Todo{
+		{ID: "todo1", Title: "Renamed", Completed: true, DueAt: &due},
+		{ID: "todo1@example.com", Title: "From the calendar"},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, ImportCounts{Created: 1, Updated: 1}, report.Todos)
+
+	updated, err := store.GetTodo(ctx, "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Title)
+	assert.Equal(t, domain.PriorityHigh, updated.Priority)
+	assert.True(t, updated.Completed)
+	assert.NotNil(t, updated.CompletedAt)
+	assert.Equal(t, &due, updated.DueAt)
+
+	created, err := store.GetTodo(ctx, "todo1@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "user1", created.UserID)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	report, err = NewImporter(store, store).ImportICal(ctx, "user2", []*domain.Todo{{ID: "todo1", Title: "Taken"}}, false)
+	assert.ErrorIs(t, err, ErrInvalidData)
+	assert.Equal(t, []RecordError{{Entity: recordTodo, Index: 0, ID: "todo1", Err: errors.New("existing todo belongs to another user")}}, report.Errors)
+}
@@ -0,0 +1,452 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/smallinterface"
+)
+
+// ErrInvalidData is returned when records of an import fail validation
+// The report lists them
+var ErrInvalidData = errors.New("import data is invalid")
+
+// DuplicateStrategy decides what an import does with a record whose ID is already taken in the store
+type DuplicateStrategy string
+
+const (
+	// DuplicateFail reports the record as invalid, so nothing is imported
+	DuplicateFail DuplicateStrategy = "fail"
+	// DuplicateSkip keeps the existing entity and ignores the record
+	DuplicateSkip DuplicateStrategy = "skip"
+	// DuplicateOverwrite replaces the existing entity with the record
+	// Entities in the trash are never overwritten
+	DuplicateOverwrite DuplicateStrategy = "overwrite"
+)
+
+// ParseDuplicateStrategy returns the DuplicateStrategy with the given name
+func ParseDuplicateStrategy(name string) (DuplicateStrategy, error) {
+	switch strategy := DuplicateStrategy(name); strategy {
+	case DuplicateFail, DuplicateSkip, DuplicateOverwrite:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown duplicate strategy %q, want fail, skip or overwrite", name)
+	}
+}
+
+// ImportOptions configures an import
+type ImportOptions struct {
+	Duplicates DuplicateStrategy
+	// DryRun validates the data and reports what would be imported without writing anything
+	DryRun bool
+}
+
+// ImportCounts is how many records of one entity an import creates, overwrites and skips
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// RecordError is a record that failed validation
+// Index is the position of the record among the users or the Todos of the Dataset
+type RecordError struct {
+	Entity string
+	Index  int
+	ID     string
+	Err    error
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("%s %d (%s): %v", e.Entity, e.Index, e.ID, e.Err)
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Report describes what an import did, or what it would do in a dry run
+type Report struct {
+	DryRun bool
+	Users  ImportCounts
+	Todos  ImportCounts
+	Errors []RecordError
+}
+
+// Importer writes a Dataset into the stores
+type Importer struct {
+	txRunner     smallinterface.TxRunner
+	projectStore smallinterface.ProjectStore // Only used to validate project membership
+}
+
+// NewImporter creates a new Importer
+func NewImporter(txRunner smallinterface.TxRunner, projectStore smallinterface.ProjectStore) *Importer {
+	return &Importer{
+		txRunner:     txRunner,
+		projectStore: projectStore,
+	}
+}
+
+// importAction is what an import does with a valid record
+type importAction int
+
+const (
+	actionCreate importAction = iota
+	actionUpdate
+	actionSkip
+)
+
+// importPlan holds the writes of an import, with Todos ordered so that parents come first
+type importPlan struct {
+	createUsers []*domain.User
+	updateUsers []*domain.User
+	createTodos []*domain.Todo
+	updateTodos []*domain.Todo
+}
+
+// Import validates every record and then writes them all in a single transaction
+// Nothing is written when a record is invalid or a write fails; the report then describes
+// what was planned and lists the invalid records
+// Created Todos keep the positions they come with, 0 included
+func (i *Importer) Import(ctx context.Context, data *Dataset, opts ImportOptions) (*Report, error) {
+	return i.run(ctx, opts, func(context.Context, smallinterface.TodoStore) *Dataset {
+		for _, todo := range data.Todos {
+			todo.PositionSet = true
+		}
+		return data
+	})
+}
+
+// ImportICal creates or updates the given user's Todos from decoded VTODO components, keyed by their UID
+// Fields iCalendar does not carry, such as the priority or the project, are kept on updated Todos
+// A UID taken by another user's Todo or by a Todo in the trash is reported as invalid
+func (i *Importer) ImportICal(ctx context.Context, userID string, entries []*domain.Todo, dryRun bool) (*Report, error) {
+	now := time.Now()
+	opts := ImportOptions{Duplicates: DuplicateOverwrite, DryRun: dryRun}
+	return i.run(ctx, opts, func(ctx context.Context, todos smallinterface.TodoStore) *Dataset {
+		data := &Dataset{Todos: make([]*domain.Todo, 0, len(entries))}
+		for _, entry := range entries {
+			todo := *entry
+			if existing, err := todos.GetTodo(ctx, entry.ID); err == nil && existing.UserID == userID {
+				todo = *existing
+				todo.Title = entry.Title
+				todo.Description = entry.Description
+				todo.Completed = entry.Completed
+				todo.CompletedAt = entry.CompletedAt
+				todo.DueAt = entry.DueAt
+				todo.UpdatedAt = entry.UpdatedAt
+			}
+			todo.UserID = userID
+			if todo.CreatedAt.IsZero() {
+				todo.CreatedAt = now
+			}
+			if todo.UpdatedAt.IsZero() {
+				todo.UpdatedAt = now
+			}
+			if todo.Completed && todo.CompletedAt == nil {
+				todo.CompletedAt = &todo.UpdatedAt
+			}
+			data.Todos = append(data.Todos, &todo)
+		}
+		return data
+	})
+}
+
+// run plans the Dataset prepare returns and writes it in a transaction, unless it is a dry run
+func (i *Importer) run(ctx context.Context, opts ImportOptions, prepare func(ctx context.Context, todos smallinterface.TodoStore) *Dataset) (*Report, error) {
+	if _, err := ParseDuplicateStrategy(string(opts.Duplicates)); err != nil {
+		return nil, err
+	}
+
+	var report *Report
+	err := i.txRunner.RunInTx(ctx, func(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error {
+		report = &Report{DryRun: opts.DryRun}
+		plan, err := i.plan(ctx, users, todos, prepare(ctx, todos), opts.Duplicates, report)
+		if err != nil {
+			return err
+		}
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("%w: %d records failed validation", ErrInvalidData, len(report.Errors))
+		}
+		if opts.DryRun {
+			return nil
+		}
+		return plan.apply(ctx, users, todos)
+	})
+	if report == nil {
+		return nil, err
+	}
+	return report, err
+}
+
+func (i *Importer) plan(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore, data *Dataset, duplicates DuplicateStrategy, report *Report) (*importPlan, error) {
+	plan := &importPlan{}
+	// owners caches whether a user can own imported Todos
+	owners := make(map[string]bool)
+
+	seen := make(map[string]struct{}, len(data.Users))
+	for index, user := range data.Users {
+		fail := func(err error) {
+			report.Errors = append(report.Errors, RecordError{Entity: recordUser, Index: index, ID: user.ID, Err: err})
+		}
+		if err := validateUser(user); err != nil {
+			fail(err)
+			continue
+		}
+		if _, ok := seen[user.ID]; ok {
+			fail(errors.New("id appears more than once"))
+			continue
+		}
+		seen[user.ID] = struct{}{}
+
+		_, liveErr := users.GetUser(ctx, user.ID)
+		_, trashErr := users.GetDeletedUser(ctx, user.ID)
+		action, err := resolveDuplicate(duplicates, liveErr == nil, trashErr == nil)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		owners[user.ID] = true
+		switch action {
+		case actionCreate:
+			plan.createUsers = append(plan.createUsers, user)
+			report.Users.Created++
+		case actionUpdate:
+			plan.updateUsers = append(plan.updateUsers, user)
+			report.Users.Updated++
+		case actionSkip:
+			report.Users.Skipped++
+		}
+	}
+
+	if err := i.planTodos(ctx, users, todos, data.Todos, duplicates, owners, plan, report); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(report.Errors, func(a, b int) bool {
+		if report.Errors[a].Entity != report.Errors[b].Entity {
+			return report.Errors[a].Entity == recordUser
+		}
+		return report.Errors[a].Index < report.Errors[b].Index
+	})
+	return plan, nil
+}
+
+// planTodos validates the Todos parents first, so that a Todo whose parent is invalid is reported too
+func (i *Importer) planTodos(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore, records []*domain.Todo, duplicates DuplicateStrategy, owners map[string]bool, plan *importPlan, report *Report) error {
+	fail := func(index int, err error) {
+		report.Errors = append(report.Errors, RecordError{Entity: recordTodo, Index: index, ID: records[index].ID, Err: err})
+	}
+
+	byID := make(map[string]int, len(records))
+	candidates := make([]int, 0, len(records))
+	for index, todo := range records {
+		if todo.ID == "" {
+			fail(index, errors.New("id is required"))
+			continue
+		}
+		if _, ok := byID[todo.ID]; ok {
+			fail(index, errors.New("id appears more than once"))
+			continue
+		}
+		byID[todo.ID] = index
+		candidates = append(candidates, index)
+	}
+
+	depths := make(map[int]int, len(candidates))
+	ordered := make([]int, 0, len(candidates))
+	for _, index := range candidates {
+		depth, err := importDepth(records, byID, index, make(map[int]struct{}))
+		if err != nil {
+			fail(index, err)
+			continue
+		}
+		depths[index] = depth
+		ordered = append(ordered, index)
+	}
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return depths[ordered[a]] < depths[ordered[b]]
+	})
+
+	// valid holds the imported Todos that passed, which their subtasks may refer to
+	valid := make(map[string]*domain.Todo, len(ordered))
+	for _, index := range ordered {
+		todo := records[index]
+		if err := i.checkTodo(ctx, users, todos, todo, owners, byID, valid); err != nil {
+			fail(index, err)
+			continue
+		}
+
+		existing, liveErr := todos.GetTodo(ctx, todo.ID)
+		_, trashErr := todos.GetDeletedTodo(ctx, todo.ID)
+		action, err := resolveDuplicate(duplicates, liveErr == nil, trashErr == nil)
+		if err != nil {
+			fail(index, err)
+			continue
+		}
+		if action == actionUpdate && existing.UserID != todo.UserID {
+			fail(index, errors.New("existing todo belongs to another user"))
+			continue
+		}
+		valid[todo.ID] = todo
+		switch action {
+		case actionCreate:
+			plan.createTodos = append(plan.createTodos, todo)
+			report.Todos.Created++
+		case actionUpdate:
+			plan.updateTodos = append(plan.updateTodos, todo)
+			report.Todos.Updated++
+		case actionSkip:
+			report.Todos.Skipped++
+		}
+	}
+	return nil
+}
+
+// checkTodo validates a Todo against the store and the imported records
+func (i *Importer) checkTodo(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore, todo *domain.Todo, owners map[string]bool, byID map[string]int, valid map[string]*domain.Todo) error {
+	if todo.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	owner, ok := owners[todo.UserID]
+	if !ok {
+		_, err := users.GetUser(ctx, todo.UserID)
+		owner = err == nil
+		owners[todo.UserID] = owner
+	}
+	if !owner {
+		return fmt.Errorf("user not found: %s", todo.UserID)
+	}
+	if todo.AssigneeID != "" {
+		assignee, ok := owners[todo.AssigneeID]
+		if !ok {
+			_, err := users.GetUser(ctx, todo.AssigneeID)
+			assignee = err == nil
+			owners[todo.AssigneeID] = assignee
+		}
+		if !assignee {
+			return fmt.Errorf("assignee not found: %s", todo.AssigneeID)
+		}
+	}
+	if !todo.Priority.IsValid() {
+		return fmt.Errorf("invalid priority: %d", todo.Priority)
+	}
+	if todo.Recurrence != nil {
+		if err := todo.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if todo.ParentID != "" {
+		parent, ok := valid[todo.ParentID]
+		if _, imported := byID[todo.ParentID]; imported && !ok {
+			return fmt.Errorf("parent todo %s is invalid", todo.ParentID)
+		}
+		if !ok {
+			var err error
+			if parent, err = todos.GetTodo(ctx, todo.ParentID); err != nil {
+				return fmt.Errorf("parent todo not found: %s", todo.ParentID)
+			}
+		}
+		if parent.UserID != todo.UserID {
+			return errors.New("parent todo belongs to another user")
+		}
+		if importedDepth(ctx, todos, valid, todo.ParentID) > domain.MaxSubtaskDepth {
+			return fmt.Errorf("subtasks cannot be nested deeper than %d levels", domain.MaxSubtaskDepth)
+		}
+	}
+
+	if todo.ProjectID != "" {
+		project, err := i.projectStore.GetProject(ctx, todo.ProjectID)
+		if err != nil {
+			return fmt.Errorf("project not found: %s", todo.ProjectID)
+		}
+		if project.UserID != todo.UserID {
+			return errors.New("project belongs to another user")
+		}
+	}
+	return nil
+}
+
+func (p *importPlan) apply(ctx context.Context, users smallinterface.UserStore, todos smallinterface.TodoStore) error {
+	if err := users.CreateUsers(ctx, p.createUsers); err != nil {
+		return err
+	}
+	for _, user := range p.updateUsers {
+		if err := users.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+	}
+	if err := todos.CreateTodos(ctx, p.createTodos); err != nil {
+		return err
+	}
+	return todos.UpdateTodos(ctx, p.updateTodos)
+}
+
+func validateUser(user *domain.User) error {
+	if user.ID == "" {
+		return errors.New("id is required")
+	}
+	if user.Email != "" && !strings.Contains(user.Email, "@") {
+		return fmt.Errorf("invalid email: %s", user.Email)
+	}
+	return nil
+}
+
+// resolveDuplicate decides what to do with a record whose ID may be taken by a live or a trashed entity
+func resolveDuplicate(strategy DuplicateStrategy, live, trashed bool) (importAction, error) {
+	switch {
+	case !live && !trashed:
+		return actionCreate, nil
+	case strategy == DuplicateSkip:
+		return actionSkip, nil
+	case trashed:
+		return 0, errors.New("id belongs to an entity in the trash")
+	case strategy == DuplicateOverwrite:
+		return actionUpdate, nil
+	default:
+		return 0, errors.New("id already exists")
+	}
+}
+
+// importedDepth returns how many ancestors a Todo under parentID has once imported, looking them up
+// in the imported Todos that passed first and in the store then
+// Counting stops past domain.MaxSubtaskDepth, which also ends loops between imported and stored Todos
+func importedDepth(ctx context.Context, todos smallinterface.TodoStore, valid map[string]*domain.Todo, parentID string) int {
+	depth := 0
+	for parentID != "" && depth <= domain.MaxSubtaskDepth {
+		parent, ok := valid[parentID]
+		if !ok {
+			var err error
+			if parent, err = todos.GetTodo(ctx, parentID); err != nil {
+				break
+			}
+		}
+		depth++
+		parentID = parent.ParentID
+	}
+	return depth
+}
+
+// importDepth returns how many imported ancestors a Todo has
+// visiting holds the Todos on the current path, to detect parent cycles
+func importDepth(records []*domain.Todo, byID map[string]int, index int, visiting map[int]struct{}) (int, error) {
+	parent, ok := byID[records[index].ParentID]
+	if !ok {
+		return 0, nil
+	}
+	if _, ok := visiting[index]; ok {
+		return 0, errors.New("parent_id forms a cycle")
+	}
+	visiting[index] = struct{}{}
+	depth, err := importDepth(records, byID, parent, visiting)
+	if err != nil {
+		return 0, err
+	}
+	return depth + 1, nil
+}
@@ -0,0 +1,263 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/infra/inmemory"
+)
+
+// seededStore returns a store holding user1 with todo1, and user2 with project2
+func seededStore(t *testing.T) *inmemory.Store {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+	store := inmemory.NewStore()
+	require.NoError(t, store.CreateUser(ctx, &domain.User{ID: "user1", Name: "John", Email: "john@example.com", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateUser(ctx, &domain.User{ID: "user2", Name: "Jane", Email: "jane@example.com", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "todo1", UserID: "user1", Title: "Existing", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateProject(ctx, &domain.Project{ID: "project2", UserID: "user2", Name: "Home", CreatedAt: now, UpdatedAt: now}))
+	return store
+}
+
+func TestImporter_Import(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		setup      func(t *testing.T, store *inmemory.Store)
+		data       *Dataset
+		opts       ImportOptions
+		wantReport Report
+		wantErr    error
+		check      func(t *testing.T, store *inmemory.Store)
+	}{
+		"new users and todos are created, parents first": {
+			data: &Dataset{
+				Users: []*domain.User{{ID: "user3", Name: "Alice", Email: "alice@example.com"}},
+				Todos: []*domain.Todo{
+					{ID: "todo3", UserID: "user3", ParentID: "todo2", Title: "Subtask"},
+					{ID: "todo2", UserID: "user3", Title: "Parent"},
+					{ID: "todo4", UserID: "user1", ParentID: "todo1", Title: "Under an existing todo"},
+				},
+			},
+			opts:       ImportOptions{Duplicates: DuplicateFail},
+			wantReport: Report{Users: ImportCounts{Created: 1}, Todos: ImportCounts{Created: 3}},
+			check: func(t *testing.T, store *inmemory.Store) {
+				_, err := store.GetUser(ctx, "user3")
+				assert.NoError(t, err)
+				todo, err := store.GetTodo(ctx, "todo3")
+				require.NoError(t, err)
+				assert.Equal(t, "todo2", todo.ParentID)
+			},
+		},
+		"imported positions are kept, 0 included": {
+			data: &Dataset{
+				Todos: []*domain.Todo{
+					{ID: "todo2", UserID: "user1", Position: 0, Title: "Moved to the top"},
+					{ID: "todo3", UserID: "user1", Position: 1.5, Title: "Between"},
+				},
+			},
+			opts:       ImportOptions{Duplicates: DuplicateFail},
+			wantReport: Report{Todos: ImportCounts{Created: 2}},
+			check: func(t *testing.T, store *inmemory.Store) {
+				todos, err := store.ListUserTodos(ctx, "user1")
+				require.NoError(t, err)
+				positions := make(map[string]float64, len(todos))
+				for _, todo := range todos {
+					positions[todo.ID] = todo.Position
+				}
+				assert.Equal(t, map[string]float64{"todo2": 0, "todo1": 1, "todo3": 1.5}, positions)
+			},
+		},
+		"duplicates fail the whole import": {
+			data: &Dataset{
+				Users: []*domain.User{{ID: "user1", Name: "Renamed"}},
+				Todos: []*domain.Todo{{ID: "todo2", UserID: "user1", Title: "New"}},
+			},
+			opts: ImportOptions{Duplicates: DuplicateFail},
+			wantReport: Report{
+				Todos:  ImportCounts{Created: 1},
+				Errors: []RecordError{{Entity: recordUser, Index: 0, ID: "user1", Err: errors.New("id already exists")}},
+			},
+			wantErr: ErrInvalidData,
+			check: func(t *testing.T, store *inmemory.Store) {
+				user, err := store.GetUser(ctx, "user1")
+				require.NoError(t, err)
+				assert.Equal(t, "John", user.Name)
+				_, err = store.GetTodo(ctx, "todo2")
+				assert.Error(t, err)
+			},
+		},
+		"duplicates are skipped": {
+			data: &Dataset{
+				Users: []*domain.User{{ID: "user1", Name: "Renamed"}},
+				Todos: []*domain.Todo{{ID: "todo1", UserID: "user1", Title: "Renamed"}},
+			},
+			opts:       ImportOptions{Duplicates: DuplicateSkip},
+			wantReport: Report{Users: ImportCounts{Skipped: 1}, Todos: ImportCounts{Skipped: 1}},
+			check: func(t *testing.T, store *inmemory.Store) {
+				todo, err := store.GetTodo(ctx, "todo1")
+				require.NoError(t, err)
+				assert.Equal(t, "Existing", todo.Title)
+			},
+		},
+		"duplicates are overwritten": {
+			data: &Dataset{
+				Users: []*domain.User{{ID: "user1", Name: "Renamed"}},
+				Todos: []*domain.Todo{{ID: "todo1", UserID: "user1", Title: "Renamed"}},
+			},
+			opts:       ImportOptions{Duplicates: DuplicateOverwrite},
+			wantReport: Report{Users: ImportCounts{Updated: 1}, Todos: ImportCounts{Updated: 1}},
+			check: func(t *testing.T, store *inmemory.Store) {
+				user, err := store.GetUser(ctx, "user1")
+				require.NoError(t, err)
+				assert.Equal(t, "Renamed", user.Name)
+				todo, err := store.GetTodo(ctx, "todo1")
+				require.NoError(t, err)
+				assert.Equal(t, "Renamed", todo.Title)
+			},
+		},
+		"a dry run writes nothing": {
+			data: &Dataset{
+				Users: []*domain.User{{ID: "user3", Name: "Alice"}},
+				Todos: []*domain.Todo{{ID: "todo1", UserID: "user1", Title: "Renamed"}},
+			},
+			opts:       ImportOptions{Duplicates: DuplicateOverwrite, DryRun: true},
+			wantReport: Report{DryRun: true, Users: ImportCounts{Created: 1}, Todos: ImportCounts{Updated: 1}},
+			check: func(t *testing.T, store *inmemory.Store) {
+				_, err := store.GetUser(ctx, "user3")
+				assert.Error(t, err)
+				todo, err := store.GetTodo(ctx, "todo1")
+				require.NoError(t, err)
+				assert.Equal(t, "Existing", todo.Title)
+			},
+		},
+		"invalid records are reported": {
+			data: &Dataset{
+				Users: []*domain.User{
+					{ID: "", Name: "Nobody"},
+					{ID: "user3", Email: "not-an-email"},
+				},
+				Todos: []*domain.Todo{
+					{ID: "todo2", UserID: "user9", Title: "Unknown user"},
+					{ID: "todo3", UserID: "user1", Priority: domain.Priority(9)},
+					{ID: "todo4", UserID: "user1", ParentID: "todo5"},
+					{ID: "todo5", UserID: "user1", ParentID: "todo4"},
+					{ID: "todo6", UserID: "user1", ProjectID: "project2"},
+					{ID: "todo7", UserID: "user2", ParentID: "todo1"},
+					{ID: "todo8", UserID: "user1", ParentID: "todo3"},
+					{ID: "todo8", UserID: "user1"},
+				},
+			},
+			opts: ImportOptions{Duplicates: DuplicateFail},
+			wantReport: Report{
+				Errors: []RecordError{
+					{Entity: recordUser, Index: 0, ID: "", Err: errors.New("id is required")},
+					{Entity: recordUser, Index: 1, ID: "user3", Err: errors.New("invalid email: not-an-email")},
+					{Entity: recordTodo, Index: 0, ID: "todo2", Err: errors.New("user not found: user9")},
+					{Entity: recordTodo, Index: 1, ID: "todo3", Err: errors.New("invalid priority: 9")},
+					{Entity: recordTodo, Index: 2, ID: "todo4", Err: errors.New("parent_id forms a cycle")},
+					{Entity: recordTodo, Index: 3, ID: "todo5", Err: errors.New("parent_id forms a cycle")},
+					{Entity: recordTodo, Index: 4, ID: "todo6", Err: errors.New("project belongs to another user")},
+					{Entity: recordTodo, Index: 5, ID: "todo7", Err: errors.New("parent todo belongs to another user")},
+					{Entity: recordTodo, Index: 6, ID: "todo8", Err: errors.New("parent todo todo3 is invalid")},
+					{Entity: recordTodo, Index: 7, ID: "todo8", Err: errors.New("id appears more than once")},
+				},
+			},
+			wantErr: ErrInvalidData,
+		},
+		"subtasks too deep or under a trashed parent are reported": {
+			setup: func(t *testing.T, store *inmemory.Store) {
+				require.NoError(t, store.CreateTodo(ctx, &domain.Todo{ID: "trashed", UserID: "user1"}))
+				require.NoError(t, store.DeleteTodo(ctx, "trashed"))
+			},
+			data: &Dataset{
+				Todos: []*domain.Todo{
+					{ID: "todo5", UserID: "user1", ParentID: "todo4"},
+					{ID: "todo4", UserID: "user1", ParentID: "todo3"},
+					{ID: "todo3", UserID: "user1", ParentID: "todo2"},
+					{ID: "todo2", UserID: "user1", ParentID: "todo1"},
+					{ID: "todo6", UserID: "user1", ParentID: "trashed"},
+				},
+			},
+			opts: ImportOptions{Duplicates: DuplicateFail},
+			wantReport: Report{
+				Todos: ImportCounts{Created: 3},
+				Errors: []RecordError{
+					{Entity: recordTodo, Index: 0, ID: "todo5", Err: errors.New("subtasks cannot be nested deeper than 3 levels")},
+					{Entity: recordTodo, Index: 4, ID: "todo6", Err: errors.New("parent todo not found: trashed")},
+				},
+			},
+			wantErr: ErrInvalidData,
+		},
+		"trashed entities are not overwritten": {
+			setup: func(t *testing.T, store *inmemory.Store) {
+				require.NoError(t, store.DeleteUser(ctx, "user2"))
+			},
+			data: &Dataset{
+				Users: []*domain.User{{ID: "user2", Name: "Renamed"}},
+			},
+			opts: ImportOptions{Duplicates: DuplicateOverwrite},
+			wantReport: Report{
+				Errors: []RecordError{{Entity: recordUser, Index: 0, ID: "user2", Err: errors.New("id belongs to an entity in the trash")}},
+			},
+			wantErr: ErrInvalidData,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := seededStore(t)
+			if tt.setup != nil {
+				tt.setup(t, store)
+			}
+			importer := NewImporter(store, store)
+
+			report, err := importer.Import(ctx, tt.data, tt.opts)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			require.NotNil(t, report)
+			assert.Equal(t, tt.wantReport, *report)
+			if tt.check != nil {
+				tt.check(t, store)
+			}
+		})
+	}
+}
+
+func TestImporter_ImportRejectsUnknownStrategy(t *testing.T) {
+	store := inmemory.NewStore()
+	_, err := NewImporter(store, store).Import(context.Background(), &Dataset{}, ImportOptions{Duplicates: "merge"})
+	assert.Error(t, err)
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := seededStore(t)
+	data, err := NewExporter(source, source).Export(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, data.Users, 1)
+	require.Len(t, data.Todos, 1)
+
+	target := inmemory.NewStore()
+	report, err := NewImporter(target, target).Import(ctx, data, ImportOptions{Duplicates: DuplicateFail})
+	require.NoError(t, err)
+	assert.Equal(t, ImportCounts{Created: 1}, report.Users)
+	assert.Equal(t, ImportCounts{Created: 1}, report.Todos)
+
+	again, err := NewExporter(target, target).Export(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	_, err = NewExporter(target, target).Export(ctx, "user9")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
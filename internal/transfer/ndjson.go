@@ -0,0 +1,64 @@
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TakumaKurosawa/big-interface-vs-small-interface/internal/domain"
+)
+
+// maxNDJSONLine is the longest line Decode accepts
+const maxNDJSONLine = 1 << 20
+
+// ndjsonRecord is a single line of the NDJSON format
+type ndjsonRecord struct {
+	Type string       `json:"type"`
+	User *domain.User `json:"user,omitempty"`
+	Todo *domain.Todo `json:"todo,omitempty"`
+}
+
+func encodeNDJSON(w io.Writer, data *Dataset) error {
+	enc := json.NewEncoder(w)
+	for _, user := range data.Users {
+		if err := enc.Encode(ndjsonRecord{Type: recordUser, User: user}); err != nil {
+			return err
+		}
+	}
+	for _, todo := range data.Todos {
+		if err := enc.Encode(ndjsonRecord{Type: recordTodo, Todo: todo}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeNDJSON(r io.Reader) (*Dataset, error) {
+	data := &Dataset{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var record ndjsonRecord
+		if err := unmarshalStrict([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		switch {
+		case record.Type == recordUser && record.User != nil && record.Todo == nil:
+			data.Users = append(data.Users, record.User)
+		case record.Type == recordTodo && record.Todo != nil && record.User == nil:
+			data.Todos = append(data.Todos, record.Todo)
+		default:
+			return nil, fmt.Errorf("line %d: want a %q record with a user or a %q record with a todo", line, recordUser, recordTodo)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ndjson: %w", err)
+	}
+	return data, nil
+}